@@ -0,0 +1,289 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ExternalSpec defines the desired state of External.
+type ExternalSpec struct {
+	// Gateway is the name of the gateway (in the same namespace) that provides the handoff to the external
+	Gateway string `json:"gateway,omitempty"`
+	// Interface is the name of the gateway interface used for the handoff, it shouldn't be a fabric interface
+	// unless a VLAN is used
+	Interface string `json:"interface,omitempty"`
+	// VLAN is the VLAN used for the handoff on the interface, untagged if not set
+	VLAN uint16 `json:"vlan,omitempty"`
+	// IPs is the list of IP addresses to assign to the handoff interface
+	IPs []string `json:"ips,omitempty"`
+	// MTU for the handoff interface
+	MTU uint32 `json:"mtu,omitempty"`
+	// ASN is the local ASN used for the BGP sessions with the external, gateway ASN is used if not set
+	ASN uint32 `json:"asn,omitempty"`
+	// Neighbors is a list of eBGP neighbors of the external
+	Neighbors []ExternalBGPNeighbor `json:"neighbors,omitempty"`
+	// StaticRoutes is a list of static routes to use instead of BGP neighbors for the upstreams that can't run BGP.
+	// It's rejected for now as the dataplane doesn't support static routes yet.
+	StaticRoutes []ExternalStaticRoute `json:"staticRoutes,omitempty"`
+	// Import is a list of prefix filters for the routes received from the external, cidr entries permit and not
+	// entries deny matching routes, all routes are accepted if empty. It's rejected for now as the dataplane doesn't
+	// support prefix lists yet, use the not entries of the peering exposes of the external to filter the routes.
+	Import []ExternalPrefixFilter `json:"import,omitempty"`
+	// Export is a list of prefix filters for the routes advertised to the external, cidr entries permit and not
+	// entries deny matching routes, all routes from the peerings are advertised if empty. It's rejected for now as
+	// the dataplane doesn't support prefix lists yet.
+	Export []ExternalPrefixFilter `json:"export,omitempty"`
+}
+
+// ExternalBGPNeighbor defines the configuration for an external BGP neighbor
+type ExternalBGPNeighbor struct {
+	// IP is the IP address of the BGP neighbor
+	IP string `json:"ip,omitempty"`
+	// ASN is the remote ASN of the BGP neighbor
+	ASN uint32 `json:"asn,omitempty"`
+}
+
+// ExternalStaticRoute defines the prefixes reachable behind a static next hop of the external
+type ExternalStaticRoute struct {
+	// NextHop is the IP address of the next hop, it should be in the subnet of one of the handoff interface IPs
+	NextHop string `json:"nextHop,omitempty"`
+	// Prefixes is a list of prefixes reachable via the next hop
+	Prefixes []string `json:"prefixes,omitempty"`
+	// Monitor enables reachability monitoring of the next hop so routes are withdrawn while it's down
+	Monitor *ExternalNextHopMonitor `json:"monitor,omitempty"`
+}
+
+// ExternalNextHopMonitor defines the reachability monitoring of a static next hop
+type ExternalNextHopMonitor struct {
+	// IntervalSeconds is the interval between the probes, 1s by default
+	IntervalSeconds uint32 `json:"intervalSeconds,omitempty"`
+	// Failures is the number of consecutive failed probes after which the next hop is considered down, 3 by default
+	Failures uint32 `json:"failures,omitempty"`
+}
+
+// ExternalPrefixFilter defines a prefix filter entry for the routes exchanged with the external
+type ExternalPrefixFilter struct {
+	CIDR string `json:"cidr,omitempty"`
+	Not  string `json:"not,omitempty"`
+	// GE is the minimum prefix length of the matched routes
+	GE uint8 `json:"ge,omitempty"`
+	// LE is the maximum prefix length of the matched routes
+	LE uint8 `json:"le,omitempty"`
+}
+
+// ExternalStatus defines the observed state of External.
+type ExternalStatus struct {
+	// InternalID is allocated from the same space as the VPC IDs as externals are modeled as VPCs by the dataplane
+	InternalID string `json:"internalID,omitempty"`
+	// Error is set if the external isn't configured on the gateway as it uses a feature the dataplane doesn't
+	// support yet, the peerings with it aren't configured either
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=ext
+// +kubebuilder:printcolumn:name="Gateway",type=string,JSONPath=`.spec.gateway`,priority=0
+// +kubebuilder:printcolumn:name="Interface",type=string,JSONPath=`.spec.interface`,priority=0
+// +kubebuilder:printcolumn:name="VLAN",type=string,JSONPath=`.spec.vlan`,priority=0
+// +kubebuilder:printcolumn:name="InternalID",type=string,JSONPath=`.status.internalID`,priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// External is the Schema for the externals API. It represents an external connection that could be peered with
+// VPCs using Peering objects just like a VPC.
+type External struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ExternalSpec   `json:"spec,omitempty"`
+	Status ExternalStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExternalList contains a list of External.
+type ExternalList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []External `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&External{}, &ExternalList{})
+}
+
+func (ext *External) IsReady() bool {
+	return ext.Status.InternalID != ""
+}
+
+func (ext *External) Default() {
+	if ext.Labels == nil {
+		ext.Labels = map[string]string{}
+	}
+
+	if ext.Spec.Gateway != "" {
+		ext.Labels[LabelGateway] = ext.Spec.Gateway
+	}
+
+	for idx := range ext.Spec.StaticRoutes {
+		if mon := ext.Spec.StaticRoutes[idx].Monitor; mon != nil {
+			mon.IntervalSeconds = max(mon.IntervalSeconds, 1)
+			if mon.Failures == 0 {
+				mon.Failures = 3
+			}
+		}
+	}
+}
+
+// DataplaneUnsupported returns the reason the external can't be configured on the gateway yet or nil if it can, the
+// webhook rejects all of it but the externals accepted before are only skipped by the gateway controller together
+// with their peerings and reported in their status
+func (s *ExternalSpec) DataplaneUnsupported() error {
+	// there are no prefix lists in the dataplane API to filter the routes, so the external isn't configured at all
+	// instead of leaking the routes
+	if len(s.Import) > 0 || len(s.Export) > 0 {
+		return fmt.Errorf("import/export prefix filters aren't supported by the dataplane yet") //nolint:goerr113
+	}
+	// same for the static routes, the external would be unreachable without them
+	if len(s.StaticRoutes) > 0 {
+		return fmt.Errorf("static routes aren't supported by the dataplane yet") //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (ext *External) Validate(ctx context.Context, kube kclient.Reader) error {
+	if ext.Spec.Gateway == "" {
+		return fmt.Errorf("gateway must be set") //nolint:goerr113
+	}
+	if ext.Spec.Interface == "" {
+		return fmt.Errorf("interface must be set") //nolint:goerr113
+	}
+	if ext.Spec.VLAN > 4094 {
+		return fmt.Errorf("vlan %d must be in range 1-4094", ext.Spec.VLAN) //nolint:goerr113
+	}
+
+	if len(ext.Spec.IPs) == 0 {
+		return fmt.Errorf("at least one IP address must be defined") //nolint:goerr113
+	}
+	ifacePrefixes := []netip.Prefix{}
+	for _, extIP := range ext.Spec.IPs {
+		prefix, err := netip.ParsePrefix(extIP)
+		if err != nil {
+			return fmt.Errorf("invalid IP %s: %w", extIP, err)
+		}
+		if !prefix.Addr().Is4() {
+			return fmt.Errorf("IP %s must be an IPv4 address", extIP) //nolint:goerr113
+		}
+		ifacePrefixes = append(ifacePrefixes, prefix)
+	}
+
+	if len(ext.Spec.Neighbors) == 0 && len(ext.Spec.StaticRoutes) == 0 {
+		return fmt.Errorf("at least one BGP neighbor or static route must be defined") //nolint:goerr113
+	}
+	if len(ext.Spec.Neighbors) > 0 && len(ext.Spec.StaticRoutes) > 0 {
+		return fmt.Errorf("BGP neighbors and static routes can't be used together") //nolint:goerr113
+	}
+	nextHops := map[netip.Addr]bool{}
+	for _, route := range ext.Spec.StaticRoutes {
+		nextHop, err := netip.ParseAddr(route.NextHop)
+		if err != nil {
+			return fmt.Errorf("invalid static route next hop %s: %w", route.NextHop, err)
+		}
+		if nextHops[nextHop] {
+			return fmt.Errorf("duplicate static route next hop %s", route.NextHop) //nolint:goerr113
+		}
+		nextHops[nextHop] = true
+
+		onLink := false
+		for _, prefix := range ifacePrefixes {
+			if prefix.Contains(nextHop) && prefix.Addr() != nextHop {
+				onLink = true
+
+				break
+			}
+		}
+		if !onLink {
+			return fmt.Errorf("static route next hop %s isn't in the subnet of any interface IP", route.NextHop) //nolint:goerr113
+		}
+
+		if len(route.Prefixes) == 0 {
+			return fmt.Errorf("static route next hop %s must have at least one prefix", route.NextHop) //nolint:goerr113
+		}
+		for _, prefix := range route.Prefixes {
+			if _, err := netip.ParsePrefix(prefix); err != nil {
+				return fmt.Errorf("invalid static route prefix %s: %w", prefix, err)
+			}
+		}
+	}
+	for _, neigh := range ext.Spec.Neighbors {
+		neighIP, err := netip.ParseAddr(neigh.IP)
+		if err != nil {
+			return fmt.Errorf("invalid neighbor IP %s: %w", neigh.IP, err)
+		}
+		if !neighIP.Is4() {
+			return fmt.Errorf("BGP neighbor IP %s must be an IPv4 address", neigh.IP) //nolint:goerr113
+		}
+		if neigh.ASN == 0 {
+			return fmt.Errorf("BGP neighbor %s must have an ASN", neigh.IP) //nolint:goerr113
+		}
+		if ext.Spec.ASN != 0 && neigh.ASN == ext.Spec.ASN {
+			return fmt.Errorf("BGP neighbor %s ASN %d must be different from the local ASN for eBGP", neigh.IP, neigh.ASN) //nolint:goerr113
+		}
+	}
+
+	for _, filter := range append(append([]ExternalPrefixFilter{}, ext.Spec.Import...), ext.Spec.Export...) {
+		if filter.CIDR == "" && filter.Not == "" {
+			return fmt.Errorf("exactly one of cidr or not must be set in prefix filter") //nolint:goerr113
+		}
+
+		ip := PeeringEntryIP{CIDR: filter.CIDR, Not: filter.Not, GE: filter.GE, LE: filter.LE}
+		if err := ip.Validate(); err != nil {
+			return fmt.Errorf("invalid prefix filter: %w", err)
+		}
+	}
+	if err := ext.Spec.DataplaneUnsupported(); err != nil {
+		return err
+	}
+
+	if kube != nil {
+		gw := &Gateway{}
+		if err := kube.Get(ctx, kclient.ObjectKey{Namespace: ext.Namespace, Name: ext.Spec.Gateway}, gw); err != nil {
+			if !kapierrors.IsNotFound(err) {
+				return fmt.Errorf("getting gateway %s: %w", ext.Spec.Gateway, err)
+			}
+		} else {
+			if _, exists := gw.Spec.Interfaces[ext.Spec.Interface]; exists && ext.Spec.VLAN == 0 {
+				return fmt.Errorf("interface %s is used by the gateway for the fabric, vlan must be set to share it", ext.Spec.Interface) //nolint:goerr113
+			}
+
+			for _, neigh := range ext.Spec.Neighbors {
+				if ext.Spec.ASN == 0 && neigh.ASN == gw.Spec.ASN {
+					return fmt.Errorf("BGP neighbor %s ASN %d must be different from the gateway ASN for eBGP", neigh.IP, neigh.ASN) //nolint:goerr113
+				}
+			}
+		}
+
+		// the VPCs of all namespaces end up in the same gateway config as the externals keyed by name
+		vpcs := &VPCInfoList{}
+		if err := kube.List(ctx, vpcs); err != nil {
+			return fmt.Errorf("listing vpcs: %w", err)
+		}
+		for _, vpc := range vpcs.Items {
+			if vpc.Name == ext.Name {
+				return fmt.Errorf("vpc with the same name %s already exists in namespace %s", ext.Name, vpc.Namespace) //nolint:goerr113
+			}
+		}
+	}
+
+	return nil
+}
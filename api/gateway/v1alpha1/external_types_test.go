@@ -0,0 +1,182 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExternalValidate(t *testing.T) {
+	kube := kubetest.NewReader(
+		&Gateway{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "gw-1"},
+			Spec: GatewaySpec{
+				ASN:        65534,
+				Interfaces: map[string]GatewayInterface{"eth0": {}},
+			},
+		},
+		&VPCInfo{ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-a", Name: "vpc-1"}},
+	)
+	neighbors := []ExternalBGPNeighbor{{IP: "192.168.1.1", ASN: 65000}}
+
+	for _, tt := range []struct {
+		name string
+		ext  *External
+		err  string
+	}{
+		{
+			name: "bgp",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+			}},
+		},
+		{
+			name: "fabric-interface-vlan",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth0", VLAN: 100, IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+			}},
+		},
+		{
+			name: "fabric-interface-untagged",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth0", IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+			}},
+			err: "interface eth0 is used by the gateway for the fabric, vlan must be set to share it",
+		},
+		{
+			name: "vlan-out-of-range",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", VLAN: 4095, IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+			}},
+			err: "vlan 4095 must be in range 1-4094",
+		},
+		{
+			name: "ipv6",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"fd00::2/64"}, Neighbors: neighbors,
+			}},
+			err: "IP fd00::2/64 must be an IPv4 address",
+		},
+		{
+			name: "no-neighbors",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"},
+			}},
+			err: "at least one BGP neighbor or static route must be defined",
+		},
+		{
+			name: "neighbor-same-asn",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"},
+				Neighbors: []ExternalBGPNeighbor{{IP: "192.168.1.1", ASN: 65534}},
+			}},
+			err: "BGP neighbor 192.168.1.1 ASN 65534 must be different from the gateway ASN for eBGP",
+		},
+		{
+			name: "static-routes",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"},
+				StaticRoutes: []ExternalStaticRoute{{NextHop: "192.168.1.1", Prefixes: []string{"0.0.0.0/0"}, Monitor: &ExternalNextHopMonitor{}}},
+			}},
+			err: "static routes aren't supported by the dataplane yet",
+		},
+		{
+			name: "static-routes-with-neighbors",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+				StaticRoutes: []ExternalStaticRoute{{NextHop: "192.168.1.1", Prefixes: []string{"0.0.0.0/0"}}},
+			}},
+			err: "BGP neighbors and static routes can't be used together",
+		},
+		{
+			name: "static-route-off-link",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"},
+				StaticRoutes: []ExternalStaticRoute{{NextHop: "192.168.1.5", Prefixes: []string{"0.0.0.0/0"}}},
+			}},
+			err: "static route next hop 192.168.1.5 isn't in the subnet of any interface IP",
+		},
+		{
+			name: "static-route-own-ip",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"},
+				StaticRoutes: []ExternalStaticRoute{{NextHop: "192.168.1.2", Prefixes: []string{"0.0.0.0/0"}}},
+			}},
+			err: "static route next hop 192.168.1.2 isn't in the subnet of any interface IP",
+		},
+		{
+			name: "static-route-duplicate",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/29"},
+				StaticRoutes: []ExternalStaticRoute{
+					{NextHop: "192.168.1.1", Prefixes: []string{"10.0.0.0/8"}},
+					{NextHop: "192.168.1.1", Prefixes: []string{"172.16.0.0/12"}},
+				},
+			}},
+			err: "duplicate static route next hop 192.168.1.1",
+		},
+		{
+			name: "static-route-no-prefixes",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"},
+				StaticRoutes: []ExternalStaticRoute{{NextHop: "192.168.1.1"}},
+			}},
+			err: "static route next hop 192.168.1.1 must have at least one prefix",
+		},
+		{
+			name: "import",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+				Import: []ExternalPrefixFilter{{CIDR: "0.0.0.0/0", LE: 24}},
+			}},
+			err: "import/export prefix filters aren't supported by the dataplane yet",
+		},
+		{
+			name: "export",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+				Export: []ExternalPrefixFilter{{Not: "10.0.0.0/8"}},
+			}},
+			err: "import/export prefix filters aren't supported by the dataplane yet",
+		},
+		{
+			name: "invalid-filter",
+			ext: &External{Spec: ExternalSpec{
+				Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+				Export: []ExternalPrefixFilter{{GE: 8}},
+			}},
+			err: "exactly one of cidr or not must be set in prefix filter",
+		},
+		{
+			name: "vpc-name",
+			ext: &External{
+				ObjectMeta: kmetav1.ObjectMeta{Name: "vpc-1"},
+				Spec: ExternalSpec{
+					Gateway: "gw-1", Interface: "eth1", IPs: []string{"192.168.1.2/30"}, Neighbors: neighbors,
+				},
+			},
+			err: "vpc with the same name vpc-1 already exists in namespace tenant-a",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ext.Namespace = "default"
+			if tt.ext.Name == "" {
+				tt.ext.Name = "ext-1"
+			}
+			tt.ext.Default()
+
+			err := tt.ext.Validate(context.Background(), kube)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -34,6 +34,9 @@ type GatewaySpec struct {
 	Neighbors []GatewayBGPNeighbor `json:"neighbors,omitempty"`
 	// Alloy is the Alloy configuration for the gateway
 	Alloy AlloyConfig `json:"alloy,omitempty"`
+	// FlowExport is the flow export (IPFIX/NetFlow) configuration for the gateway, the flows are only exported for
+	// the peerings opting in, disabled if not set. It's rejected until flow export is supported by the dataplane.
+	FlowExport *GatewayFlowExport `json:"flowExport,omitempty"`
 }
 
 // GatewayInterface defines the configuration for a gateway interface
@@ -54,6 +57,28 @@ type GatewayBGPNeighbor struct {
 	ASN uint32 `json:"asn,omitempty"`
 }
 
+// FlowExportProtocol is the protocol used to export the flow records
+type FlowExportProtocol string
+
+const (
+	FlowExportProtocolIPFIX     FlowExportProtocol = "IPFIX"
+	FlowExportProtocolNetFlowV9 FlowExportProtocol = "NetFlowV9"
+)
+
+// GatewayFlowExport defines how the flows crossing the gateway are exported
+type GatewayFlowExport struct {
+	// Collectors is the list of the flow collectors to export the flows to
+	Collectors []GatewayFlowCollector `json:"collectors,omitempty"`
+}
+
+// GatewayFlowCollector defines a flow collector
+type GatewayFlowCollector struct {
+	// Address is the ip:port of the collector
+	Address string `json:"address,omitempty"`
+	// Protocol is the export protocol: IPFIX or NetFlowV9, IPFIX by default
+	Protocol FlowExportProtocol `json:"protocol,omitempty"`
+}
+
 // GatewayStatus defines the observed state of Gateway.
 type GatewayStatus struct{}
 
@@ -83,6 +108,13 @@ func init() {
 }
 
 func (gw *Gateway) Default() {
+	if fe := gw.Spec.FlowExport; fe != nil {
+		for idx := range fe.Collectors {
+			if fe.Collectors[idx].Protocol == "" {
+				fe.Collectors[idx].Protocol = FlowExportProtocolIPFIX
+			}
+		}
+	}
 }
 
 func (gw *Gateway) Validate(_ context.Context, _ kclient.Reader) error {
@@ -157,9 +189,51 @@ func (gw *Gateway) Validate(_ context.Context, _ kclient.Reader) error {
 		}
 	}
 
+	if fe := gw.Spec.FlowExport; fe != nil {
+		if err := fe.Validate(); err != nil {
+			return fmt.Errorf("invalid flow export: %w", err)
+		}
+		if len(fe.Collectors) == 0 {
+			return fmt.Errorf("flow export requires at least one collector") //nolint:goerr113
+		}
+
+		return fe.DataplaneUnsupported()
+	}
+
+	return nil
+}
+
+func (fe *GatewayFlowExport) Validate() error {
+	addrs := map[string]bool{}
+	for _, collector := range fe.Collectors {
+		addr, err := netip.ParseAddrPort(collector.Address)
+		if err != nil {
+			return fmt.Errorf("invalid collector address %s: %w", collector.Address, err)
+		}
+		if !addr.Addr().Is4() || addr.Port() == 0 {
+			return fmt.Errorf("collector address %s must be an IPv4 address with a port", collector.Address) //nolint:goerr113
+		}
+		if addrs[addr.String()] {
+			return fmt.Errorf("duplicate collector %s", collector.Address) //nolint:goerr113
+		}
+		addrs[addr.String()] = true
+
+		switch collector.Protocol {
+		case FlowExportProtocolIPFIX, FlowExportProtocolNetFlowV9:
+		default:
+			return fmt.Errorf("invalid collector %s protocol %q, must be IPFIX or NetFlowV9", collector.Address, collector.Protocol) //nolint:goerr113
+		}
+	}
+
 	return nil
 }
 
+// DataplaneUnsupported returns the reason the flow export can't be configured on the gateways yet, it's only
+// stripped by the gateway controller for the gateways accepted before
+func (fe *GatewayFlowExport) DataplaneUnsupported() error {
+	return fmt.Errorf("flow export isn't supported by the dataplane yet") //nolint:goerr113
+}
+
 // TODO extract alloy related code into a separate repo to standardize Alloy configuration and config generation
 
 var alloyLabel = regexp.MustCompile(`^[a-z]([_a-z0-9]*[a-z0-9])?$`)
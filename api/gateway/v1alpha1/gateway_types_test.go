@@ -0,0 +1,54 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewayFlowExportValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		fe   GatewayFlowExport
+		err  bool
+	}{
+		{
+			name: "defaults",
+			fe:   GatewayFlowExport{Collectors: []GatewayFlowCollector{{Address: "10.9.0.10:4739"}}},
+		},
+		{
+			name: "netflow",
+			fe:   GatewayFlowExport{Collectors: []GatewayFlowCollector{{Address: "10.9.0.10:2055", Protocol: FlowExportProtocolNetFlowV9}}},
+		},
+		{
+			name: "no-port",
+			fe:   GatewayFlowExport{Collectors: []GatewayFlowCollector{{Address: "10.9.0.10"}}},
+			err:  true,
+		},
+		{
+			name: "duplicate",
+			fe:   GatewayFlowExport{Collectors: []GatewayFlowCollector{{Address: "10.9.0.10:4739"}, {Address: "10.9.0.10:4739"}}},
+			err:  true,
+		},
+		{
+			name: "otlp",
+			fe:   GatewayFlowExport{Collectors: []GatewayFlowCollector{{Address: "10.9.0.10:4317", Protocol: "OTLP"}}},
+			err:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := &Gateway{Spec: GatewaySpec{FlowExport: &tt.fe}}
+			gw.Default()
+
+			err := gw.Spec.FlowExport.Validate()
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
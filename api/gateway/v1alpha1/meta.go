@@ -4,8 +4,11 @@
 package v1alpha1
 
 var (
-	LabelPrefix    = "gateway.githedgehog.com/"
-	ListLabelValue = "true"
+	LabelPrefix        = "gateway.githedgehog.com/"
+	LabelGateway       = LabelPrefix + "gateway"
+	LabelPeeringPolicy = LabelPrefix + "peering-policy"
+	LabelInspectionVPC = LabelPrefix + "inspection-vpc"
+	ListLabelValue     = "true"
 )
 
 func ListLabelPrefix(listType string) string {
@@ -19,3 +22,7 @@ func ListLabel(listType, val string) string {
 func ListLabelVPC(vpcName string) string {
 	return ListLabel("vpc", vpcName)
 }
+
+func ListLabelNATPool(poolName string) string {
+	return ListLabel("natpool", poolName)
+}
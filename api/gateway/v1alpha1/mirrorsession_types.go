@@ -0,0 +1,223 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// MirrorSessionSpec defines the desired state of MirrorSession.
+type MirrorSessionSpec struct {
+	// Peering is the name of the peering (in the same namespace) to mirror the traffic of
+	Peering string `json:"peering,omitempty"`
+	// Filter limits the mirrored traffic, all traffic of the peering is mirrored if not set
+	Filter MirrorSessionFilter `json:"filter,omitempty"`
+	// Destination is the collector the mirrored traffic is sent to
+	Destination MirrorSessionDestination `json:"destination,omitempty"`
+}
+
+// MirrorSessionFilter defines which traffic of the peering is mirrored
+type MirrorSessionFilter struct {
+	// Prefixes is the list of CIDRs matched against the source or destination address, any address if not set
+	Prefixes []string `json:"prefixes,omitempty"`
+	// Protocol is the protocol to mirror: tcp, udp or icmp, any protocol if not set
+	Protocol string `json:"protocol,omitempty"`
+	// Ports is the list of source or destination ports to mirror, only for tcp and udp, any port if not set
+	Ports []uint16 `json:"ports,omitempty"`
+}
+
+// MirrorSessionEncapsulation is the encapsulation of the mirrored traffic
+type MirrorSessionEncapsulation string
+
+const (
+	MirrorSessionEncapsulationERSPAN MirrorSessionEncapsulation = "ERSPAN"
+	MirrorSessionEncapsulationVXLAN  MirrorSessionEncapsulation = "VXLAN"
+)
+
+const (
+	MirrorSessionProtocolICMP = "icmp"
+	// MirrorSessionMaxERSPANID is the max ERSPAN session ID (10 bits)
+	MirrorSessionMaxERSPANID = 1023
+	// MirrorSessionMaxVNI is the max VXLAN VNI (24 bits)
+	MirrorSessionMaxVNI = 1<<24 - 1
+)
+
+// MirrorSessionDestination defines the collector of the mirrored traffic
+type MirrorSessionDestination struct {
+	// VPC is the name of the monitoring VPC (in the same namespace) the collector is reachable in
+	VPC string `json:"vpc,omitempty"`
+	// CollectorIP is the address of the collector in the monitoring VPC
+	CollectorIP string `json:"collectorIP,omitempty"`
+	// Encapsulation is the encapsulation of the mirrored traffic: ERSPAN or VXLAN, ERSPAN by default
+	Encapsulation MirrorSessionEncapsulation `json:"encapsulation,omitempty"`
+	// SessionID is the ERSPAN session ID, only for ERSPAN
+	SessionID uint32 `json:"sessionID,omitempty"`
+	// VNI is the VXLAN network identifier of the mirrored traffic, only for VXLAN
+	VNI uint32 `json:"vni,omitempty"`
+}
+
+// MirrorSessionStatus defines the observed state of MirrorSession.
+type MirrorSessionStatus struct {
+	// Error is the human readable reason the session isn't active, if any
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=mirror
+// +kubebuilder:printcolumn:name="Peering",type=string,JSONPath=`.spec.peering`,priority=0
+// +kubebuilder:printcolumn:name="Collector",type=string,JSONPath=`.spec.destination.collectorIP`,priority=0
+// +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.error`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// MirrorSession is the Schema for the mirrorsessions API. It copies the (filtered) traffic of a peering to a
+// collector in a monitoring VPC using ERSPAN or VXLAN encapsulation.
+type MirrorSession struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MirrorSessionSpec   `json:"spec,omitempty"`
+	Status MirrorSessionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MirrorSessionList contains a list of MirrorSession.
+type MirrorSessionList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []MirrorSession `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MirrorSession{}, &MirrorSessionList{})
+}
+
+func (ms *MirrorSession) Default() {
+	if ms.Spec.Destination.Encapsulation == "" {
+		ms.Spec.Destination.Encapsulation = MirrorSessionEncapsulationERSPAN
+	}
+}
+
+func (ms *MirrorSession) Validate(ctx context.Context, kube kclient.Reader) error {
+	if err := ms.validate(ctx, kube); err != nil {
+		return err
+	}
+
+	return ms.Spec.DataplaneUnsupported()
+}
+
+// DataplaneUnsupported returns the reason the mirror session can't be configured on the gateways yet, the ones
+// accepted before are only reported in their status
+func (s *MirrorSessionSpec) DataplaneUnsupported() error {
+	return fmt.Errorf("mirror sessions aren't supported by the dataplane yet") //nolint:goerr113
+}
+
+func (ms *MirrorSession) validate(ctx context.Context, kube kclient.Reader) error {
+	if ms.Spec.Peering == "" {
+		return fmt.Errorf("peering must be set") //nolint:goerr113
+	}
+	if err := ms.Spec.Filter.Validate(); err != nil {
+		return fmt.Errorf("filter: %w", err)
+	}
+	if err := ms.Spec.Destination.Validate(); err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+
+	if kube == nil {
+		return nil
+	}
+
+	peering := &Peering{}
+	if err := kube.Get(ctx, kclient.ObjectKey{Namespace: ms.Namespace, Name: ms.Spec.Peering}, peering); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return fmt.Errorf("peering %s not found", ms.Spec.Peering) //nolint:goerr113
+		}
+
+		return fmt.Errorf("getting peering %s: %w", ms.Spec.Peering, err)
+	}
+
+	dst := ms.Spec.Destination
+	subnets, isExternal, err := GetPeeredSubnets(ctx, kube, ms.Namespace, dst.VPC)
+	if err != nil {
+		if kapierrors.IsNotFound(err) {
+			return fmt.Errorf("monitoring vpc %s not found", dst.VPC) //nolint:goerr113
+		}
+
+		return err
+	}
+	if isExternal {
+		return fmt.Errorf("monitoring vpc %s must be a vpc, not an external", dst.VPC) //nolint:goerr113
+	}
+
+	set, err := prefixset.Parse(SubnetCIDRs(subnets)...)
+	if err != nil {
+		return fmt.Errorf("parsing monitoring vpc %s subnets: %w", dst.VPC, err)
+	}
+	if !set.Contains(netip.MustParseAddr(dst.CollectorIP)) {
+		return fmt.Errorf("collector IP %s isn't in the subnets of monitoring vpc %s", dst.CollectorIP, dst.VPC) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (f *MirrorSessionFilter) Validate() error {
+	if _, err := prefixset.Parse(f.Prefixes...); err != nil {
+		return fmt.Errorf("invalid prefixes: %w", err)
+	}
+
+	switch f.Protocol {
+	case "", PortForwardProtocolTCP, PortForwardProtocolUDP, MirrorSessionProtocolICMP:
+	default:
+		return fmt.Errorf("invalid protocol %q, must be tcp, udp or icmp", f.Protocol) //nolint:goerr113
+	}
+
+	if len(f.Ports) > 0 && f.Protocol != PortForwardProtocolTCP && f.Protocol != PortForwardProtocolUDP {
+		return fmt.Errorf("ports are only supported for tcp and udp") //nolint:goerr113
+	}
+	if slices.Contains(f.Ports, 0) {
+		return fmt.Errorf("port must not be 0") //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (d *MirrorSessionDestination) Validate() error {
+	if d.VPC == "" {
+		return fmt.Errorf("monitoring vpc must be set") //nolint:goerr113
+	}
+	if _, err := netip.ParseAddr(d.CollectorIP); err != nil {
+		return fmt.Errorf("invalid collector IP %s: %w", d.CollectorIP, err)
+	}
+
+	switch d.Encapsulation {
+	case "", MirrorSessionEncapsulationERSPAN:
+		if d.VNI != 0 {
+			return fmt.Errorf("vni is only supported for VXLAN") //nolint:goerr113
+		}
+		if d.SessionID > MirrorSessionMaxERSPANID {
+			return fmt.Errorf("ERSPAN session ID must be at most %d", MirrorSessionMaxERSPANID) //nolint:goerr113
+		}
+	case MirrorSessionEncapsulationVXLAN:
+		if d.SessionID != 0 {
+			return fmt.Errorf("session ID is only supported for ERSPAN") //nolint:goerr113
+		}
+		if d.VNI == 0 || d.VNI > MirrorSessionMaxVNI {
+			return fmt.Errorf("VXLAN vni must be between 1 and %d", MirrorSessionMaxVNI) //nolint:goerr113
+		}
+	default:
+		return fmt.Errorf("invalid encapsulation %q, must be ERSPAN or VXLAN", d.Encapsulation) //nolint:goerr113
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorSessionValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		spec MirrorSessionSpec
+		err  bool
+	}{
+		{
+			name: "erspan",
+			spec: MirrorSessionSpec{
+				Peering:     "vpc-1--vpc-2",
+				Filter:      MirrorSessionFilter{Prefixes: []string{"10.1.1.0/24"}, Protocol: "tcp", Ports: []uint16{443}},
+				Destination: MirrorSessionDestination{VPC: "monitoring", CollectorIP: "10.9.0.10", SessionID: 42},
+			},
+		},
+		{
+			name: "vxlan",
+			spec: MirrorSessionSpec{
+				Peering:     "vpc-1--vpc-2",
+				Destination: MirrorSessionDestination{VPC: "monitoring", CollectorIP: "10.9.0.10", Encapsulation: MirrorSessionEncapsulationVXLAN, VNI: 1000},
+			},
+		},
+		{
+			name: "no-peering",
+			spec: MirrorSessionSpec{Destination: MirrorSessionDestination{VPC: "monitoring", CollectorIP: "10.9.0.10"}},
+			err:  true,
+		},
+		{
+			name: "invalid-prefix",
+			spec: MirrorSessionSpec{
+				Peering:     "vpc-1--vpc-2",
+				Filter:      MirrorSessionFilter{Prefixes: []string{"10.1.1.0"}},
+				Destination: MirrorSessionDestination{VPC: "monitoring", CollectorIP: "10.9.0.10"},
+			},
+			err: true,
+		},
+		{
+			name: "ports-icmp",
+			spec: MirrorSessionSpec{
+				Peering:     "vpc-1--vpc-2",
+				Filter:      MirrorSessionFilter{Protocol: "icmp", Ports: []uint16{443}},
+				Destination: MirrorSessionDestination{VPC: "monitoring", CollectorIP: "10.9.0.10"},
+			},
+			err: true,
+		},
+		{
+			name: "no-collector",
+			spec: MirrorSessionSpec{Peering: "vpc-1--vpc-2", Destination: MirrorSessionDestination{VPC: "monitoring"}},
+			err:  true,
+		},
+		{
+			name: "erspan-id-too-big",
+			spec: MirrorSessionSpec{
+				Peering:     "vpc-1--vpc-2",
+				Destination: MirrorSessionDestination{VPC: "monitoring", CollectorIP: "10.9.0.10", SessionID: 1024},
+			},
+			err: true,
+		},
+		{
+			name: "vxlan-no-vni",
+			spec: MirrorSessionSpec{
+				Peering:     "vpc-1--vpc-2",
+				Destination: MirrorSessionDestination{VPC: "monitoring", CollectorIP: "10.9.0.10", Encapsulation: MirrorSessionEncapsulationVXLAN},
+			},
+			err: true,
+		},
+		{
+			name: "erspan-vni",
+			spec: MirrorSessionSpec{
+				Peering:     "vpc-1--vpc-2",
+				Destination: MirrorSessionDestination{VPC: "monitoring", CollectorIP: "10.9.0.10", VNI: 1000},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &MirrorSession{Spec: tt.spec}
+			ms.Default()
+
+			err := ms.validate(t.Context(), nil)
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.EqualError(t, ms.Validate(t.Context(), nil), "mirror sessions aren't supported by the dataplane yet")
+			}
+		})
+	}
+}
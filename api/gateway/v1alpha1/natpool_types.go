@@ -0,0 +1,221 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"time"
+
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// DefaultNATPoolReleaseCooldown is the time a released allocation is kept before its addresses could be reused
+const DefaultNATPoolReleaseCooldown = time.Hour
+
+// NATPoolSpec defines the desired state of NATPool.
+type NATPoolSpec struct {
+	// CIDRs is the list of the prefixes the "as" pools of the peerings are allocated from
+	CIDRs []string `json:"cidrs,omitempty"`
+	// ReleaseCooldown is the time the addresses of a released allocation aren't reused for, 1h by default
+	ReleaseCooldown *kmetav1.Duration `json:"releaseCooldown,omitempty"`
+}
+
+// NATPoolStatus defines the observed state of NATPool.
+type NATPoolStatus struct {
+	// Allocations is the list of the allocated prefixes keyed by the peering, the VPC and the ips of the expose
+	// (see NATPoolAllocationKey)
+	Allocations map[string]NATPoolAllocation `json:"allocations,omitempty"`
+	// Released is the list of the prefixes released by the deleted peerings (or exposes) and kept until the cooldown
+	// is over
+	Released []NATPoolAllocation `json:"released,omitempty"`
+	// Allocated is the number of the active allocations
+	Allocated int `json:"allocated,omitempty"`
+	// Free is the number of the addresses in the pool that aren't allocated, cooling down or used by the VPC subnets
+	// and the "as" pools listed in the peerings
+	Free string `json:"free,omitempty"`
+	// Error is the human readable reason some of the requests couldn't be allocated, if any
+	Error string `json:"error,omitempty"`
+}
+
+// NATPoolAllocation is a prefix allocated from the pool
+type NATPoolAllocation struct {
+	// CIDR is the allocated prefix
+	CIDR string `json:"cidr,omitempty"`
+	// Peering is the name of the peering the prefix is allocated for
+	Peering string `json:"peering,omitempty"`
+	// VPC is the name of the VPC whose expose uses the prefix as its "as" pool
+	VPC string `json:"vpc,omitempty"`
+	// IPs identifies the expose in the peering entry of the VPC by its ips (see PeeringEntryExpose.IPsKey), so the
+	// allocations stay with the exposes when they're reordered
+	IPs string `json:"ips,omitempty"`
+	// ReleasedAt is the time the allocation was released, only set for the released ones
+	ReleasedAt *kmetav1.Time `json:"releasedAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=natpool
+// +kubebuilder:printcolumn:name="CIDRs",type=string,JSONPath=`.spec.cidrs`,priority=0
+// +kubebuilder:printcolumn:name="Allocated",type=string,JSONPath=`.status.allocated`,priority=0
+// +kubebuilder:printcolumn:name="Free",type=string,JSONPath=`.status.free`,priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// NATPool is the Schema for the natpools API. It's the address space the "as" pools of the peering exposes asking
+// for a prefix of the specific size (see PeeringEntryASPool) are allocated from.
+type NATPool struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NATPoolSpec   `json:"spec,omitempty"`
+	Status NATPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NATPoolList contains a list of NATPool.
+type NATPoolList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []NATPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NATPool{}, &NATPoolList{})
+}
+
+func (p *NATPool) Default() {
+	if p.Spec.ReleaseCooldown == nil {
+		p.Spec.ReleaseCooldown = &kmetav1.Duration{Duration: DefaultNATPoolReleaseCooldown}
+	}
+}
+
+func (p *NATPool) Validate(ctx context.Context, kube kclient.Reader) error {
+	if len(p.Spec.CIDRs) == 0 {
+		return fmt.Errorf("at least one cidr must be set") //nolint:goerr113
+	}
+
+	set := &prefixset.Set{}
+	for _, cidr := range p.Spec.CIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid cidr %s: %w", cidr, err)
+		}
+		if !prefix.Addr().Is4() {
+			return fmt.Errorf("cidr %s must be an IPv4 prefix", cidr) //nolint:goerr113
+		}
+		if prefix.Masked() != prefix {
+			return fmt.Errorf("cidr %s has host bits set", cidr) //nolint:goerr113
+		}
+
+		next := prefixset.New(prefix)
+		if overlap := set.Intersect(next); !overlap.IsEmpty() {
+			return fmt.Errorf("cidr %s overlaps with the other cidrs: %s", cidr, overlap) //nolint:goerr113
+		}
+		set = set.Union(next)
+	}
+
+	if p.Spec.ReleaseCooldown != nil && p.Spec.ReleaseCooldown.Duration < 0 {
+		return fmt.Errorf("release cooldown must not be negative") //nolint:goerr113
+	}
+
+	if kube == nil {
+		return nil
+	}
+
+	pools := &NATPoolList{}
+	if err := kube.List(ctx, pools, kclient.InNamespace(p.Namespace)); err != nil {
+		return fmt.Errorf("listing nat pools: %w", err)
+	}
+	for _, other := range pools.Items {
+		if other.Name == p.Name {
+			continue
+		}
+		if overlap := set.Intersect(other.Set()); !overlap.IsEmpty() {
+			return fmt.Errorf("cidrs overlap with nat pool %s: %s", other.Name, overlap) //nolint:goerr113
+		}
+	}
+
+	vpcs := &VPCInfoList{}
+	if err := kube.List(ctx, vpcs, kclient.InNamespace(p.Namespace)); err != nil {
+		return fmt.Errorf("listing vpcs: %w", err)
+	}
+	for _, vpc := range vpcs.Items {
+		for _, subnetName := range slices.Sorted(maps.Keys(vpc.Spec.Subnets)) {
+			subnet := vpc.Spec.Subnets[subnetName]
+			if subnet == nil {
+				continue
+			}
+			subnetSet, err := prefixset.Parse(subnet.CIDR)
+			if err != nil {
+				continue
+			}
+			if overlap := set.Intersect(subnetSet); !overlap.IsEmpty() {
+				return fmt.Errorf("cidrs overlap with vpc %s subnet %s: %s", vpc.Name, subnetName, overlap) //nolint:goerr113
+			}
+		}
+	}
+
+	return nil
+}
+
+// Set returns the addresses of the pool, the cidrs are expected to be validated already
+func (p *NATPool) Set() *prefixset.Set {
+	set := &prefixset.Set{}
+	for _, cidr := range p.Spec.CIDRs {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil {
+			set = set.Union(prefixset.New(prefix.Masked()))
+		}
+	}
+
+	return set
+}
+
+// ReleaseCooldownDuration returns the release cooldown or the default one if it isn't set
+func (p *NATPool) ReleaseCooldownDuration() time.Duration {
+	if p.Spec.ReleaseCooldown == nil {
+		return DefaultNATPoolReleaseCooldown
+	}
+
+	return p.Spec.ReleaseCooldown.Duration
+}
+
+// CheckAllocations returns an error if any of the active or the cooling down allocations isn't in the pool anymore
+func (p *NATPool) CheckAllocations(status NATPoolStatus) error {
+	set := p.Set()
+	for _, alloc := range append(slices.Collect(maps.Values(status.Allocations)), status.Released...) {
+		prefix, err := netip.ParsePrefix(alloc.CIDR)
+		if err != nil {
+			continue
+		}
+		if !set.ContainsPrefix(prefix) {
+			return fmt.Errorf("cidr %s allocated for peering %s vpc %s isn't in the pool anymore", alloc.CIDR, alloc.Peering, alloc.VPC) //nolint:goerr113
+		}
+	}
+
+	return nil
+}
+
+// Allocation returns the prefix allocated for the key (see NATPoolAllocationKey) if there is an active allocation
+func (p *NATPool) Allocation(key string) (string, bool) {
+	alloc, ok := p.Status.Allocations[key]
+	if !ok || alloc.ReleasedAt != nil {
+		return "", false
+	}
+
+	return alloc.CIDR, true
+}
+
+// NATPoolAllocationKey returns the key of the allocation for the expose of the VPC in the peering, the expose is
+// identified by its ips (see PeeringEntryExpose.IPsKey) and not by its index so reordering the exposes doesn't move
+// the allocations between them
+func NATPoolAllocationKey(peering, vpc, ips string) string {
+	return fmt.Sprintf("%s/%s/%s", peering, vpc, ips)
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNATPoolValidate(t *testing.T) {
+	kube := kubetest.NewReader(
+		&NATPool{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "pool-1"},
+			Spec:       NATPoolSpec{CIDRs: []string{"192.168.0.0/24"}},
+		},
+		&NATPool{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "other", Name: "pool-2"},
+			Spec:       NATPoolSpec{CIDRs: []string{"192.168.1.0/24"}},
+		},
+		&VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-1"},
+			Spec:       VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.1.0.0/24"}}},
+		},
+	)
+
+	for _, tt := range []struct {
+		name  string
+		pool  string
+		cidrs []string
+		err   string
+	}{
+		{"valid", "pool-2", []string{"192.168.1.0/24"}, ""},
+		{"update", "pool-1", []string{"192.168.0.0/23"}, ""},
+		{"empty", "pool-2", nil, "at least one cidr must be set"},
+		{"overlapping-cidrs", "pool-2", []string{"192.168.2.0/24", "192.168.2.128/25"}, "cidr 192.168.2.128/25 overlaps with the other cidrs: [192.168.2.128/25]"},
+		{"overlapping-pool", "pool-2", []string{"192.168.0.128/25"}, "cidrs overlap with nat pool pool-1: [192.168.0.128/25]"},
+		{"overlapping-vpc", "pool-2", []string{"10.0.0.0/8"}, "cidrs overlap with vpc vpc-1 subnet subnet-1: [10.1.0.0/24]"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &NATPool{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: tt.pool},
+				Spec:       NATPoolSpec{CIDRs: tt.cidrs},
+			}
+			err := pool.Validate(t.Context(), kube)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -5,10 +5,18 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"maps"
+	"math/big"
+	"net/netip"
 	"slices"
+	"strings"
+	"time"
 
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -19,24 +27,331 @@ import (
 type PeeringSpec struct {
 	// Peerings is a map of peering entries for each VPC participating in the peering (keyed by VPC name)
 	Peering map[string]*PeeringEntry `json:"peering,omitempty"`
+	// ActiveFrom is the time the peering becomes active, it's active right away if not set
+	ActiveFrom *kmetav1.Time `json:"activeFrom,omitempty"`
+	// ExpiresAt is the time the peering stops being active, it never expires if not set
+	ExpiresAt *kmetav1.Time `json:"expiresAt,omitempty"`
+	// DeleteAfterExpiry is the grace period after which the expired peering is deleted, it's kept if not set
+	DeleteAfterExpiry *kmetav1.Duration `json:"deleteAfterExpiry,omitempty"`
+	// Advertisement is the policy of the routes advertised into the VRFs of the peered VPCs, the routes advertised to
+	// the externals are always enumerated. It's only reported in the status as the intended mode for now, the routes
+	// are always enumerated until the dataplane supports the other modes.
+	Advertisement *PeeringAdvertisement `json:"advertisement,omitempty"`
+	// ServiceChain redirects all traffic between the peered VPCs through an appliance (e.g. a firewall) in the
+	// inspection VPC instead of forwarding it directly, it's rejected until it's supported by the dataplane
+	ServiceChain *PeeringServiceChain `json:"serviceChain,omitempty"`
+	// FlowExport enables exporting the flows crossing the peering to the flow collectors configured on the gateways,
+	// it's rejected until flow export is supported by the dataplane
+	FlowExport bool `json:"flowExport,omitempty"`
+}
+
+// PeeringServiceChain defines the appliance the traffic of the peering is steered through
+type PeeringServiceChain struct {
+	// VPC is the name of the inspection VPC (in the same namespace as the peering) the appliance is running in
+	VPC string `json:"vpc,omitempty"`
+	// IngressIP is the address of the appliance the traffic is sent to for inspection
+	IngressIP string `json:"ingressIP,omitempty"`
+	// EgressIP is the address of the appliance the inspected traffic is coming back from
+	EgressIP string `json:"egressIP,omitempty"`
+}
+
+// PeeringAdvertisementMode defines which routes are advertised into the VRF of a peered VPC
+type PeeringAdvertisementMode string
+
+const (
+	// PeeringAdvertisementModeFull enumerates all of the prefixes exposed by (or accepted from) the other side
+	PeeringAdvertisementModeFull PeeringAdvertisementMode = "Full"
+	// PeeringAdvertisementModeAggregate only advertises the cidr entries of the exposes of the other side
+	PeeringAdvertisementModeAggregate PeeringAdvertisementMode = "Aggregate"
+	// PeeringAdvertisementModeDefault only advertises the default route
+	PeeringAdvertisementModeDefault PeeringAdvertisementMode = "Default"
+	// PeeringAdvertisementModeAuto enumerates the prefixes up to the threshold and falls back to the default route
+	PeeringAdvertisementModeAuto PeeringAdvertisementMode = "Auto"
+)
+
+// DefaultPeeringAdvertisementThreshold is the route count threshold of the auto advertisement mode if not set
+const DefaultPeeringAdvertisementThreshold = 64
+
+// PeeringAdvertisement defines the routes advertised into the VRFs of the peered VPCs
+type PeeringAdvertisement struct {
+	// Mode is the advertisement mode: Full, Aggregate, Default or Auto, Full by default
+	Mode PeeringAdvertisementMode `json:"mode,omitempty"`
+	// Threshold is the max number of enumerated prefixes in the Auto mode, the default route is advertised instead if
+	// there are more of them (or the accepted routes of an external aren't limited)
+	Threshold uint32 `json:"threshold,omitempty"`
+}
+
+// EffectiveAdvertisementMode returns the advertisement mode in effect for the VRF of a VPC given the number of
+// prefixes the peering would enumerate into it, unbounded means the routes are learned from an external without
+// limiting their range, so their number isn't known upfront
+func (s *PeeringSpec) EffectiveAdvertisementMode(routeCount int, unbounded bool) PeeringAdvertisementMode {
+	if s.Advertisement == nil || s.Advertisement.Mode == "" {
+		return PeeringAdvertisementModeFull
+	}
+	if s.Advertisement.Mode != PeeringAdvertisementModeAuto {
+		return s.Advertisement.Mode
+	}
+
+	threshold := s.Advertisement.Threshold
+	if threshold == 0 {
+		threshold = DefaultPeeringAdvertisementThreshold
+	}
+	if unbounded || routeCount > int(threshold) {
+		return PeeringAdvertisementModeDefault
+	}
+
+	return PeeringAdvertisementModeFull
+}
+
+// PeeringWindowState is the state of the peering relative to its activity window
+type PeeringWindowState string
+
+const (
+	// PeeringWindowStateUpcoming means the peering isn't active yet
+	PeeringWindowStateUpcoming PeeringWindowState = "Upcoming"
+	// PeeringWindowStateActive means the peering is active
+	PeeringWindowStateActive PeeringWindowState = "Active"
+	// PeeringWindowStateExpired means the peering isn't active anymore
+	PeeringWindowStateExpired PeeringWindowState = "Expired"
+)
+
+// WindowState returns the state of the peering at the given time and the time of the next window boundary (zero if
+// there is none)
+func (s *PeeringSpec) WindowState(now time.Time) (PeeringWindowState, time.Time) {
+	if s.ActiveFrom != nil && now.Before(s.ActiveFrom.Time) {
+		return PeeringWindowStateUpcoming, s.ActiveFrom.Time
+	}
+	if s.ExpiresAt != nil {
+		if now.Before(s.ExpiresAt.Time) {
+			return PeeringWindowStateActive, s.ExpiresAt.Time
+		}
+
+		return PeeringWindowStateExpired, time.Time{}
+	}
+
+	return PeeringWindowStateActive, time.Time{}
 }
 
 type PeeringEntryExpose struct {
 	IPs []PeeringEntryIP `json:"ips,omitempty"`
 	As  []PeeringEntryAs `json:"as,omitempty"`
+	// ASPool asks for the "as" pool to be allocated from a NAT pool instead of listing it in "as", the allocated
+	// prefix is reported in the peering status and kept until the peering (or the expose) is deleted
+	ASPool *PeeringEntryASPool `json:"asPool,omitempty"`
+	// Translation exposes the ips to the other side in the other address family instead of using the "as" pool,
+	// e.g. the IPv4 addresses of a legacy VPC to an IPv6-only one. It's rejected until NAT64/NAT46 is supported by
+	// the dataplane.
+	Translation *PeeringEntryTranslation `json:"translation,omitempty"`
+	// Metric is the metric of the routes advertised for this expose into the VRF of the other VPC, so the lower metric
+	// would win if multiple peerings provide routes for the same prefixes. It's rejected if set as the dataplane
+	// doesn't support route metrics yet.
+	Metric uint32 `json:"metric,omitempty"`
+	// PortForwards is a list of ports forwarded to the specific services inside the VPC, external addresses have to
+	// be in the "as" pool (or in the "ips" if there is no NAT) and internal ones in the "ips" of the expose. It's
+	// rejected for now as the dataplane doesn't support port forwarding yet.
+	PortForwards []PeeringEntryPortForward `json:"portForwards,omitempty"`
+}
+
+// PeeringEntryASPool defines the "as" pool allocated from a NAT pool
+type PeeringEntryASPool struct {
+	// Pool is the name of the NAT pool (in the same namespace as the peering) to allocate from
+	Pool string `json:"pool,omitempty"`
+	// PrefixLen is the length of the allocated prefix, it has to hold as many addresses as the ips expose
+	PrefixLen uint8 `json:"prefixLen,omitempty"`
+}
+
+// PeeringTranslationMode is the address family translation of an expose
+type PeeringTranslationMode string
+
+const (
+	// PeeringTranslationModeNAT64 exposes the IPv4 ips as the IPv6 addresses embedded into the prefix (RFC 6052)
+	PeeringTranslationModeNAT64 PeeringTranslationMode = "NAT64"
+	// PeeringTranslationModeNAT46 exposes the IPv6 ips as the IPv4 addresses using the explicit mappings
+	PeeringTranslationModeNAT46 PeeringTranslationMode = "NAT46"
+)
+
+// NAT64WellKnownPrefix is the well-known prefix for the IPv4 embedded IPv6 addresses (RFC 6052)
+const NAT64WellKnownPrefix = "64:ff9b::/96"
+
+var (
+	allIPv4 = prefixset.MustParse("0.0.0.0/0")
+	allIPv6 = prefixset.MustParse("::/0")
+)
+
+// PeeringEntryTranslation defines the stateless address family translation of an expose
+type PeeringEntryTranslation struct {
+	// Mode is the translation mode: NAT64 or NAT46
+	Mode PeeringTranslationMode `json:"mode,omitempty"`
+	// Prefix is the IPv6 prefix the IPv4 ips are embedded into for NAT64, the well-known 64:ff9b::/96 if not set,
+	// network-specific prefixes could be /32, /40, /48, /56, /64 or /96
+	Prefix string `json:"prefix,omitempty"`
+	// Mappings is the list of the explicit mappings of the IPv6 ips to the IPv4 addresses for NAT46, they have to
+	// cover all ips
+	Mappings []PeeringTranslationMapping `json:"mappings,omitempty"`
+}
+
+// PeeringTranslationMapping maps an IPv6 prefix to an IPv4 prefix of the same size
+type PeeringTranslationMapping struct {
+	// IPv6 is the IPv6 prefix of the VPC
+	IPv6 string `json:"ipv6,omitempty"`
+	// IPv4 is the IPv4 prefix it's exposed as
+	IPv4 string `json:"ipv4,omitempty"`
 }
 
+// PeeringEntryPortForward defines a port forwarded (destination NATed) to a service inside the VPC
+type PeeringEntryPortForward struct {
+	// Protocol is the protocol of the forwarded port: tcp or udp, tcp by default
+	Protocol string `json:"protocol,omitempty"`
+	// ExternalIP is the address the service is reachable on from the other VPC
+	ExternalIP string `json:"externalIP,omitempty"`
+	// ExternalPort is the port the service is reachable on from the other VPC
+	ExternalPort uint16 `json:"externalPort,omitempty"`
+	// InternalIP is the address of the service inside the VPC
+	InternalIP string `json:"internalIP,omitempty"`
+	// InternalPort is the port of the service inside the VPC, same as the external port if not set
+	InternalPort uint16 `json:"internalPort,omitempty"`
+}
+
+const (
+	PortForwardProtocolTCP = "tcp"
+	PortForwardProtocolUDP = "udp"
+)
+
+// PeeringEntry is the side of a peering for a VPC or external. A VPC with no exposes would be consume-only: nothing
+// from the other side could reach it and only the return traffic of the flows it starts would be allowed back. It's
+// rejected for now as the dataplane can't restrict the traffic to the return one yet.
 type PeeringEntry struct {
 	Expose []PeeringEntryExpose `json:"expose,omitempty"`
+	// Transit makes the VPC re-expose to the other side the prefixes it learns from its other peerings (incl. the
+	// meshes and the accepted requests), so it carries the transit traffic between them (e.g. hub-and-spoke with
+	// inspection in the hub). The peering isn't passed to the dataplane while the VPC has nothing to expose.
+	Transit bool `json:"transit,omitempty"`
+	// QoS limits and classifies the traffic of the VPC crossing the peering, not limited if not set. It's rejected
+	// until the policers are supported by the dataplane.
+	QoS *PeeringEntryQoS `json:"qos,omitempty"`
 	// Ingress []PeeringEntryIngress `json:"ingress,omitempty"`
 	// TODO add natType: stateful # as there are not enough IPs in the "as" pool
-	// TODO add metric: 0 # add 0 to the advertised route metrics
+}
+
+// ConsumeOnly returns true if the entry doesn't expose anything (neither its own prefixes nor the transit ones), so
+// the VPC can only start flows to the other side
+func (e *PeeringEntry) ConsumeOnly() bool {
+	return e == nil || len(e.Expose) == 0 && !e.Transit
+}
+
+// PeeringEntryQoS defines the QoS policies of the VPC for each direction of the traffic
+type PeeringEntryQoS struct {
+	// Egress is the policy of the traffic from the VPC to the other side
+	Egress *PeeringQoSPolicy `json:"egress,omitempty"`
+	// Ingress is the policy of the traffic from the other side to the VPC
+	Ingress *PeeringQoSPolicy `json:"ingress,omitempty"`
+}
+
+// PeeringQoSPriority is the priority class of the traffic
+type PeeringQoSPriority string
+
+const (
+	PeeringQoSPriorityLow    PeeringQoSPriority = "Low"
+	PeeringQoSPriorityNormal PeeringQoSPriority = "Normal"
+	PeeringQoSPriorityHigh   PeeringQoSPriority = "High"
+)
+
+const (
+	// PeeringQoSMinBandwidth is the min bandwidth limit in bits per second
+	PeeringQoSMinBandwidth = 64_000
+	// PeeringQoSMaxBandwidth is the max bandwidth limit in bits per second
+	PeeringQoSMaxBandwidth = 400_000_000_000
+	// PeeringQoSMaxPacketRate is the max packet rate limit in packets per second
+	PeeringQoSMaxPacketRate = 1_000_000_000
+	// PeeringQoSMaxDSCP is the max DSCP value (6 bits)
+	PeeringQoSMaxDSCP = 63
+)
+
+// PeeringQoSPolicy defines the policer and the marking of the traffic in one direction
+type PeeringQoSPolicy struct {
+	// Bandwidth is the max rate in bits per second with an optional decimal SI suffix, e.g. 500M or 10G, not limited
+	// if not set
+	Bandwidth string `json:"bandwidth,omitempty"`
+	// PacketRate is the max rate in packets per second, not limited if not set
+	PacketRate uint64 `json:"packetRate,omitempty"`
+	// DSCP is the value (0-63) the packets are remarked with, not remarked if not set
+	DSCP *uint8 `json:"dscp,omitempty"`
+	// Priority is the priority class of the traffic: Low, Normal or High, can't be combined with DSCP
+	Priority PeeringQoSPriority `json:"priority,omitempty"`
+}
+
+// BandwidthBits returns the bandwidth limit in bits per second, 0 if it isn't limited
+func (q *PeeringQoSPolicy) BandwidthBits() (uint64, error) {
+	if q.Bandwidth == "" {
+		return 0, nil
+	}
+
+	quantity, err := resource.ParseQuantity(q.Bandwidth)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bandwidth %s: %w", q.Bandwidth, err)
+	}
+	if quantity.Format != resource.DecimalSI {
+		return 0, fmt.Errorf("bandwidth %s must use a decimal SI suffix (k, M, G)", q.Bandwidth) //nolint:goerr113
+	}
+	bits, ok := quantity.AsInt64()
+	if !ok || bits < PeeringQoSMinBandwidth || bits > PeeringQoSMaxBandwidth {
+		return 0, fmt.Errorf("bandwidth %s must be a whole number of bits per second between %d and %d", //nolint:goerr113
+			q.Bandwidth, PeeringQoSMinBandwidth, PeeringQoSMaxBandwidth)
+	}
+
+	return uint64(bits), nil
+}
+
+func (q *PeeringQoSPolicy) Validate() error {
+	if _, err := q.BandwidthBits(); err != nil {
+		return err
+	}
+	if q.PacketRate > PeeringQoSMaxPacketRate {
+		return fmt.Errorf("packet rate must be at most %d", PeeringQoSMaxPacketRate) //nolint:goerr113
+	}
+	if q.DSCP != nil && *q.DSCP > PeeringQoSMaxDSCP {
+		return fmt.Errorf("dscp must be at most %d", PeeringQoSMaxDSCP) //nolint:goerr113
+	}
+
+	switch q.Priority {
+	case "":
+	case PeeringQoSPriorityLow, PeeringQoSPriorityNormal, PeeringQoSPriorityHigh:
+		if q.DSCP != nil {
+			return fmt.Errorf("dscp and priority are mutually exclusive") //nolint:goerr113
+		}
+	default:
+		return fmt.Errorf("invalid priority %q, must be Low, Normal or High", q.Priority) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (q *PeeringEntryQoS) Validate() error {
+	if q.Egress != nil {
+		if err := q.Egress.Validate(); err != nil {
+			return fmt.Errorf("egress: %w", err)
+		}
+	}
+	if q.Ingress != nil {
+		if err := q.Ingress.Validate(); err != nil {
+			return fmt.Errorf("ingress: %w", err)
+		}
+	}
+
+	return nil
 }
 
 type PeeringEntryIP struct {
 	CIDR      string `json:"cidr,omitempty"`
 	Not       string `json:"not,omitempty"`
 	VPCSubnet string `json:"vpcSubnet,omitempty"`
+	// GE is the minimum prefix length of the routes matched by the cidr or not entry of an external, e.g. to only
+	// accept more specific routes within 0.0.0.0/0 that are at least /8. It's rejected for now as the dataplane API
+	// has no prefix length ranges to build the route filter from.
+	GE uint8 `json:"ge,omitempty"`
+	// LE is the maximum prefix length of the routes matched by the cidr or not entry of an external, e.g. to reject
+	// routes more specific than /24. It's rejected for now same as GE.
+	LE uint8 `json:"le,omitempty"`
 }
 
 type PeeringEntryAs struct {
@@ -57,11 +372,134 @@ type PeeringEntryAs struct {
 // }
 
 // PeeringStatus defines the observed state of Peering.
-type PeeringStatus struct{}
+type PeeringStatus struct {
+	// State is the state of the peering relative to its activity window: Upcoming, Active or Expired
+	State PeeringWindowState `json:"state,omitempty"`
+	// ObservedGeneration is the generation of the peering the status is computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Error is set if the effective result of the peering can't be computed, e.g. if a VPC subnet is unknown, or if
+	// the gateways skip the peering as it uses a feature the dataplane doesn't support yet
+	Error string `json:"error,omitempty"`
+	// VPCs is the effective result of the peering for the VRF of each VPC or external (keyed by name)
+	VPCs map[string]PeeringVPCStatus `json:"vpcs,omitempty"`
+	// Traffic is the traffic crossing the peering summed over all gateways, not set until a gateway reports it
+	Traffic *PeeringTrafficStatus `json:"traffic,omitempty"`
+}
+
+// PeeringTrafficStatus is the traffic crossing the peering summed over all gateways, the counters are the totals
+// since the dataplanes started, so they drop if a dataplane restarts
+type PeeringTrafficStatus struct {
+	// UpdatedAt is the time the counters were last refreshed, they're refreshed at most once per the traffic
+	// resolution configured for the controller
+	UpdatedAt kmetav1.Time `json:"updatedAt,omitempty"`
+	// Gateways is the number of gateways reporting the counters
+	Gateways int `json:"gateways,omitempty"`
+	// VPCs is the traffic of each side of the peering keyed by the VPC or external name
+	VPCs map[string]PeeringVPCTrafficStatus `json:"vpcs,omitempty"`
+}
+
+// PeeringVPCTrafficStatus is the traffic of a side of the peering
+type PeeringVPCTrafficStatus struct {
+	// EgressBytes is the number of bytes from the VPC to the other side
+	EgressBytes uint64 `json:"egressBytes,omitempty"`
+	// EgressPackets is the number of packets from the VPC to the other side
+	EgressPackets uint64 `json:"egressPackets,omitempty"`
+	// IngressBytes is the number of bytes from the other side to the VPC
+	IngressBytes uint64 `json:"ingressBytes,omitempty"`
+	// IngressPackets is the number of packets from the other side to the VPC
+	IngressPackets uint64 `json:"ingressPackets,omitempty"`
+	// NATSessions is the number of active NAT sessions of the VPC
+	NATSessions uint64 `json:"natSessions,omitempty"`
+}
+
+// Add adds the counters of the other side status to the status
+func (t *PeeringVPCTrafficStatus) Add(other PeeringVPCTrafficStatus) {
+	t.EgressBytes += other.EgressBytes
+	t.EgressPackets += other.EgressPackets
+	t.IngressBytes += other.IngressBytes
+	t.IngressPackets += other.IngressPackets
+	t.NATSessions += other.NATSessions
+}
+
+// PeeringVPCStatus is the effective result of the peering for the VRF of a VPC or external
+type PeeringVPCStatus struct {
+	// Routes is the list of prefixes the gateway advertises into the VRF, for the routes learned from an external
+	// it's the range of the routes accepted from it
+	Routes []string `json:"routes,omitempty"`
+	// RouteCount is the total number of prefixes advertised into the VRF
+	RouteCount int `json:"routeCount,omitempty"`
+	// Summarized is true if there are too many prefixes to enumerate and Routes only lists the aggregates (cidr
+	// entries) they're computed from
+	Summarized bool `json:"summarized,omitempty"`
+	// SourceNAT is the list of static NAT mappings for the traffic from the VPC, applied before routing
+	SourceNAT []PeeringNATStatus `json:"sourceNAT,omitempty"`
+	// DestinationNAT is the list of static NAT mappings for the traffic to the other VPC, applied after routing
+	DestinationNAT []PeeringNATStatus `json:"destinationNAT,omitempty"`
+	// Advertisement is the intended advertisement mode for the VRF (the auto mode is resolved), empty for externals.
+	// It isn't applied by the dataplane yet, so the Routes are always enumerated.
+	Advertisement PeeringAdvertisementMode `json:"advertisement,omitempty"`
+	// Transit is the list of the routes re-exposed into the VRF by the transit VPC on the other side with the paths
+	// they're learned through
+	Transit []PeeringTransitPath `json:"transit,omitempty"`
+	// AllocatedAs is the list of the "as" pools allocated from the NAT pools for the exposes of the VPC
+	AllocatedAs []PeeringASAllocation `json:"allocatedAs,omitempty"`
+	// QoS is the configured QoS policies of the VPC with the packets dropped by the policers, if any
+	QoS *PeeringQoSStatus `json:"qos,omitempty"`
+}
+
+// PeeringQoSStatus is the QoS status of a VPC for each direction of the traffic
+type PeeringQoSStatus struct {
+	Egress  *PeeringQoSPolicyStatus `json:"egress,omitempty"`
+	Ingress *PeeringQoSPolicyStatus `json:"ingress,omitempty"`
+}
+
+// PeeringQoSPolicyStatus is the configured policy and the observed drops of a direction
+type PeeringQoSPolicyStatus struct {
+	PeeringQoSPolicy `json:",inline"`
+	// DroppedPackets is the number of packets dropped by the policer summed over all gateways
+	DroppedPackets uint64 `json:"droppedPackets,omitempty"`
+	// Gateways is the number of gateways reporting the drops of the policer, the drops aren't observed if it's 0
+	Gateways int `json:"gateways,omitempty"`
+}
+
+// PeeringASAllocation is an "as" pool allocated from a NAT pool for an expose
+type PeeringASAllocation struct {
+	// Expose is the index of the expose in the peering entry of the VPC
+	Expose int `json:"expose,omitempty"`
+	// Pool is the name of the NAT pool
+	Pool string `json:"pool,omitempty"`
+	// CIDR is the allocated prefix
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// PeeringTransitPath describes the routes re-exposed by a transit VPC
+type PeeringTransitPath struct {
+	// Path is the list of VPCs (or externals) the routes are learned through starting from the one exposing them and
+	// ending with the transit VPC of the peering
+	Path []string `json:"path,omitempty"`
+	// Routes is the list of prefixes re-exposed through the path
+	Routes []string `json:"routes,omitempty"`
+}
+
+// PeeringNATStatus describes a static NAT mapping
+type PeeringNATStatus struct {
+	// From is the list of prefixes translated
+	From []string `json:"from,omitempty"`
+	// To is the list of prefixes they're translated to
+	To []string `json:"to,omitempty"`
+	// ExcludedFrom is the list of prefixes excluded from From by the not entries
+	ExcludedFrom []string `json:"excludedFrom,omitempty"`
+	// ExcludedTo is the list of prefixes excluded from To by the not entries
+	ExcludedTo []string `json:"excludedTo,omitempty"`
+}
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=peer
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,priority=0
+// +kubebuilder:printcolumn:name="Advertisement",type=string,JSONPath=`.spec.advertisement.mode`,priority=1
+// +kubebuilder:printcolumn:name="FlowExport",type=boolean,JSONPath=`.spec.flowExport`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
 // Peering is the Schema for the peerings API.
 type Peering struct {
 	kmetav1.TypeMeta   `json:",inline"`
@@ -84,25 +522,1029 @@ func init() {
 	SchemeBuilder.Register(&Peering{}, &PeeringList{})
 }
 
+// PeeringVPCPairField is the name of the field index of the peerings by the unordered pair of the peered VPCs taken
+// from the VPC labels, it's used to make sure there is only a single peering between any 2 VPCs
+const PeeringVPCPairField = "vpcPair"
+
+// PeeringName returns the canonical name of the peering between the VPCs (sorted names joined with "--")
+func PeeringName(vpc1, vpc2 string) string {
+	return strings.Join(slices.Sorted(slices.Values([]string{vpc1, vpc2})), "--")
+}
+
+// LabeledVPCPair returns the canonical form of the pair of VPCs from the peering VPC labels, empty if there are not
+// exactly 2 of them
+func (p *Peering) LabeledVPCPair() string {
+	prefix := ListLabelPrefix("vpc")
+
+	vpcs := []string{}
+	for label, value := range p.Labels {
+		if vpcName, ok := strings.CutPrefix(label, prefix); ok && value == ListLabelValue {
+			vpcs = append(vpcs, vpcName)
+		}
+	}
+	if len(vpcs) != 2 {
+		return ""
+	}
+
+	return PeeringName(vpcs[0], vpcs[1])
+}
+
 func (p *Peering) Default() {
 	if p.Labels == nil {
 		p.Labels = map[string]string{}
 	}
 
+	if p.Spec.ServiceChain != nil && p.Spec.ServiceChain.VPC != "" {
+		p.Labels[LabelInspectionVPC] = p.Spec.ServiceChain.VPC
+	} else {
+		delete(p.Labels, LabelInspectionVPC)
+	}
+
 	vpcs := slices.Collect(maps.Keys(p.Spec.Peering))
 	if len(vpcs) != 2 {
 		return
 	}
 
+	// drop the labels of the VPCs that aren't peered anymore
+	prefix := ListLabelPrefix("vpc")
+	for label := range p.Labels {
+		if vpcName, ok := strings.CutPrefix(label, prefix); ok {
+			if _, exists := p.Spec.Peering[vpcName]; !exists {
+				delete(p.Labels, label)
+			}
+		}
+	}
+
 	p.Labels[ListLabelVPC(vpcs[0])] = ListLabelValue
 	p.Labels[ListLabelVPC(vpcs[1])] = ListLabelValue
+
+	// label the peering with the NAT pools its exposes are allocated from
+	pools := map[string]bool{}
+	for _, entry := range p.Spec.Peering {
+		if entry == nil {
+			continue
+		}
+		for _, expose := range entry.Expose {
+			if expose.ASPool != nil && expose.ASPool.Pool != "" {
+				pools[expose.ASPool.Pool] = true
+			}
+		}
+	}
+	poolPrefix := ListLabelPrefix("natpool")
+	for label := range p.Labels {
+		if poolName, ok := strings.CutPrefix(label, poolPrefix); ok && !pools[poolName] {
+			delete(p.Labels, label)
+		}
+	}
+	for poolName := range pools {
+		p.Labels[ListLabelNATPool(poolName)] = ListLabelValue
+	}
+
+	if p.Name == "" && p.GenerateName == "" {
+		p.Name = PeeringName(vpcs[0], vpcs[1])
+	}
 }
 
-func (p *Peering) Validate(_ context.Context, _ kclient.Reader) error {
+func (p *Peering) Validate(ctx context.Context, kube kclient.Reader) error {
 	vpcs := slices.Collect(maps.Keys(p.Spec.Peering))
 	if len(vpcs) != 2 {
 		return fmt.Errorf("peering must have exactly 2 VPCs, got %d", len(vpcs)) //nolint:goerr113
 	}
+	if p.Spec.Peering[vpcs[0]].ConsumeOnly() && p.Spec.Peering[vpcs[1]].ConsumeOnly() {
+		return fmt.Errorf("at least one of the VPCs must expose something") //nolint:goerr113
+	}
+
+	if p.Spec.ActiveFrom != nil && p.Spec.ExpiresAt != nil && !p.Spec.ExpiresAt.After(p.Spec.ActiveFrom.Time) {
+		return fmt.Errorf("expiresAt must be after activeFrom") //nolint:goerr113
+	}
+	if p.Spec.DeleteAfterExpiry != nil {
+		if p.Spec.ExpiresAt == nil {
+			return fmt.Errorf("deleteAfterExpiry requires expiresAt to be set") //nolint:goerr113
+		}
+		if p.Spec.DeleteAfterExpiry.Duration < 0 {
+			return fmt.Errorf("deleteAfterExpiry must not be negative") //nolint:goerr113
+		}
+	}
+	if adv := p.Spec.Advertisement; adv != nil {
+		switch adv.Mode {
+		case "", PeeringAdvertisementModeFull, PeeringAdvertisementModeAggregate, PeeringAdvertisementModeDefault, PeeringAdvertisementModeAuto:
+		default:
+			return fmt.Errorf("invalid advertisement mode %q", adv.Mode) //nolint:goerr113
+		}
+		if adv.Threshold > 0 && adv.Mode != PeeringAdvertisementModeAuto {
+			return fmt.Errorf("advertisement threshold is only supported in the Auto mode") //nolint:goerr113
+		}
+	}
+
+	if p.Spec.FlowExport {
+		return fmt.Errorf("flow export isn't supported by the dataplane yet") //nolint:goerr113
+	}
+
+	if p.Spec.ServiceChain != nil {
+		if err := p.Spec.ServiceChain.Validate(ctx, kube, p.Namespace, vpcs); err != nil {
+			return fmt.Errorf("service chain: %w", err)
+		}
+
+		// the traffic must never bypass the appliance, so it's rejected until the redirect could be configured
+		return fmt.Errorf("service chaining isn't supported by the dataplane yet") //nolint:goerr113
+	}
+
+	return ValidatePeeringEntries(ctx, kube, p.Namespace, p.Spec.Peering)
+}
+
+// DataplaneUnsupported returns the reason the peering can't be configured on the gateways yet or nil if it can, the
+// webhooks reject all of it but the peerings accepted before or generated from the meshes and the requests are only
+// skipped by the gateway controller and reported in their status, isExternal tells the externals from the VPCs
+func (s *PeeringSpec) DataplaneUnsupported(isExternal func(name string) bool) error {
+	if s.FlowExport {
+		return fmt.Errorf("flow export isn't supported by the dataplane yet") //nolint:goerr113
+	}
+	if s.ServiceChain != nil {
+		return fmt.Errorf("service chaining isn't supported by the dataplane yet") //nolint:goerr113
+	}
+
+	for _, vpcName := range slices.Sorted(maps.Keys(s.Peering)) {
+		entry := s.Peering[vpcName]
+		// the transit VPCs with no exposes of their own are skipped later if they have nothing to re-expose
+		if entry.ConsumeOnly() {
+			return fmt.Errorf("vpc %s doesn't expose anything, consume-only vpcs aren't supported by the dataplane yet", vpcName) //nolint:goerr113
+		}
+		if entry.QoS != nil {
+			return fmt.Errorf("vpc %s: qos policies aren't supported by the dataplane yet", vpcName) //nolint:goerr113
+		}
+
+		for idx, expose := range entry.Expose {
+			if expose.Translation != nil {
+				return fmt.Errorf("vpc %s expose %d: nat64/nat46 translation isn't supported by the dataplane yet", vpcName, idx) //nolint:goerr113
+			}
+			if len(expose.PortForwards) > 0 {
+				return fmt.Errorf("vpc %s expose %d: port forwards aren't supported by the dataplane yet", vpcName, idx) //nolint:goerr113
+			}
+			for _, ip := range expose.IPs {
+				if ip.GE != 0 || ip.LE != 0 {
+					return fmt.Errorf("vpc %s expose %d: ge/le route filters aren't supported by the dataplane yet", vpcName, idx) //nolint:goerr113
+				}
+				if !isExternal(vpcName) {
+					continue
+				}
+				// the BGP sessions with the externals only carry IPv4 unicast for now
+				for _, cidr := range []string{ip.CIDR, ip.Not} {
+					if prefix, err := netip.ParsePrefix(cidr); err == nil && prefix.Addr().Is6() {
+						return fmt.Errorf("external %s expose %d: ipv6 routes aren't supported for externals yet", vpcName, idx) //nolint:goerr113
+					}
+				}
+			}
+		}
+	}
 
 	return nil
 }
+
+// Validate checks the service chain and, if kube is set, that the inspection VPC exists and the appliance addresses
+// are in its subnets, peered are the names of the VPCs of the peering
+func (sc *PeeringServiceChain) Validate(ctx context.Context, kube kclient.Reader, namespace string, peered []string) error {
+	if sc.VPC == "" {
+		return fmt.Errorf("inspection vpc must be set") //nolint:goerr113
+	}
+	if slices.Contains(peered, sc.VPC) {
+		return fmt.Errorf("inspection vpc %s can't be one of the peered vpcs", sc.VPC) //nolint:goerr113
+	}
+
+	ingress, err := netip.ParseAddr(sc.IngressIP)
+	if err != nil {
+		return fmt.Errorf("invalid ingress IP %s: %w", sc.IngressIP, err)
+	}
+	egress, err := netip.ParseAddr(sc.EgressIP)
+	if err != nil {
+		return fmt.Errorf("invalid egress IP %s: %w", sc.EgressIP, err)
+	}
+
+	if kube == nil {
+		return nil
+	}
+
+	subnets, isExternal, err := GetPeeredSubnets(ctx, kube, namespace, sc.VPC)
+	if err != nil {
+		if kapierrors.IsNotFound(err) {
+			return fmt.Errorf("inspection vpc %s not found", sc.VPC) //nolint:goerr113
+		}
+
+		return err
+	}
+	if isExternal {
+		return fmt.Errorf("inspection vpc %s must be a vpc, not an external", sc.VPC) //nolint:goerr113
+	}
+
+	set, err := prefixset.Parse(SubnetCIDRs(subnets)...)
+	if err != nil {
+		return fmt.Errorf("parsing inspection vpc %s subnets: %w", sc.VPC, err)
+	}
+	if !set.Contains(ingress) {
+		return fmt.Errorf("ingress IP %s isn't in the subnets of inspection vpc %s", sc.IngressIP, sc.VPC) //nolint:goerr113
+	}
+	if !set.Contains(egress) {
+		return fmt.Errorf("egress IP %s isn't in the subnets of inspection vpc %s", sc.EgressIP, sc.VPC) //nolint:goerr113
+	}
+
+	return nil
+}
+
+// ValidatePeeringEntries validates the exposes of each of the peered VPCs and, if kube is set, checks that the ips
+// and as pools of the exposes match in size and don't overlap within the VPC
+func ValidatePeeringEntries(ctx context.Context, kube kclient.Reader, namespace string, entries map[string]*PeeringEntry) error {
+	for vpcName, entry := range entries {
+		if entry == nil {
+			continue
+		}
+
+		for idx, expose := range entry.Expose {
+			if err := expose.Validate(); err != nil {
+				return fmt.Errorf("vpc %s expose %d: %w", vpcName, idx, err)
+			}
+			if expose.Translation != nil {
+				return fmt.Errorf("vpc %s expose %d: nat64/nat46 translation isn't supported by the dataplane yet", vpcName, idx) //nolint:goerr113
+			}
+			if expose.Metric != 0 {
+				return fmt.Errorf("vpc %s expose %d: route metrics aren't supported by the dataplane yet", vpcName, idx) //nolint:goerr113
+			}
+		}
+
+		if entry.QoS != nil {
+			if err := entry.QoS.Validate(); err != nil {
+				return fmt.Errorf("vpc %s qos: %w", vpcName, err)
+			}
+
+			return fmt.Errorf("vpc %s: qos policies aren't supported by the dataplane yet", vpcName) //nolint:goerr113
+		}
+	}
+
+	for _, vpcName := range slices.Sorted(maps.Keys(entries)) {
+		if entries[vpcName].ConsumeOnly() {
+			return fmt.Errorf("vpc %s doesn't expose anything, consume-only vpcs aren't supported by the dataplane yet", vpcName) //nolint:goerr113
+		}
+	}
+
+	if kube == nil {
+		return nil
+	}
+
+	// address families of the subnets of each side, the externals are IPv4 only
+	families := map[string][2]bool{}
+	for vpcName := range entries {
+		subnets, isExternal, err := GetPeeredSubnets(ctx, kube, namespace, vpcName)
+		if err != nil {
+			if kapierrors.IsNotFound(err) {
+				continue
+			}
+
+			return err
+		}
+		if isExternal {
+			families[vpcName] = [2]bool{true, false}
+
+			continue
+		}
+
+		set, err := prefixset.Parse(SubnetCIDRs(subnets)...)
+		if err != nil {
+			return fmt.Errorf("vpc %s has invalid subnets: %w", vpcName, err)
+		}
+		v4, v6 := Families(set)
+		families[vpcName] = [2]bool{v4, v6}
+	}
+
+	// checks that all the other sides could reach the exposed addresses of the VPC, the dual-stack exposes are only
+	// reachable in the address families the other side has
+	checkFamily := func(vpcName string, idx int, exposed *prefixset.Set) error {
+		v4, v6 := Families(exposed)
+		for otherName, other := range families {
+			if otherName == vpcName {
+				continue
+			}
+			if !(v4 && other[0]) && !(v6 && other[1]) {
+				return fmt.Errorf("vpc %s expose %d: addresses %s are in an address family vpc %s doesn't have, use translation", //nolint:goerr113
+					vpcName, idx, exposed, otherName)
+			}
+		}
+
+		return nil
+	}
+
+	for vpcName, entry := range entries {
+		if entry == nil {
+			continue
+		}
+
+		subnets, isExternal, err := GetPeeredSubnets(ctx, kube, namespace, vpcName)
+		if err != nil {
+			if kapierrors.IsNotFound(err) {
+				continue
+			}
+
+			return err
+		}
+
+		// not entries of the externals act as route filters, so there are no address pools to check
+		if isExternal {
+			if entry.Transit {
+				return fmt.Errorf("external %s: transit is only supported for vpcs", vpcName) //nolint:goerr113
+			}
+			for idx, expose := range entry.Expose {
+				if len(expose.PortForwards) > 0 {
+					return fmt.Errorf("external %s expose %d: port forwards are only supported for vpcs", vpcName, idx) //nolint:goerr113
+				}
+				// the dataplane peering API only carries the prefixes themselves (no prefix length ranges), so ge/le
+				// can't be turned into the route filters and are deliberately rejected instead of being ignored
+				for _, ip := range expose.IPs {
+					if ip.GE != 0 || ip.LE != 0 {
+						return fmt.Errorf("external %s expose %d: ge/le route filters aren't supported by the dataplane yet", vpcName, idx) //nolint:goerr113
+					}
+				}
+
+				routes, err := expose.ExternalRoutes()
+				if err != nil {
+					return fmt.Errorf("external %s expose %d: %w", vpcName, idx, err)
+				}
+				// the BGP sessions with the externals only carry IPv4 unicast for now
+				if _, v6 := Families(routes); v6 {
+					return fmt.Errorf("external %s expose %d: ipv6 routes aren't supported for externals yet", vpcName, idx) //nolint:goerr113
+				}
+				if err := checkFamily(vpcName, idx, routes); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		ipSets, asSets := []*prefixset.Set{}, []*prefixset.Set{}
+		forwarded := map[string]int{}
+		for idx, expose := range entry.Expose {
+			if len(expose.IPs) == 0 {
+				return fmt.Errorf("vpc %s expose %d: ips must be set", vpcName, idx) //nolint:goerr113
+			}
+			for _, ip := range expose.IPs {
+				if ip.GE != 0 || ip.LE != 0 {
+					return fmt.Errorf("vpc %s expose %d: ge/le are only supported for externals", vpcName, idx) //nolint:goerr113
+				}
+			}
+
+			if expose.ASPool != nil {
+				if err := kube.Get(ctx, kclient.ObjectKey{Namespace: namespace, Name: expose.ASPool.Pool}, &NATPool{}); err != nil {
+					if kapierrors.IsNotFound(err) {
+						return fmt.Errorf("vpc %s expose %d: nat pool %s not found", vpcName, idx, expose.ASPool.Pool) //nolint:goerr113
+					}
+
+					return fmt.Errorf("getting nat pool %s: %w", expose.ASPool.Pool, err)
+				}
+			}
+
+			ips, as, err := expose.Sets(subnets)
+			if err != nil {
+				return fmt.Errorf("vpc %s expose %d: %w", vpcName, idx, err)
+			}
+
+			exposed := ips
+			if !as.IsEmpty() {
+				exposed = as
+			}
+			if err := checkFamily(vpcName, idx, exposed); err != nil {
+				return err
+			}
+
+			if err := expose.ValidatePortForwards(ips, as); err != nil {
+				return fmt.Errorf("vpc %s expose %d: %w", vpcName, idx, err)
+			}
+			for _, pf := range expose.PortForwards {
+				key, err := pf.Key()
+				if err != nil {
+					return fmt.Errorf("vpc %s expose %d: %w", vpcName, idx, err)
+				}
+				if prevIdx, exists := forwarded[key]; exists {
+					return fmt.Errorf("vpc %s expose %d port forward %s is already used by expose %d", vpcName, idx, key, prevIdx) //nolint:goerr113
+				}
+				forwarded[key] = idx
+			}
+			if len(expose.PortForwards) > 0 {
+				return fmt.Errorf("vpc %s expose %d: port forwards aren't supported by the dataplane yet", vpcName, idx) //nolint:goerr113
+			}
+
+			for prevIdx, prev := range ipSets {
+				if overlap := prev.Intersect(ips); !overlap.IsEmpty() {
+					return fmt.Errorf("vpc %s expose %d ips overlap with expose %d: %s", vpcName, idx, prevIdx, overlap) //nolint:goerr113
+				}
+			}
+			ipSets = append(ipSets, ips)
+
+			for prevIdx, prev := range asSets {
+				if overlap := prev.Intersect(as); !overlap.IsEmpty() {
+					return fmt.Errorf("vpc %s expose %d as overlaps with expose %d: %s", vpcName, idx, prevIdx, overlap) //nolint:goerr113
+				}
+			}
+			asSets = append(asSets, as)
+		}
+	}
+
+	return nil
+}
+
+// GetPeeredSubnets returns the subnets (name to CIDRs) of the VPC referenced by a peering entry or true if it's an
+// External, returns a not found error if there is neither VPC nor External with the name
+func GetPeeredSubnets(ctx context.Context, kube kclient.Reader, namespace, name string) (map[string][]string, bool, error) {
+	vpc := &VPCInfo{}
+	if err := kube.Get(ctx, kclient.ObjectKey{Namespace: namespace, Name: name}, vpc); err == nil {
+		subnets := map[string][]string{}
+		for subnetName, subnet := range vpc.Spec.Subnets {
+			if subnet != nil {
+				subnets[subnetName] = subnet.AllCIDRs()
+			}
+		}
+
+		return subnets, false, nil
+	} else if !kapierrors.IsNotFound(err) {
+		return nil, false, fmt.Errorf("getting vpc %s: %w", name, err)
+	}
+
+	ext := &External{}
+	if err := kube.Get(ctx, kclient.ObjectKey{Namespace: namespace, Name: name}, ext); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return nil, false, err //nolint:wrapcheck
+		}
+
+		return nil, false, fmt.Errorf("getting external %s: %w", name, err)
+	}
+
+	return nil, true, nil
+}
+
+// SubnetCIDRs returns the CIDRs of all subnets (name to CIDRs) of a VPC
+func SubnetCIDRs(subnets map[string][]string) []string {
+	res := []string{}
+	for _, name := range slices.Sorted(maps.Keys(subnets)) {
+		res = append(res, subnets[name]...)
+	}
+
+	return res
+}
+
+func (e *PeeringEntryExpose) Validate() error {
+	for _, ip := range e.IPs {
+		if err := ip.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, as := range e.As {
+		if err := as.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if e.Translation != nil {
+		if len(e.As) > 0 || e.ASPool != nil {
+			return fmt.Errorf("translation can't be combined with as or asPool") //nolint:goerr113
+		}
+		if err := e.Translation.Validate(); err != nil {
+			return fmt.Errorf("translation: %w", err)
+		}
+	}
+
+	if e.ASPool != nil {
+		if len(e.As) > 0 {
+			return fmt.Errorf("as and asPool are mutually exclusive") //nolint:goerr113
+		}
+		if e.ASPool.Pool == "" {
+			return fmt.Errorf("asPool pool must be set") //nolint:goerr113
+		}
+		if e.ASPool.PrefixLen == 0 || e.ASPool.PrefixLen > 32 {
+			return fmt.Errorf("asPool prefix length must be between 1 and 32") //nolint:goerr113
+		}
+	}
+
+	forwarded := map[string]bool{}
+	for _, pf := range e.PortForwards {
+		if err := pf.Validate(); err != nil {
+			return err
+		}
+
+		key, err := pf.Key()
+		if err != nil {
+			return err
+		}
+		if forwarded[key] {
+			return fmt.Errorf("duplicate port forward %s", key) //nolint:goerr113
+		}
+		forwarded[key] = true
+	}
+
+	return nil
+}
+
+// ValidatePortForwards checks that the port forwards addresses are in the ranges of the expose, ips and as are the
+// expose sets (see Sets)
+func (e *PeeringEntryExpose) ValidatePortForwards(ips, as *prefixset.Set) error {
+	external := as
+	if as.IsEmpty() {
+		external = ips
+	}
+
+	for _, pf := range e.PortForwards {
+		externalIP, err := netip.ParseAddr(pf.ExternalIP)
+		if err != nil {
+			return fmt.Errorf("invalid port forward external IP %s: %w", pf.ExternalIP, err)
+		}
+		if !external.Contains(externalIP) {
+			return fmt.Errorf("port forward %s external IP isn't exposed by the expose", netip.AddrPortFrom(externalIP, pf.ExternalPort)) //nolint:goerr113
+		}
+
+		internalIP, err := netip.ParseAddr(pf.InternalIP)
+		if err != nil {
+			return fmt.Errorf("invalid port forward internal IP %s: %w", pf.InternalIP, err)
+		}
+		if !ips.Contains(internalIP) {
+			return fmt.Errorf("port forward %s internal IP %s isn't in the expose ips", netip.AddrPortFrom(externalIP, pf.ExternalPort), pf.InternalIP) //nolint:goerr113
+		}
+	}
+
+	return nil
+}
+
+func (pf *PeeringEntryPortForward) Validate() error {
+	switch pf.Protocol {
+	case "", PortForwardProtocolTCP, PortForwardProtocolUDP:
+	default:
+		return fmt.Errorf("invalid port forward protocol %q, must be tcp or udp", pf.Protocol) //nolint:goerr113
+	}
+
+	externalIP, err := netip.ParseAddr(pf.ExternalIP)
+	if err != nil {
+		return fmt.Errorf("invalid port forward external IP %s: %w", pf.ExternalIP, err)
+	}
+	internalIP, err := netip.ParseAddr(pf.InternalIP)
+	if err != nil {
+		return fmt.Errorf("invalid port forward internal IP %s: %w", pf.InternalIP, err)
+	}
+	if externalIP.Is4() != internalIP.Is4() {
+		return fmt.Errorf("port forward external IP %s and internal IP %s must be in the same address family", pf.ExternalIP, pf.InternalIP) //nolint:goerr113
+	}
+	if pf.ExternalPort == 0 {
+		return fmt.Errorf("port forward external port must be set") //nolint:goerr113
+	}
+
+	return nil
+}
+
+// Key returns the protocol, external address and port of the port forward which have to be unique
+func (pf *PeeringEntryPortForward) Key() (string, error) {
+	protocol := pf.Protocol
+	if protocol == "" {
+		protocol = PortForwardProtocolTCP
+	}
+
+	externalIP, err := netip.ParseAddr(pf.ExternalIP)
+	if err != nil {
+		return "", fmt.Errorf("invalid port forward external IP %s: %w", pf.ExternalIP, err)
+	}
+
+	return protocol + "/" + netip.AddrPortFrom(externalIP, pf.ExternalPort).String(), nil
+}
+
+// Target returns the internal address and port the port forward points to
+func (pf *PeeringEntryPortForward) Target() (string, error) {
+	port := pf.InternalPort
+	if port == 0 {
+		port = pf.ExternalPort
+	}
+
+	internalIP, err := netip.ParseAddr(pf.InternalIP)
+	if err != nil {
+		return "", fmt.Errorf("invalid port forward internal IP %s: %w", pf.InternalIP, err)
+	}
+
+	return netip.AddrPortFrom(internalIP, port).String(), nil
+}
+
+// Sets returns the set of addresses exposed by the ips entries and the NAT pool defined by the as entries (empty if
+// NAT isn't used) with the not entries subtracted, vpcSubnet entries are resolved using the subnets (name to CIDRs).
+// It's only applicable to the VPCs as for the externals not entries are route filters.
+func (e *PeeringEntryExpose) Sets(subnets map[string][]string) (*prefixset.Set, *prefixset.Set, error) {
+	ips, ipsNot := &prefixset.Set{}, &prefixset.Set{}
+	for _, ip := range e.IPs {
+		cidrs := []string{ip.CIDR}
+		if ip.VPCSubnet != "" {
+			subnetCIDRs, ok := subnets[ip.VPCSubnet]
+			if !ok {
+				return nil, nil, fmt.Errorf("unknown vpc subnet %s", ip.VPCSubnet) //nolint:goerr113
+			}
+			cidrs = subnetCIDRs
+		}
+
+		if ip.Not != "" {
+			set, err := prefixset.Parse(ip.Not)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid ips not: %w", err)
+			}
+			ipsNot = ipsNot.Union(set)
+
+			continue
+		}
+
+		set, err := prefixset.Parse(cidrs...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ips cidr: %w", err)
+		}
+		ips = ips.Union(set)
+	}
+	ips = ips.Subtract(ipsNot)
+
+	if ips.IsEmpty() {
+		return nil, nil, fmt.Errorf("ips don't expose any addresses") //nolint:goerr113
+	}
+	ipsV4, ipsV6 := Families(ips)
+
+	// the translated addresses act as the "as" pool in the other address family
+	if e.Translation != nil {
+		switch {
+		case ipsV4 && ipsV6:
+			return nil, nil, fmt.Errorf("translation requires ips of a single address family, use separate exposes") //nolint:goerr113
+		case e.Translation.Mode == PeeringTranslationModeNAT64 && !ipsV4:
+			return nil, nil, fmt.Errorf("NAT64 translation requires IPv4 ips") //nolint:goerr113
+		case e.Translation.Mode == PeeringTranslationModeNAT46 && !ipsV6:
+			return nil, nil, fmt.Errorf("NAT46 translation requires IPv6 ips") //nolint:goerr113
+		}
+
+		as, err := e.Translation.Translate(ips)
+		if err != nil {
+			return nil, nil, err
+		}
+		if as.Size().Cmp(ips.Size()) != 0 {
+			return nil, nil, fmt.Errorf("translation mappings don't cover all ips %s", ips) //nolint:goerr113
+		}
+
+		return ips, as, nil
+	}
+
+	as, asNot := &prefixset.Set{}, &prefixset.Set{}
+	for _, asEntry := range e.As {
+		cidr := asEntry.CIDR
+		if asEntry.Not != "" {
+			cidr = asEntry.Not
+		}
+
+		set, err := prefixset.Parse(cidr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid as: %w", err)
+		}
+
+		if asEntry.Not != "" {
+			asNot = asNot.Union(set)
+		} else {
+			as = as.Union(set)
+		}
+	}
+	as = as.Subtract(asNot)
+
+	// dual-stack ips are NATed within each address family, so the pool has to match the ips in both of them
+	if len(e.As) > 0 {
+		ipsByFamily, asByFamily := SplitFamilies(ips), SplitFamilies(as)
+		for idx := range ipsByFamily {
+			familyIPs, familyAs := ipsByFamily[idx], asByFamily[idx]
+			if familyIPs.IsEmpty() && !familyAs.IsEmpty() {
+				return nil, nil, fmt.Errorf("as pool must be in the same address family as the ips, use translation instead") //nolint:goerr113
+			}
+			if familyAs.Size().Cmp(familyIPs.Size()) != 0 {
+				return nil, nil, fmt.Errorf("as pool has %s addresses while ips expose %s", familyAs.Size(), familyIPs.Size()) //nolint:goerr113
+			}
+		}
+	}
+	if e.ASPool != nil && (!ipsV4 || ipsV6) {
+		return nil, nil, fmt.Errorf("as pools are only allocated for IPv4 ips") //nolint:goerr113
+	}
+	if e.ASPool != nil && e.ASPool.PrefixLen <= 32 {
+		size := new(big.Int).Lsh(big.NewInt(1), uint(32-e.ASPool.PrefixLen))
+		if size.Cmp(ips.Size()) != 0 {
+			return nil, nil, fmt.Errorf("as pool /%d has %s addresses while ips expose %s", e.ASPool.PrefixLen, size, ips.Size()) //nolint:goerr113
+		}
+	}
+
+	return ips, as, nil
+}
+
+// IPsKey returns the sorted ips entries of the expose joined by commas, the not entries prefixed with "!". It
+// identifies the expose within the VPC as the ips of its exposes don't overlap.
+func (e *PeeringEntryExpose) IPsKey() string {
+	ips := make([]string, 0, len(e.IPs))
+	for _, ip := range e.IPs {
+		switch {
+		case ip.VPCSubnet != "":
+			ips = append(ips, ip.VPCSubnet)
+		case ip.Not != "":
+			ips = append(ips, "!"+ip.Not)
+		default:
+			ips = append(ips, ip.CIDR)
+		}
+	}
+	slices.Sort(ips)
+
+	return strings.Join(ips, ",")
+}
+
+// WithAllocatedAs returns a copy of the expose with the "as" pool set to the prefix allocated for its ASPool
+func (e *PeeringEntryExpose) WithAllocatedAs(cidr string) PeeringEntryExpose {
+	res := *e.DeepCopy()
+	res.As = []PeeringEntryAs{{CIDR: cidr}}
+	res.ASPool = nil
+
+	return res
+}
+
+// ExternalRoutes returns the range of the routes accepted from an external by the expose, which is everything
+// permitted by the cidr entries (or anything if there are none) that isn't filtered out by the not entries
+func (e *PeeringEntryExpose) ExternalRoutes() (*prefixset.Set, error) {
+	permit, deny := &prefixset.Set{}, &prefixset.Set{}
+	for _, ip := range e.IPs {
+		switch {
+		case ip.CIDR != "":
+			set, err := prefixset.Parse(ip.CIDR)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ips cidr: %w", err)
+			}
+			permit = permit.Union(set)
+		case ip.Not != "":
+			set, err := prefixset.Parse(ip.Not)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ips not: %w", err)
+			}
+			deny = deny.Union(set)
+		}
+	}
+	if permit.IsEmpty() {
+		permit = allIPv4
+	}
+
+	return permit.Subtract(deny), nil
+}
+
+func (ip *PeeringEntryIP) Validate() error {
+	set := 0
+	for _, val := range []string{ip.CIDR, ip.Not, ip.VPCSubnet} {
+		if val != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of cidr, not or vpcSubnet must be set in ips entry") //nolint:goerr113
+	}
+
+	if ip.VPCSubnet != "" {
+		if ip.GE != 0 || ip.LE != 0 {
+			return fmt.Errorf("ge/le can't be used with vpcSubnet %s", ip.VPCSubnet) //nolint:goerr113
+		}
+
+		return nil
+	}
+
+	cidr := ip.CIDR
+	if cidr == "" {
+		cidr = ip.Not
+	}
+
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return fmt.Errorf("invalid ips CIDR %s: %w", cidr, err)
+	}
+
+	ge, le := ip.PrefixLenRange(prefix)
+	if ge < prefix.Bits() {
+		return fmt.Errorf("ge %d must be at least the prefix length of %s", ge, cidr) //nolint:goerr113
+	}
+	if le > prefix.Addr().BitLen() {
+		return fmt.Errorf("le %d must be at most %d for %s", le, prefix.Addr().BitLen(), cidr) //nolint:goerr113
+	}
+	if ge > le {
+		return fmt.Errorf("ge %d must be less than or equal to le %d for %s", ge, le, cidr) //nolint:goerr113
+	}
+
+	return nil
+}
+
+// PrefixLenRange returns the range of prefix lengths matched by the entry for the specified (already parsed) CIDR,
+// defaulting to the exact prefix length if neither ge nor le is set and to the max prefix length if only ge is set
+func (ip *PeeringEntryIP) PrefixLenRange(prefix netip.Prefix) (int, int) {
+	ge, le := prefix.Bits(), prefix.Bits()
+
+	if ip.GE != 0 {
+		ge = int(ip.GE)
+		le = prefix.Addr().BitLen()
+	}
+	if ip.LE != 0 {
+		le = int(ip.LE)
+	}
+
+	return ge, le
+}
+
+func (as *PeeringEntryAs) Validate() error {
+	if (as.CIDR == "") == (as.Not == "") {
+		return fmt.Errorf("exactly one of cidr or not must be set in as entry") //nolint:goerr113
+	}
+
+	cidr := as.CIDR
+	if cidr == "" {
+		cidr = as.Not
+	}
+
+	if _, err := netip.ParsePrefix(cidr); err != nil {
+		return fmt.Errorf("invalid as CIDR %s: %w", cidr, err)
+	}
+
+	return nil
+}
+
+// validateNoASPools rejects the exposes allocated from the NAT pools as the allocations are only tracked for peerings
+func validateNoASPools(entries map[string]*PeeringEntry) error {
+	for vpcName, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		for idx, expose := range entry.Expose {
+			if expose.ASPool != nil {
+				return fmt.Errorf("vpc %s expose %d: asPool is only supported in peerings", vpcName, idx) //nolint:goerr113
+			}
+		}
+	}
+
+	return nil
+}
+
+// Families returns true for each address family (IPv4, IPv6) present in the set
+func Families(set *prefixset.Set) (bool, bool) {
+	v4, v6 := false, false
+	for _, prefix := range set.Prefixes() {
+		if prefix.Addr().Is4() {
+			v4 = true
+		} else {
+			v6 = true
+		}
+	}
+
+	return v4, v6
+}
+
+// SplitFamilies returns the IPv4 and the IPv6 addresses of the set
+func SplitFamilies(set *prefixset.Set) [2]*prefixset.Set {
+	return [2]*prefixset.Set{set.Intersect(allIPv4), set.Intersect(allIPv6)}
+}
+
+// NAT64Prefix returns the prefix the IPv4 addresses are embedded into, the well-known one if not set
+func (t *PeeringEntryTranslation) NAT64Prefix() (netip.Prefix, error) {
+	cidr := t.Prefix
+	if cidr == "" {
+		cidr = NAT64WellKnownPrefix
+	}
+
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid NAT64 prefix %s: %w", cidr, err)
+	}
+
+	return prefix, nil
+}
+
+func (t *PeeringEntryTranslation) Validate() error {
+	switch t.Mode {
+	case PeeringTranslationModeNAT64:
+		if len(t.Mappings) > 0 {
+			return fmt.Errorf("mappings are only supported for NAT46") //nolint:goerr113
+		}
+
+		prefix, err := t.NAT64Prefix()
+		if err != nil {
+			return err
+		}
+		if !prefix.Addr().Is6() || prefix.Addr().Is4In6() {
+			return fmt.Errorf("NAT64 prefix %s must be an IPv6 prefix", prefix) //nolint:goerr113
+		}
+		if prefix.Masked() != prefix {
+			return fmt.Errorf("NAT64 prefix %s has host bits set", prefix) //nolint:goerr113
+		}
+		if !slices.Contains([]int{32, 40, 48, 56, 64, 96}, prefix.Bits()) {
+			return fmt.Errorf("NAT64 prefix %s length must be 32, 40, 48, 56, 64 or 96", prefix) //nolint:goerr113
+		}
+		// bits 64 to 71 (the "u" octet) are reserved and must be zero
+		if prefix.Bits() == 96 && prefix.Addr().As16()[8] != 0 {
+			return fmt.Errorf("NAT64 prefix %s must have bits 64 to 71 set to zero", prefix) //nolint:goerr113
+		}
+	case PeeringTranslationModeNAT46:
+		if t.Prefix != "" {
+			return fmt.Errorf("prefix is only supported for NAT64") //nolint:goerr113
+		}
+		if len(t.Mappings) == 0 {
+			return fmt.Errorf("NAT46 requires at least one mapping") //nolint:goerr113
+		}
+
+		v4s, v6s := &prefixset.Set{}, &prefixset.Set{}
+		for _, mapping := range t.Mappings {
+			v6, v4, err := mapping.Prefixes()
+			if err != nil {
+				return err
+			}
+			if v4s.Overlaps(prefixset.New(v4)) {
+				return fmt.Errorf("mapping IPv4 prefix %s overlaps with the other mappings", v4) //nolint:goerr113
+			}
+			if v6s.Overlaps(prefixset.New(v6)) {
+				return fmt.Errorf("mapping IPv6 prefix %s overlaps with the other mappings", v6) //nolint:goerr113
+			}
+			v4s, v6s = v4s.Union(prefixset.New(v4)), v6s.Union(prefixset.New(v6))
+		}
+	default:
+		return fmt.Errorf("invalid translation mode %q, must be NAT64 or NAT46", t.Mode) //nolint:goerr113
+	}
+
+	return nil
+}
+
+// Prefixes returns the IPv6 and IPv4 prefixes of the mapping, they have to be of the same size
+func (m *PeeringTranslationMapping) Prefixes() (netip.Prefix, netip.Prefix, error) {
+	v6, err := netip.ParsePrefix(m.IPv6)
+	if err != nil || !v6.Addr().Is6() || v6.Addr().Is4In6() || v6.Masked() != v6 {
+		return netip.Prefix{}, netip.Prefix{}, fmt.Errorf("invalid mapping IPv6 prefix %s", m.IPv6) //nolint:goerr113
+	}
+	v4, err := netip.ParsePrefix(m.IPv4)
+	if err != nil || !v4.Addr().Is4() || v4.Masked() != v4 {
+		return netip.Prefix{}, netip.Prefix{}, fmt.Errorf("invalid mapping IPv4 prefix %s", m.IPv4) //nolint:goerr113
+	}
+	if 128-v6.Bits() != 32-v4.Bits() {
+		return netip.Prefix{}, netip.Prefix{}, fmt.Errorf("mapping prefixes %s and %s must be of the same size", m.IPv6, m.IPv4) //nolint:goerr113
+	}
+
+	return v6, v4, nil
+}
+
+// Translate returns the addresses the set is exposed as in the other address family, the addresses that can't be
+// translated (the IPv6 ones not covered by the mappings) are skipped
+func (t *PeeringEntryTranslation) Translate(set *prefixset.Set) (*prefixset.Set, error) {
+	res := []netip.Prefix{}
+
+	switch t.Mode {
+	case PeeringTranslationModeNAT64:
+		nat64, err := t.NAT64Prefix()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, prefix := range set.Prefixes() {
+			if !prefix.Addr().Is4() {
+				continue
+			}
+			res = append(res, embedIPv4Prefix(nat64, prefix))
+		}
+	case PeeringTranslationModeNAT46:
+		for _, mapping := range t.Mappings {
+			v6, v4, err := mapping.Prefixes()
+			if err != nil {
+				return nil, err
+			}
+
+			// the host bits (at most 32) are the same in both families
+			base := binary.BigEndian.Uint32(v4.Addr().AsSlice())
+			for _, prefix := range set.Intersect(prefixset.New(v6)).Prefixes() {
+				v6Bytes := prefix.Addr().As16()
+				offset := binary.BigEndian.Uint32(v6Bytes[12:])
+				if hostBits := 128 - v6.Bits(); hostBits < 32 {
+					offset &= 1<<hostBits - 1
+				}
+
+				addr := netip.AddrFrom4([4]byte(binary.BigEndian.AppendUint32(nil, base|offset)))
+				res = append(res, netip.PrefixFrom(addr, prefix.Bits()-(v6.Bits()-v4.Bits())))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid translation mode %q", t.Mode) //nolint:goerr113
+	}
+
+	return prefixset.New(res...), nil
+}
+
+// embedIPv4Prefix returns the IPv6 prefix the IPv4 prefix is embedded as into the NAT64 prefix (RFC 6052), skipping
+// the reserved bits 64 to 71
+func embedIPv4Prefix(nat64 netip.Prefix, prefix netip.Prefix) netip.Prefix {
+	addr := nat64.Addr().As16()
+	v4 := prefix.Addr().As4()
+
+	pos := nat64.Bits() / 8
+	for _, octet := range v4 {
+		if pos == 8 {
+			pos++
+		}
+		addr[pos] = octet
+		pos++
+	}
+
+	bits := nat64.Bits() + prefix.Bits()
+	if nat64.Bits() < 64 && bits > 64 || nat64.Bits() == 64 {
+		bits += 8
+	}
+
+	return netip.PrefixFrom(netip.AddrFrom16(addr), bits)
+}
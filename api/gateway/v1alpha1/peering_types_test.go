@@ -0,0 +1,527 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPeeringEntryIPValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		ip   PeeringEntryIP
+		err  bool
+	}{
+		{"cidr", PeeringEntryIP{CIDR: "10.0.0.0/8"}, false},
+		{"not", PeeringEntryIP{Not: "10.0.0.0/8"}, false},
+		{"vpc-subnet", PeeringEntryIP{VPCSubnet: "subnet-1"}, false},
+		{"empty", PeeringEntryIP{}, true},
+		{"cidr-and-not", PeeringEntryIP{CIDR: "10.0.0.0/8", Not: "10.1.0.0/16"}, true},
+		{"invalid-cidr", PeeringEntryIP{CIDR: "10.0.0.0"}, true},
+		{"ge-le", PeeringEntryIP{CIDR: "0.0.0.0/0", GE: 8, LE: 24}, false},
+		{"ge-only", PeeringEntryIP{CIDR: "10.0.0.0/8", GE: 16}, false},
+		{"le-only", PeeringEntryIP{Not: "10.0.0.0/8", LE: 24}, false},
+		{"ge-eq-le", PeeringEntryIP{CIDR: "10.0.0.0/8", GE: 24, LE: 24}, false},
+		{"ge-gt-le", PeeringEntryIP{CIDR: "0.0.0.0/0", GE: 24, LE: 8}, true},
+		{"ge-lt-len", PeeringEntryIP{CIDR: "10.0.0.0/16", GE: 8}, true},
+		{"le-lt-len", PeeringEntryIP{CIDR: "10.0.0.0/16", LE: 8}, true},
+		{"le-too-long", PeeringEntryIP{CIDR: "10.0.0.0/16", LE: 33}, true},
+		{"vpc-subnet-ge", PeeringEntryIP{VPCSubnet: "subnet-1", GE: 24}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ip.Validate()
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPeeringEntryExposeValidatePortForwards(t *testing.T) {
+	ips := prefixset.MustParse("10.1.1.0/24")
+	as := prefixset.MustParse("192.168.1.0/28")
+
+	for _, tt := range []struct {
+		name string
+		pf   PeeringEntryPortForward
+		as   *prefixset.Set
+		err  bool
+	}{
+		{"nat", PeeringEntryPortForward{ExternalIP: "192.168.1.10", ExternalPort: 443, InternalIP: "10.1.1.25", InternalPort: 8443}, as, false},
+		{"no-nat", PeeringEntryPortForward{ExternalIP: "10.1.1.25", ExternalPort: 443, InternalIP: "10.1.1.25"}, &prefixset.Set{}, false},
+		{"external-not-in-as", PeeringEntryPortForward{ExternalIP: "10.1.1.25", ExternalPort: 443, InternalIP: "10.1.1.25"}, as, true},
+		{"internal-not-in-ips", PeeringEntryPortForward{ExternalIP: "192.168.1.10", ExternalPort: 443, InternalIP: "10.1.2.25"}, as, true},
+		{"invalid-ip", PeeringEntryPortForward{ExternalIP: "192.168.1", ExternalPort: 443, InternalIP: "10.1.1.25"}, as, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			expose := &PeeringEntryExpose{PortForwards: []PeeringEntryPortForward{tt.pf}}
+			err := expose.ValidatePortForwards(ips, tt.as)
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	pf := PeeringEntryPortForward{ExternalIP: "192.168.1.10", ExternalPort: 443, InternalIP: "10.1.1.25"}
+	require.NoError(t, pf.Validate())
+	key, err := pf.Key()
+	require.NoError(t, err)
+	require.Equal(t, "tcp/192.168.1.10:443", key)
+	target, err := pf.Target()
+	require.NoError(t, err)
+	require.Equal(t, "10.1.1.25:443", target)
+
+	pf.Protocol = "sctp"
+	require.Error(t, pf.Validate())
+
+	pf.ExternalIP, pf.InternalIP = "192.168.1", "10.1.1"
+	_, err = pf.Key()
+	require.ErrorContains(t, err, "invalid port forward external IP 192.168.1")
+	_, err = pf.Target()
+	require.ErrorContains(t, err, "invalid port forward internal IP 10.1.1")
+}
+
+func TestPeeringDefaultVPCPair(t *testing.T) {
+	p := &Peering{
+		Spec: PeeringSpec{Peering: map[string]*PeeringEntry{"vpc-2": {}, "vpc-1": {}}},
+	}
+	p.Labels = map[string]string{ListLabelVPC("vpc-3"): ListLabelValue, "other": "value"}
+
+	p.Default()
+
+	require.Equal(t, "vpc-1--vpc-2", p.Name)
+	require.Equal(t, "vpc-1--vpc-2", p.LabeledVPCPair())
+	require.Equal(t, map[string]string{
+		ListLabelVPC("vpc-1"): ListLabelValue,
+		ListLabelVPC("vpc-2"): ListLabelValue,
+		"other":               "value",
+	}, p.Labels)
+
+	delete(p.Labels, ListLabelVPC("vpc-2"))
+	require.Empty(t, p.LabeledVPCPair())
+}
+
+func TestPeeringPolicyPeeringFor(t *testing.T) {
+	pp := &PeeringPolicy{
+		Spec: PeeringPolicySpec{
+			Peer:       "shared",
+			Expose:     []PeeringEntryExpose{{IPs: []PeeringEntryIP{{VPCSubnet: "default"}}}},
+			PeerExpose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: "10.99.0.0/16"}}}},
+		},
+	}
+	pp.Name = "tenants"
+	pp.Namespace = "default"
+
+	peering := pp.PeeringFor("tenant-1")
+	require.Equal(t, "shared--tenant-1", peering.Name)
+	require.Equal(t, "default", peering.Namespace)
+	require.Equal(t, "shared--tenant-1", peering.LabeledVPCPair())
+	require.Equal(t, "tenants", peering.Labels[LabelPeeringPolicy])
+	require.Equal(t, pp.Spec.Expose, peering.Spec.Peering["tenant-1"].Expose)
+	require.Equal(t, pp.Spec.PeerExpose, peering.Spec.Peering["shared"].Expose)
+
+	peering.Spec.Peering["tenant-1"].Expose[0].IPs[0].VPCSubnet = "changed"
+	require.Equal(t, "default", pp.Spec.Expose[0].IPs[0].VPCSubnet)
+}
+
+func TestPeeringWindowState(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) *kmetav1.Time {
+		return &kmetav1.Time{Time: base.AddDate(0, 0, days)}
+	}
+
+	for _, tt := range []struct {
+		name  string
+		spec  PeeringSpec
+		state PeeringWindowState
+		next  time.Time
+	}{
+		{"always", PeeringSpec{}, PeeringWindowStateActive, time.Time{}},
+		{"upcoming", PeeringSpec{ActiveFrom: at(1), ExpiresAt: at(7)}, PeeringWindowStateUpcoming, at(1).Time},
+		{"active", PeeringSpec{ActiveFrom: at(-1), ExpiresAt: at(7)}, PeeringWindowStateActive, at(7).Time},
+		{"active-no-expiry", PeeringSpec{ActiveFrom: at(0)}, PeeringWindowStateActive, time.Time{}},
+		{"expired", PeeringSpec{ActiveFrom: at(-7), ExpiresAt: at(0)}, PeeringWindowStateExpired, time.Time{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			state, next := tt.spec.WindowState(base)
+			require.Equal(t, tt.state, state)
+			require.Equal(t, tt.next, next)
+		})
+	}
+}
+
+func TestPeeringEffectiveAdvertisementMode(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		adv       *PeeringAdvertisement
+		routes    int
+		unbounded bool
+		expected  PeeringAdvertisementMode
+	}{
+		{"default", nil, 1000, true, PeeringAdvertisementModeFull},
+		{"full", &PeeringAdvertisement{Mode: PeeringAdvertisementModeFull}, 1000, false, PeeringAdvertisementModeFull},
+		{"aggregate", &PeeringAdvertisement{Mode: PeeringAdvertisementModeAggregate}, 1, false, PeeringAdvertisementModeAggregate},
+		{"default-route", &PeeringAdvertisement{Mode: PeeringAdvertisementModeDefault}, 1, false, PeeringAdvertisementModeDefault},
+		{"auto-below", &PeeringAdvertisement{Mode: PeeringAdvertisementModeAuto}, DefaultPeeringAdvertisementThreshold, false, PeeringAdvertisementModeFull},
+		{"auto-above", &PeeringAdvertisement{Mode: PeeringAdvertisementModeAuto}, DefaultPeeringAdvertisementThreshold + 1, false, PeeringAdvertisementModeDefault},
+		{"auto-threshold", &PeeringAdvertisement{Mode: PeeringAdvertisementModeAuto, Threshold: 2}, 3, false, PeeringAdvertisementModeDefault},
+		{"auto-unbounded", &PeeringAdvertisement{Mode: PeeringAdvertisementModeAuto}, 1, true, PeeringAdvertisementModeDefault},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &PeeringSpec{Advertisement: tt.adv}
+			require.Equal(t, tt.expected, spec.EffectiveAdvertisementMode(tt.routes, tt.unbounded))
+		})
+	}
+}
+
+func TestPeeringQoSPolicyValidate(t *testing.T) {
+	dscp := func(v uint8) *uint8 { return &v }
+
+	for _, tt := range []struct {
+		name string
+		qos  PeeringQoSPolicy
+		bits uint64
+		err  bool
+	}{
+		{"empty", PeeringQoSPolicy{}, 0, false},
+		{"mega", PeeringQoSPolicy{Bandwidth: "500M"}, 500_000_000, false},
+		{"giga", PeeringQoSPolicy{Bandwidth: "10G", PacketRate: 1_000_000}, 10_000_000_000, false},
+		{"plain", PeeringQoSPolicy{Bandwidth: "64000"}, 64_000, false},
+		{"dscp", PeeringQoSPolicy{DSCP: dscp(46)}, 0, false},
+		{"priority", PeeringQoSPolicy{Priority: PeeringQoSPriorityHigh}, 0, false},
+		{"binary-suffix", PeeringQoSPolicy{Bandwidth: "1Gi"}, 0, true},
+		{"invalid-unit", PeeringQoSPolicy{Bandwidth: "100Mbps"}, 0, true},
+		{"too-low", PeeringQoSPolicy{Bandwidth: "1k"}, 0, true},
+		{"too-high", PeeringQoSPolicy{Bandwidth: "1T"}, 0, true},
+		{"fractional", PeeringQoSPolicy{Bandwidth: "100500m"}, 0, true},
+		{"packet-rate-too-high", PeeringQoSPolicy{PacketRate: 2_000_000_000}, 0, true},
+		{"dscp-too-high", PeeringQoSPolicy{DSCP: dscp(64)}, 0, true},
+		{"dscp-and-priority", PeeringQoSPolicy{DSCP: dscp(46), Priority: PeeringQoSPriorityLow}, 0, true},
+		{"invalid-priority", PeeringQoSPolicy{Priority: "Urgent"}, 0, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.qos.Validate()
+			if tt.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			bits, err := tt.qos.BandwidthBits()
+			require.NoError(t, err)
+			require.Equal(t, tt.bits, bits)
+		})
+	}
+}
+
+func TestPeeringEntryTranslationTranslate(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		translation PeeringEntryTranslation
+		ips         string
+		expected    string
+		err         bool
+	}{
+		{"nat64-well-known", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64}, "10.1.0.0/16", "64:ff9b::a01:0/112", false},
+		{"nat64-32", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64, Prefix: "2001:db8::/32"}, "192.0.2.0/24", "2001:db8:c000:200::/56", false},
+		{"nat64-56", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64, Prefix: "2001:db8:0:100::/56"}, "10.1.0.0/16", "2001:db8:0:10a:1::/80", false},
+		{"nat64-64", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64, Prefix: "2001:db8:1:2::/64"}, "10.0.0.0/8", "2001:db8:1:2:a::/80", false},
+		{"nat46", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT46, Mappings: []PeeringTranslationMapping{
+			{IPv6: "fd00::/120", IPv4: "10.9.0.0/24"},
+		}}, "fd00::/120", "10.9.0.0/24", false},
+		{"nat46-partial", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT46, Mappings: []PeeringTranslationMapping{
+			{IPv6: "fd00::/120", IPv4: "10.9.0.0/24"},
+		}}, "fd00::80/121", "10.9.0.128/25", false},
+		{"invalid-mode", PeeringEntryTranslation{Mode: "NAT66"}, "10.1.0.0/16", "", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.translation.Translate(prefixset.MustParse(tt.ips))
+			if tt.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.expected}, res.Strings())
+		})
+	}
+}
+
+func TestPeeringEntryTranslationValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		translation PeeringEntryTranslation
+		err         bool
+	}{
+		{"nat64-well-known", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64}, false},
+		{"nat64-48", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64, Prefix: "2001:db8:1::/48"}, false},
+		{"nat64-invalid-len", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64, Prefix: "2001:db8::/80"}, true},
+		{"nat64-ipv4-prefix", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64, Prefix: "10.0.0.0/8"}, true},
+		{"nat64-u-octet", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64, Prefix: "2001:db8:0:0:100::/96"}, true},
+		{"nat64-mappings", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64, Mappings: []PeeringTranslationMapping{
+			{IPv6: "fd00::/120", IPv4: "10.9.0.0/24"},
+		}}, true},
+		{"nat46", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT46, Mappings: []PeeringTranslationMapping{
+			{IPv6: "fd00::/120", IPv4: "10.9.0.0/24"},
+		}}, false},
+		{"nat46-no-mappings", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT46}, true},
+		{"nat46-size-mismatch", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT46, Mappings: []PeeringTranslationMapping{
+			{IPv6: "fd00::/112", IPv4: "10.9.0.0/24"},
+		}}, true},
+		{"nat46-overlap", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT46, Mappings: []PeeringTranslationMapping{
+			{IPv6: "fd00::/120", IPv4: "10.9.0.0/24"},
+			{IPv6: "fd00:1::/120", IPv4: "10.9.0.128/25"},
+		}}, true},
+		{"nat46-prefix", PeeringEntryTranslation{Mode: PeeringTranslationModeNAT46, Prefix: "64:ff9b::/96", Mappings: []PeeringTranslationMapping{
+			{IPv6: "fd00::/120", IPv4: "10.9.0.0/24"},
+		}}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.translation.Validate()
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPeeringEntryExposeSetsFamilies(t *testing.T) {
+	nat46 := &PeeringEntryTranslation{Mode: PeeringTranslationModeNAT46, Mappings: []PeeringTranslationMapping{
+		{IPv6: "fd00::/120", IPv4: "10.9.0.0/24"},
+	}}
+
+	for _, tt := range []struct {
+		name   string
+		expose PeeringEntryExpose
+		as     []string
+		err    bool
+	}{
+		{"ipv6", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "fd00::/64"}}}, []string{}, false},
+		{"ipv6-as", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "fd00::/120"}}, As: []PeeringEntryAs{{CIDR: "fd01::/120"}}}, []string{"fd01::/120"}, false},
+		{"dual-stack", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "10.0.1.0/24"}, {CIDR: "fd00::/120"}}}, []string{}, false},
+		{"dual-stack-subnet", PeeringEntryExpose{IPs: []PeeringEntryIP{{VPCSubnet: "dual"}, {Not: "fd00:1::/120"}}}, []string{}, false},
+		{"dual-stack-as", PeeringEntryExpose{IPs: []PeeringEntryIP{{VPCSubnet: "dual"}}, As: []PeeringEntryAs{{CIDR: "192.168.1.0/24"}, {CIDR: "fd01::/64"}}}, []string{"192.168.1.0/24", "fd01::/64"}, false},
+		{"dual-stack-as-ipv4-only", PeeringEntryExpose{IPs: []PeeringEntryIP{{VPCSubnet: "dual"}}, As: []PeeringEntryAs{{CIDR: "192.168.1.0/24"}}}, nil, true},
+		{"dual-stack-nat64", PeeringEntryExpose{IPs: []PeeringEntryIP{{VPCSubnet: "dual"}}, Translation: &PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64}}, nil, true},
+		{"dual-stack-as-pool", PeeringEntryExpose{IPs: []PeeringEntryIP{{VPCSubnet: "dual"}}, ASPool: &PeeringEntryASPool{Pool: "pool", PrefixLen: 24}}, nil, true},
+		{"as-other-family", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "fd00::/120"}}, As: []PeeringEntryAs{{CIDR: "10.9.0.0/24"}}}, nil, true},
+		{"nat64", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "10.0.1.0/24"}}, Translation: &PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64}}, []string{"64:ff9b::a00:100/120"}, false},
+		{"nat64-ipv6-ips", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "fd00::/120"}}, Translation: &PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64}}, nil, true},
+		{"nat46", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "fd00::/120"}}, Translation: nat46}, []string{"10.9.0.0/24"}, false},
+		{"nat46-not-covered", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "fd00::/112"}}, Translation: nat46}, nil, true},
+		{"as-pool-ipv6", PeeringEntryExpose{IPs: []PeeringEntryIP{{CIDR: "fd00::/120"}}, ASPool: &PeeringEntryASPool{Pool: "pool", PrefixLen: 24}}, nil, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, as, err := tt.expose.Sets(map[string][]string{"dual": {"10.0.1.0/24", "fd00:1::/64"}})
+			if tt.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.as, as.Strings())
+		})
+	}
+}
+
+func TestPeeringValidate(t *testing.T) {
+	kube := kubetest.NewReader(
+		&VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-1"},
+			Spec:       VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.1.0.0/24"}}},
+		},
+		&VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-2"},
+			Spec:       VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.2.0.0/24"}}},
+		},
+		&VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-fw"},
+			Spec:       VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.9.0.0/24"}}},
+		},
+	)
+
+	for _, tt := range []struct {
+		name       string
+		chain      *PeeringServiceChain
+		flowExport bool
+		err        string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name:       "flow-export",
+			flowExport: true,
+			err:        "flow export isn't supported by the dataplane yet",
+		},
+		{
+			name:  "chain",
+			chain: &PeeringServiceChain{VPC: "vpc-fw", IngressIP: "10.9.0.10", EgressIP: "10.9.0.11"},
+			err:   "service chaining isn't supported by the dataplane yet",
+		},
+		{
+			name:  "outside-subnets",
+			chain: &PeeringServiceChain{VPC: "vpc-fw", IngressIP: "10.9.0.10", EgressIP: "10.9.1.11"},
+			err:   "service chain: egress IP 10.9.1.11 isn't in the subnets of inspection vpc vpc-fw",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			peering := &Peering{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-1--vpc-2"},
+				Spec: PeeringSpec{
+					Peering: map[string]*PeeringEntry{
+						"vpc-1": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+						"vpc-2": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+					},
+					ServiceChain: tt.chain,
+					FlowExport:   tt.flowExport,
+				},
+			}
+
+			err := peering.Validate(context.Background(), kube)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidatePeeringEntries(t *testing.T) {
+	kube := kubetest.NewReader(
+		&VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-1"},
+			Spec:       VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.1.0.0/24"}}},
+		},
+		&VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-2"},
+			Spec:       VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.2.0.0/24"}}},
+		},
+		&External{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "ext-1"},
+		},
+	)
+	vpc1 := &PeeringEntry{Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}}
+
+	for _, tt := range []struct {
+		name    string
+		entries map[string]*PeeringEntry
+		err     string
+	}{
+		{
+			name: "vpcs",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: "10.2.0.0/24"}}}}},
+			},
+		},
+		{
+			name: "external",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"ext-1": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: "0.0.0.0/0"}}}}},
+			},
+		},
+		{
+			name: "external-ipv6",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"ext-1": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: "::/0"}}}}},
+			},
+			err: "external ext-1 expose 0: ipv6 routes aren't supported for externals yet",
+		},
+		{
+			name: "consume-only",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": nil,
+			},
+			err: "vpc vpc-2 doesn't expose anything, consume-only vpcs aren't supported by the dataplane yet",
+		},
+		{
+			name: "vpc-ge-le",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: "10.2.0.0/16", GE: 24}}}}},
+			},
+			err: "vpc vpc-2 expose 0: ge/le are only supported for externals",
+		},
+		{
+			name: "external-ge-le",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"ext-1": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: "0.0.0.0/0", LE: 24}}}}},
+			},
+			err: "external ext-1 expose 0: ge/le route filters aren't supported by the dataplane yet",
+		},
+		{
+			name: "port-forwards",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []PeeringEntryExpose{{
+					IPs:          []PeeringEntryIP{{CIDR: "10.2.0.0/24"}},
+					As:           []PeeringEntryAs{{CIDR: "192.168.2.0/24"}},
+					PortForwards: []PeeringEntryPortForward{{ExternalIP: "192.168.2.1", ExternalPort: 443, InternalIP: "10.2.0.10"}},
+				}}},
+			},
+			err: "vpc vpc-2 expose 0: port forwards aren't supported by the dataplane yet",
+		},
+		{
+			name: "translation",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []PeeringEntryExpose{{
+					IPs:         []PeeringEntryIP{{CIDR: "10.2.0.0/24"}},
+					Translation: &PeeringEntryTranslation{Mode: PeeringTranslationModeNAT64},
+				}}},
+			},
+			err: "vpc vpc-2 expose 0: nat64/nat46 translation isn't supported by the dataplane yet",
+		},
+		{
+			name: "metric",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: "10.2.0.0/24"}}, Metric: 10}}},
+			},
+			err: "vpc vpc-2 expose 0: route metrics aren't supported by the dataplane yet",
+		},
+		{
+			name: "qos",
+			entries: map[string]*PeeringEntry{
+				"vpc-1": {
+					Expose: vpc1.Expose,
+					QoS:    &PeeringEntryQoS{Egress: &PeeringQoSPolicy{Bandwidth: "100M"}},
+				},
+				"vpc-2": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: "10.2.0.0/24"}}}}},
+			},
+			err: "vpc vpc-1: qos policies aren't supported by the dataplane yet",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePeeringEntries(context.Background(), kube, "default", tt.entries)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
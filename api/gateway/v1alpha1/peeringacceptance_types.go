@@ -0,0 +1,124 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// PeeringAcceptanceSpec defines the desired state of PeeringAcceptance.
+type PeeringAcceptanceSpec struct {
+	// RequestNamespace is the namespace of the accepted PeeringRequest
+	RequestNamespace string `json:"requestNamespace,omitempty"`
+	// Request is the name of the accepted PeeringRequest
+	Request string `json:"request,omitempty"`
+	// Expose is the list of exposes of the VPC (in the same namespace as the acceptance) requested to peer
+	Expose []PeeringEntryExpose `json:"expose,omitempty"`
+	// Reject explicitly rejects the request instead of accepting it
+	Reject bool `json:"reject,omitempty"`
+}
+
+// PeeringAcceptanceStatus defines the observed state of PeeringAcceptance.
+type PeeringAcceptanceStatus struct {
+	// State is the state of the peering: Pending, Accepted, Rejected, Conflict or Invalid
+	State PeeringRequestState `json:"state,omitempty"`
+	// Message is the human readable reason for the non-accepted state
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=peeracc
+// +kubebuilder:printcolumn:name="RequestNS",type=string,JSONPath=`.spec.requestNamespace`,priority=0
+// +kubebuilder:printcolumn:name="Request",type=string,JSONPath=`.spec.request`,priority=0
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// PeeringAcceptance is the Schema for the peeringacceptances API. It accepts (or rejects) a PeeringRequest from
+// another tenant (namespace) and defines what the requested VPC exposes to it.
+type PeeringAcceptance struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PeeringAcceptanceSpec   `json:"spec,omitempty"`
+	Status PeeringAcceptanceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PeeringAcceptanceList contains a list of PeeringAcceptance.
+type PeeringAcceptanceList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []PeeringAcceptance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PeeringAcceptance{}, &PeeringAcceptanceList{})
+}
+
+func (pa *PeeringAcceptance) Default() {
+	// TODO add defaulting logic
+}
+
+// Accepts returns true if the acceptance is addressed to the request
+func (pa *PeeringAcceptance) Accepts(pr *PeeringRequest) bool {
+	return pa.Namespace == pr.Spec.RemoteNamespace && pa.Spec.RequestNamespace == pr.Namespace && pa.Spec.Request == pr.Name
+}
+
+func (pa *PeeringAcceptance) Validate(ctx context.Context, kube kclient.Reader) error {
+	if pa.Spec.RequestNamespace == "" {
+		return fmt.Errorf("request namespace must be set") //nolint:goerr113
+	}
+	if pa.Spec.Request == "" {
+		return fmt.Errorf("request must be set") //nolint:goerr113
+	}
+	if pa.Spec.Reject && len(pa.Spec.Expose) > 0 {
+		return fmt.Errorf("expose can't be set when rejecting the request") //nolint:goerr113
+	}
+
+	for idx, expose := range pa.Spec.Expose {
+		if err := expose.Validate(); err != nil {
+			return fmt.Errorf("expose %d: %w", idx, err)
+		}
+		if expose.ASPool != nil {
+			return fmt.Errorf("expose %d: asPool is only supported in peerings", idx) //nolint:goerr113
+		}
+	}
+
+	if kube == nil {
+		return nil
+	}
+
+	// the request could be created after the acceptance, the exposes are checked against the pools once it exists
+	req := &PeeringRequest{}
+	if err := kube.Get(ctx, kclient.ObjectKey{Namespace: pa.Spec.RequestNamespace, Name: pa.Spec.Request}, req); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("getting peering request %s/%s: %w", pa.Spec.RequestNamespace, pa.Spec.Request, err)
+	}
+
+	if !pa.Accepts(req) {
+		return fmt.Errorf("peering request %s/%s is addressed to namespace %s", req.Namespace, req.Name, req.Spec.RemoteNamespace) //nolint:goerr113
+	}
+
+	return pa.ValidateExposes(ctx, kube, req)
+}
+
+// ValidateExposes checks the exposes of the acceptance against the VPC requested to peer, it's also called once the
+// request is created after the acceptance and when the controllers pair them
+func (pa *PeeringAcceptance) ValidateExposes(ctx context.Context, kube kclient.Reader, req *PeeringRequest) error {
+	// the requested VPC is always resolved in the acceptance namespace, so a tenant can only expose its own VPCs
+	return ValidatePeeringEntries(ctx, kube, pa.Namespace, map[string]*PeeringEntry{
+		req.Spec.RemoteVPC: {Expose: pa.Spec.Expose},
+	})
+}
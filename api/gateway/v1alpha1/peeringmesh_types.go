@@ -0,0 +1,209 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// PeeringMeshSpec defines the desired state of PeeringMesh.
+type PeeringMeshSpec struct {
+	// Peering is a map of the VPCs (or externals) in the mesh to their exposes, each VPC exposes the same to all
+	// other VPCs in the mesh
+	Peering map[string]*PeeringEntry `json:"peering,omitempty"`
+}
+
+// PeeringMeshPairState is the state of a pairwise peering of the mesh
+type PeeringMeshPairState string
+
+const (
+	// PeeringMeshPairStateActive means the pair is peered
+	PeeringMeshPairStateActive PeeringMeshPairState = "Active"
+	// PeeringMeshPairStatePending means the pair isn't peered yet as one of the VPCs doesn't exist
+	PeeringMeshPairStatePending PeeringMeshPairState = "Pending"
+	// PeeringMeshPairStateConflict means the pair isn't peered by the mesh as it's already peered by a Peering or
+	// another mesh
+	PeeringMeshPairStateConflict PeeringMeshPairState = "Conflict"
+	// PeeringMeshPairStateInvalid means the pair isn't peered as it uses a feature the dataplane doesn't support yet
+	PeeringMeshPairStateInvalid PeeringMeshPairState = "Invalid"
+)
+
+// PeeringMeshStatus defines the observed state of PeeringMesh.
+type PeeringMeshStatus struct {
+	// Pairs is the state of each pairwise peering the mesh is expanded into keyed by the canonical pair name
+	// (VPC names sorted and joined with "--")
+	Pairs map[string]PeeringMeshPairStatus `json:"pairs,omitempty"`
+}
+
+// PeeringMeshPairStatus is the state of a pairwise peering of the mesh
+type PeeringMeshPairStatus struct {
+	// State is the state of the pair: Active, Pending, Conflict or Invalid
+	State PeeringMeshPairState `json:"state,omitempty"`
+	// Message is the human readable reason for the non-active state
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=peermesh
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// PeeringMesh is the Schema for the peeringmeshes API. It's a full mesh of peerings between all of the listed VPCs
+// that's expanded into the pairwise peerings for the gateways.
+type PeeringMesh struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PeeringMeshSpec   `json:"spec,omitempty"`
+	Status PeeringMeshStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PeeringMeshList contains a list of PeeringMesh.
+type PeeringMeshList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []PeeringMesh `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PeeringMesh{}, &PeeringMeshList{})
+}
+
+func (m *PeeringMesh) Default() {
+	if m.Labels == nil {
+		m.Labels = map[string]string{}
+	}
+
+	// drop the labels of the VPCs that aren't in the mesh anymore
+	prefix := ListLabelPrefix("vpc")
+	for label := range m.Labels {
+		if vpcName, ok := strings.CutPrefix(label, prefix); ok {
+			if _, exists := m.Spec.Peering[vpcName]; !exists {
+				delete(m.Labels, label)
+			}
+		}
+	}
+
+	for vpcName := range m.Spec.Peering {
+		m.Labels[ListLabelVPC(vpcName)] = ListLabelValue
+	}
+}
+
+func (m *PeeringMesh) Validate(ctx context.Context, kube kclient.Reader) error {
+	if len(m.Spec.Peering) < 2 {
+		return fmt.Errorf("mesh must have at least 2 VPCs, got %d", len(m.Spec.Peering)) //nolint:goerr113
+	}
+	for vpcName, entry := range m.Spec.Peering {
+		if entry != nil && entry.Transit {
+			return fmt.Errorf("vpc %s: transit isn't supported in meshes, use peerings instead", vpcName) //nolint:goerr113
+		}
+	}
+	if len(m.Pairs()) == 0 {
+		return fmt.Errorf("at least one of the VPCs must expose something") //nolint:goerr113
+	}
+
+	if err := validateNoASPools(m.Spec.Peering); err != nil {
+		return err
+	}
+
+	if err := ValidatePeeringEntries(ctx, kube, m.Namespace, m.Spec.Peering); err != nil {
+		return err
+	}
+
+	if kube == nil {
+		return nil
+	}
+
+	// all VPCs are advertised into the VRF of each VPC in the mesh, so their exposed (or NATed) addresses shouldn't
+	// be ambiguous, externals are skipped as their routes are learned dynamically
+	advertised := map[string]*prefixset.Set{}
+	for _, vpcName := range slices.Sorted(maps.Keys(m.Spec.Peering)) {
+		entry := m.Spec.Peering[vpcName]
+		if entry == nil {
+			continue
+		}
+
+		subnets, isExternal, err := GetPeeredSubnets(ctx, kube, m.Namespace, vpcName)
+		if err != nil {
+			if kapierrors.IsNotFound(err) {
+				continue
+			}
+
+			return err
+		}
+		if isExternal {
+			continue
+		}
+
+		vpcSet := &prefixset.Set{}
+		for idx, expose := range entry.Expose {
+			ips, as, err := expose.Sets(subnets)
+			if err != nil {
+				return fmt.Errorf("vpc %s expose %d: %w", vpcName, idx, err)
+			}
+
+			if as.IsEmpty() {
+				vpcSet = vpcSet.Union(ips)
+			} else {
+				vpcSet = vpcSet.Union(as)
+			}
+		}
+
+		for _, otherName := range slices.Sorted(maps.Keys(advertised)) {
+			if overlap := advertised[otherName].Intersect(vpcSet); !overlap.IsEmpty() {
+				return fmt.Errorf("vpc %s exposed addresses overlap with vpc %s: %s", vpcName, otherName, overlap) //nolint:goerr113
+			}
+		}
+		advertised[vpcName] = vpcSet
+	}
+
+	return nil
+}
+
+// PeeringMeshPair is a pairwise peering the mesh is expanded into
+type PeeringMeshPair struct {
+	// Name is the canonical name of the pair
+	Name string
+	// Spec is the pairwise peering spec
+	Spec PeeringSpec
+}
+
+// Pairs returns the pairwise peerings of the mesh sorted by name, pairs of consume-only VPCs are skipped as there is
+// nothing to peer
+func (m *PeeringMesh) Pairs() []PeeringMeshPair {
+	vpcs := slices.Sorted(maps.Keys(m.Spec.Peering))
+
+	res := []PeeringMeshPair{}
+	for i, vpc1 := range vpcs {
+		for _, vpc2 := range vpcs[i+1:] {
+			if m.Spec.Peering[vpc1].ConsumeOnly() && m.Spec.Peering[vpc2].ConsumeOnly() {
+				continue
+			}
+
+			res = append(res, PeeringMeshPair{
+				Name: PeeringName(vpc1, vpc2),
+				Spec: PeeringSpec{
+					Peering: map[string]*PeeringEntry{
+						vpc1: m.Spec.Peering[vpc1].DeepCopy(),
+						vpc2: m.Spec.Peering[vpc2].DeepCopy(),
+					},
+				},
+			})
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// PeeringPolicySpec defines the desired state of PeeringPolicy.
+type PeeringPolicySpec struct {
+	// Selector picks the VPCs (VPCInfos in the same namespace) to peer with the peer
+	Selector kmetav1.LabelSelector `json:"selector,omitempty"`
+	// Expose is the template of the exposes for each of the selected VPCs
+	Expose []PeeringEntryExpose `json:"expose,omitempty"`
+	// Peer is the name of the VPC or external (e.g. shared services or internet) all selected VPCs are peered with
+	Peer string `json:"peer,omitempty"`
+	// PeerExpose is the template of the exposes for the peer in each of the generated peerings
+	PeerExpose []PeeringEntryExpose `json:"peerExpose,omitempty"`
+}
+
+// PeeringPolicyStatus defines the observed state of PeeringPolicy.
+type PeeringPolicyStatus struct {
+	// Peerings is the list of names of the peerings generated by the policy
+	Peerings []string `json:"peerings,omitempty"`
+	// Conflicts is the list of the selected VPCs the policy can't generate or update a peering for
+	Conflicts []PeeringPolicyConflict `json:"conflicts,omitempty"`
+}
+
+// PeeringPolicyConflict describes a selected VPC the policy can't generate or update a peering for, the peering is
+// kept with its previous spec if only its update is rejected
+type PeeringPolicyConflict struct {
+	// VPC is the name of the selected VPC
+	VPC string `json:"vpc,omitempty"`
+	// Peering is the name of the existing peering between the VPC and the peer not owned by the policy or of the
+	// generated peering rejected by the webhook
+	Peering string `json:"peering,omitempty"`
+	// Message is the human readable reason of the conflict
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=peerpol
+// +kubebuilder:printcolumn:name="Peer",type=string,JSONPath=`.spec.peer`,priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// PeeringPolicy is the Schema for the peeringpolicies API. It generates a Peering between the peer and each VPC
+// matched by the selector and keeps them in sync as matching VPCs come and go.
+type PeeringPolicy struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PeeringPolicySpec   `json:"spec,omitempty"`
+	Status PeeringPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PeeringPolicyList contains a list of PeeringPolicy.
+type PeeringPolicyList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []PeeringPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PeeringPolicy{}, &PeeringPolicyList{})
+}
+
+func (pp *PeeringPolicy) Default() {
+	// TODO add defaulting logic
+}
+
+func (pp *PeeringPolicy) Validate(_ context.Context, _ kclient.Reader) error {
+	if pp.Spec.Peer == "" {
+		return fmt.Errorf("peer must be set") //nolint:goerr113
+	}
+
+	if _, err := kmetav1.LabelSelectorAsSelector(&pp.Spec.Selector); err != nil {
+		return fmt.Errorf("invalid selector: %w", err)
+	}
+
+	for idx, expose := range pp.Spec.Expose {
+		if err := expose.Validate(); err != nil {
+			return fmt.Errorf("expose %d: %w", idx, err)
+		}
+	}
+
+	for idx, expose := range pp.Spec.PeerExpose {
+		if err := expose.Validate(); err != nil {
+			return fmt.Errorf("peer expose %d: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// PeeringFor returns the peering generated by the policy for the VPC
+func (pp *PeeringPolicy) PeeringFor(vpcName string) *Peering {
+	peering := &Peering{
+		ObjectMeta: kmetav1.ObjectMeta{
+			Name:      PeeringName(vpcName, pp.Spec.Peer),
+			Namespace: pp.Namespace,
+			Labels: map[string]string{
+				LabelPeeringPolicy: pp.Name,
+			},
+		},
+		Spec: PeeringSpec{
+			Peering: map[string]*PeeringEntry{
+				vpcName:      {Expose: copyExposes(pp.Spec.Expose)},
+				pp.Spec.Peer: {Expose: copyExposes(pp.Spec.PeerExpose)},
+			},
+		},
+	}
+	peering.Default()
+
+	return peering
+}
+
+func copyExposes(exposes []PeeringEntryExpose) []PeeringEntryExpose {
+	res := make([]PeeringEntryExpose, 0, len(exposes))
+	for _, expose := range exposes {
+		res = append(res, *expose.DeepCopy())
+	}
+
+	return res
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// PeeringRequestSpec defines the desired state of PeeringRequest.
+type PeeringRequestSpec struct {
+	// VPC is the name of the local VPC (in the same namespace as the request) to peer
+	VPC string `json:"vpc,omitempty"`
+	// Expose is the list of exposes of the local VPC
+	Expose []PeeringEntryExpose `json:"expose,omitempty"`
+	// RemoteNamespace is the namespace of the remote tenant that has to accept the request
+	RemoteNamespace string `json:"remoteNamespace,omitempty"`
+	// RemoteVPC is the name of the VPC or external in the remote namespace to peer with
+	RemoteVPC string `json:"remoteVPC,omitempty"`
+}
+
+// PeeringRequestState is the state of a cross-namespace peering
+type PeeringRequestState string
+
+const (
+	// PeeringRequestStatePending means the request isn't accepted yet or one of the VPCs doesn't exist
+	PeeringRequestStatePending PeeringRequestState = "Pending"
+	// PeeringRequestStateAccepted means both sides agreed and the peering is active
+	PeeringRequestStateAccepted PeeringRequestState = "Accepted"
+	// PeeringRequestStateRejected means the remote tenant rejected the request
+	PeeringRequestStateRejected PeeringRequestState = "Rejected"
+	// PeeringRequestStateConflict means the request is accepted but the VPCs are already peered by a Peering, a
+	// PeeringMesh or another PeeringRequest, so it isn't active
+	PeeringRequestStateConflict PeeringRequestState = "Conflict"
+	// PeeringRequestStateInvalid means the request is accepted but the peering can't be configured on the gateways,
+	// e.g. as the exposes of the acceptance are invalid or it uses a feature the dataplane doesn't support yet, so it
+	// isn't active
+	PeeringRequestStateInvalid PeeringRequestState = "Invalid"
+)
+
+// PeeringRequestStatus defines the observed state of PeeringRequest.
+type PeeringRequestStatus struct {
+	// State is the state of the peering: Pending, Accepted, Rejected, Conflict or Invalid
+	State PeeringRequestState `json:"state,omitempty"`
+	// Message is the human readable reason for the non-accepted state
+	Message string `json:"message,omitempty"`
+	// Acceptance is the name of the acceptance in the remote namespace matched with the request
+	Acceptance string `json:"acceptance,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=peerreq
+// +kubebuilder:printcolumn:name="VPC",type=string,JSONPath=`.spec.vpc`,priority=0
+// +kubebuilder:printcolumn:name="RemoteNS",type=string,JSONPath=`.spec.remoteNamespace`,priority=0
+// +kubebuilder:printcolumn:name="RemoteVPC",type=string,JSONPath=`.spec.remoteVPC`,priority=0
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// PeeringRequest is the Schema for the peeringrequests API. It's one side of a peering between the VPCs of two
+// tenants (namespaces) that only becomes active once the remote tenant accepts it with a PeeringAcceptance.
+type PeeringRequest struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PeeringRequestSpec   `json:"spec,omitempty"`
+	Status PeeringRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PeeringRequestList contains a list of PeeringRequest.
+type PeeringRequestList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []PeeringRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PeeringRequest{}, &PeeringRequestList{})
+}
+
+func (pr *PeeringRequest) Default() {
+	// TODO add defaulting logic
+}
+
+func (pr *PeeringRequest) Validate(ctx context.Context, kube kclient.Reader) error {
+	if pr.Spec.VPC == "" {
+		return fmt.Errorf("vpc must be set") //nolint:goerr113
+	}
+	if pr.Spec.RemoteNamespace == "" {
+		return fmt.Errorf("remote namespace must be set") //nolint:goerr113
+	}
+	if pr.Spec.RemoteNamespace == pr.Namespace {
+		return fmt.Errorf("remote namespace must be different from the request namespace, use a peering instead") //nolint:goerr113
+	}
+	if pr.Spec.RemoteVPC == "" {
+		return fmt.Errorf("remote vpc must be set") //nolint:goerr113
+	}
+	if err := validateNoASPools(map[string]*PeeringEntry{pr.Spec.VPC: {Expose: pr.Spec.Expose}}); err != nil {
+		return err
+	}
+
+	// the local VPC is always resolved in the request namespace, so a tenant can only expose its own VPCs
+	if err := ValidatePeeringEntries(ctx, kube, pr.Namespace, map[string]*PeeringEntry{
+		pr.Spec.VPC: {Expose: pr.Spec.Expose},
+	}); err != nil {
+		return err
+	}
+
+	if kube == nil {
+		return nil
+	}
+
+	// the acceptances created before the request couldn't check their exposes against the requested VPC
+	accs := &PeeringAcceptanceList{}
+	if err := kube.List(ctx, accs, kclient.InNamespace(pr.Spec.RemoteNamespace)); err != nil {
+		return fmt.Errorf("listing peering acceptances: %w", err)
+	}
+	for idx := range accs.Items {
+		acc := &accs.Items[idx]
+		if !acc.Accepts(pr) || acc.Spec.Reject {
+			continue
+		}
+		if err := acc.ValidateExposes(ctx, kube, pr); err != nil {
+			return fmt.Errorf("peering acceptance %s/%s: %w", acc.Namespace, acc.Name, err)
+		}
+	}
+
+	return nil
+}
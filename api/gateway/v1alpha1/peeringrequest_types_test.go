@@ -0,0 +1,61 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPeeringRequestValidatePendingAcceptances(t *testing.T) {
+	acc := func(name, subnet string, reject bool) *PeeringAcceptance {
+		acc := &PeeringAcceptance{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-b", Name: name},
+			Spec:       PeeringAcceptanceSpec{RequestNamespace: "tenant-a", Request: "to-b", Reject: reject},
+		}
+		if subnet != "" {
+			acc.Spec.Expose = []PeeringEntryExpose{{IPs: []PeeringEntryIP{{VPCSubnet: subnet}}}}
+		}
+
+		return acc
+	}
+	vpc := func(namespace, name, cidr string) *VPCInfo {
+		return &VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{"subnet-1": {CIDR: cidr}}},
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		acc  *PeeringAcceptance
+		err  string
+	}{
+		{"valid", acc("from-a", "subnet-1", false), ""},
+		{"rejected", acc("from-a", "", true), ""},
+		{"unknown-subnet", acc("from-a", "subnet-2", false), "peering acceptance tenant-b/from-a: vpc vpc-b expose 0: unknown vpc subnet subnet-2"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kube := kubetest.NewReader(vpc("tenant-a", "vpc-a", "10.1.0.0/24"), vpc("tenant-b", "vpc-b", "10.2.0.0/24"), tt.acc)
+			req := &PeeringRequest{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-a", Name: "to-b"},
+				Spec: PeeringRequestSpec{
+					VPC:             "vpc-a",
+					RemoteNamespace: "tenant-b",
+					RemoteVPC:       "vpc-b",
+					Expose:          []PeeringEntryExpose{{IPs: []PeeringEntryIP{{VPCSubnet: "subnet-1"}}}},
+				},
+			}
+			err := req.Validate(t.Context(), kube)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// TenantQuotaSpec defines the desired state of TenantQuota.
+type TenantQuotaSpec struct {
+	// Selector limits the quota to the objects in the namespace with matching labels, all objects are counted if
+	// not set
+	Selector *kmetav1.LabelSelector `json:"selector,omitempty"`
+	// VPCs is the max number of VPCInfos, unlimited if not set
+	VPCs uint32 `json:"vpcs,omitempty"`
+	// Peerings is the max number of peerings (Peerings, pairs of PeeringMeshes, PeeringRequests and
+	// PeeringAcceptances), unlimited if not set
+	Peerings uint32 `json:"peerings,omitempty"`
+	// ExposedPrefixes is the max total number of prefixes exposed by the VPCs of the namespace in all peerings,
+	// unlimited if not set
+	ExposedPrefixes uint32 `json:"exposedPrefixes,omitempty"`
+	// NATAddresses is the max total number of addresses in the "as" NAT pools of the VPCs of the namespace in all
+	// peerings, unlimited if not set
+	NATAddresses uint64 `json:"natAddresses,omitempty"`
+}
+
+// TenantQuotaStatus defines the observed state of TenantQuota.
+type TenantQuotaStatus struct {
+	// Usage is the current usage of the quota
+	Usage TenantQuotaUsage `json:"usage,omitempty"`
+}
+
+// TenantQuotaUsage is the usage of the quota
+type TenantQuotaUsage struct {
+	// VPCs is the number of VPCInfos
+	VPCs uint32 `json:"vpcs,omitempty"`
+	// Peerings is the number of peerings
+	Peerings uint32 `json:"peerings,omitempty"`
+	// ExposedPrefixes is the total number of prefixes exposed by the VPCs
+	ExposedPrefixes uint32 `json:"exposedPrefixes,omitempty"`
+	// NATAddresses is the total number of addresses in the NAT pools of the VPCs, saturated at the max uint64 as the
+	// IPv6 pools could be larger than that
+	NATAddresses uint64 `json:"natAddresses,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=tquota
+// +kubebuilder:printcolumn:name="VPCs",type=string,JSONPath=`.status.usage.vpcs`,priority=0
+// +kubebuilder:printcolumn:name="Peerings",type=string,JSONPath=`.status.usage.peerings`,priority=0
+// +kubebuilder:printcolumn:name="Prefixes",type=string,JSONPath=`.status.usage.exposedPrefixes`,priority=0
+// +kubebuilder:printcolumn:name="NAT",type=string,JSONPath=`.status.usage.natAddresses`,priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// TenantQuota is the Schema for the tenantquotas API. It limits the VPCs, peerings and the address space exposed
+// by a tenant (namespace) on the shared gateways.
+type TenantQuota struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantQuotaSpec   `json:"spec,omitempty"`
+	Status TenantQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TenantQuotaList contains a list of TenantQuota.
+type TenantQuotaList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []TenantQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantQuota{}, &TenantQuotaList{})
+}
+
+func (q *TenantQuota) Default() {
+	// TODO add defaulting logic
+}
+
+func (q *TenantQuota) Validate(_ context.Context, _ kclient.Reader) error {
+	if _, err := q.selector(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (q *TenantQuota) selector() (labels.Selector, error) {
+	if q.Spec.Selector == nil {
+		return labels.Everything(), nil
+	}
+
+	selector, err := kmetav1.LabelSelectorAsSelector(q.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+
+	return selector, nil
+}
+
+// Usage computes the current usage of the quota, if override is set it replaces the object of the same kind and
+// name (or is added if there is none) to get the usage after the object is created or updated
+func (q *TenantQuota) Usage(ctx context.Context, kube kclient.Reader, override kclient.Object) (TenantQuotaUsage, error) {
+	usage := TenantQuotaUsage{}
+
+	selector, err := q.selector()
+	if err != nil {
+		return usage, err
+	}
+
+	matches := func(obj kclient.Object) bool {
+		return selector.Matches(labels.Set(obj.GetLabels()))
+	}
+
+	vpcs := &VPCInfoList{}
+	if err := kube.List(ctx, vpcs, kclient.InNamespace(q.Namespace)); err != nil {
+		return usage, fmt.Errorf("listing vpcs: %w", err)
+	}
+	for _, vpc := range withOverride(vpcs.Items, override) {
+		if matches(&vpc) {
+			usage.VPCs++
+		}
+	}
+
+	// entries are the peering entries of the VPCs of the namespace that count towards the exposed prefixes and NAT
+	entries := []map[string]*PeeringEntry{}
+
+	peerings := &PeeringList{}
+	if err := kube.List(ctx, peerings, kclient.InNamespace(q.Namespace)); err != nil {
+		return usage, fmt.Errorf("listing peerings: %w", err)
+	}
+	for _, peering := range withOverride(peerings.Items, override) {
+		if matches(&peering) {
+			usage.Peerings++
+			entries = append(entries, peering.Spec.Peering)
+		}
+	}
+
+	meshes := &PeeringMeshList{}
+	if err := kube.List(ctx, meshes, kclient.InNamespace(q.Namespace)); err != nil {
+		return usage, fmt.Errorf("listing peering meshes: %w", err)
+	}
+	for _, mesh := range withOverride(meshes.Items, override) {
+		if matches(&mesh) {
+			for _, pair := range mesh.Pairs() {
+				usage.Peerings++
+				entries = append(entries, pair.Spec.Peering)
+			}
+		}
+	}
+
+	reqs := &PeeringRequestList{}
+	if err := kube.List(ctx, reqs, kclient.InNamespace(q.Namespace)); err != nil {
+		return usage, fmt.Errorf("listing peering requests: %w", err)
+	}
+	for _, req := range withOverride(reqs.Items, override) {
+		if matches(&req) {
+			usage.Peerings++
+			entries = append(entries, map[string]*PeeringEntry{req.Spec.VPC: {Expose: req.Spec.Expose}})
+		}
+	}
+
+	accs := &PeeringAcceptanceList{}
+	if err := kube.List(ctx, accs, kclient.InNamespace(q.Namespace)); err != nil {
+		return usage, fmt.Errorf("listing peering acceptances: %w", err)
+	}
+	for _, acc := range withOverride(accs.Items, override) {
+		if !matches(&acc) || acc.Spec.Reject {
+			continue
+		}
+
+		usage.Peerings++
+
+		req := &PeeringRequest{}
+		if err := kube.Get(ctx, kclient.ObjectKey{Namespace: acc.Spec.RequestNamespace, Name: acc.Spec.Request}, req); err != nil {
+			if kapierrors.IsNotFound(err) {
+				continue
+			}
+
+			return usage, fmt.Errorf("getting peering request %s/%s: %w", acc.Spec.RequestNamespace, acc.Spec.Request, err)
+		}
+		entries = append(entries, map[string]*PeeringEntry{req.Spec.RemoteVPC: {Expose: acc.Spec.Expose}})
+	}
+
+	// the IPv6 pools alone could be larger than uint64, so the addresses are summed up as big ints and saturated
+	natAddresses := new(big.Int)
+	subnets := map[string]map[string][]string{}
+	for _, entry := range entries {
+		for vpcName, vpcEntry := range entry {
+			if vpcEntry == nil {
+				continue
+			}
+
+			vpcSubnets, ok := subnets[vpcName]
+			if !ok {
+				var isExternal bool
+				vpcSubnets, isExternal, err = GetPeeredSubnets(ctx, kube, q.Namespace, vpcName)
+				if err != nil && !kapierrors.IsNotFound(err) {
+					return usage, err
+				}
+				// externals and VPCs of other namespaces don't count towards the quota
+				if err != nil || isExternal {
+					vpcSubnets = nil
+				}
+				subnets[vpcName] = vpcSubnets
+			}
+			if vpcSubnets == nil {
+				continue
+			}
+
+			for _, expose := range vpcEntry.Expose {
+				ips, as, err := expose.Sets(vpcSubnets)
+				if err != nil {
+					// invalid exposes are rejected by the validation anyway
+					continue
+				}
+
+				switch {
+				case expose.ASPool != nil:
+					usage.ExposedPrefixes++
+					natAddresses.Add(natAddresses, ips.Size())
+				case as.IsEmpty():
+					usage.ExposedPrefixes += uint32(len(ips.Prefixes())) //nolint:gosec
+				default:
+					usage.ExposedPrefixes += uint32(len(as.Prefixes())) //nolint:gosec
+					natAddresses.Add(natAddresses, as.Size())
+				}
+			}
+		}
+	}
+
+	usage.NATAddresses = math.MaxUint64
+	if natAddresses.IsUint64() {
+		usage.NATAddresses = natAddresses.Uint64()
+	}
+
+	return usage, nil
+}
+
+// Exceeded returns an error for the first limit of the quota exceeded by the usage if it has grown compared to the
+// current usage, so objects could still be updated or deleted if the quota is lowered below the current usage
+func (q *TenantQuota) Exceeded(current, usage TenantQuotaUsage) error {
+	if q.Spec.VPCs > 0 && usage.VPCs > q.Spec.VPCs && usage.VPCs > current.VPCs {
+		return fmt.Errorf("quota %s exceeded: %d vpcs, max %d", q.Name, usage.VPCs, q.Spec.VPCs) //nolint:goerr113
+	}
+	if q.Spec.Peerings > 0 && usage.Peerings > q.Spec.Peerings && usage.Peerings > current.Peerings {
+		return fmt.Errorf("quota %s exceeded: %d peerings, max %d", q.Name, usage.Peerings, q.Spec.Peerings) //nolint:goerr113
+	}
+	if q.Spec.ExposedPrefixes > 0 && usage.ExposedPrefixes > q.Spec.ExposedPrefixes && usage.ExposedPrefixes > current.ExposedPrefixes {
+		return fmt.Errorf("quota %s exceeded: %d exposed prefixes, max %d", q.Name, usage.ExposedPrefixes, q.Spec.ExposedPrefixes) //nolint:goerr113
+	}
+	if q.Spec.NATAddresses > 0 && usage.NATAddresses > q.Spec.NATAddresses && usage.NATAddresses > current.NATAddresses {
+		return fmt.Errorf("quota %s exceeded: %d NAT addresses, max %d", q.Name, usage.NATAddresses, q.Spec.NATAddresses) //nolint:goerr113
+	}
+
+	return nil
+}
+
+// EnforceTenantQuotas checks that creating or updating the object doesn't exceed any of the quotas in its namespace
+func EnforceTenantQuotas(ctx context.Context, kube kclient.Reader, obj kclient.Object) error {
+	quotas := &TenantQuotaList{}
+	if err := kube.List(ctx, quotas, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		return fmt.Errorf("listing tenant quotas: %w", err)
+	}
+
+	for _, quota := range quotas.Items {
+		current, err := quota.Usage(ctx, kube, nil)
+		if err != nil {
+			return fmt.Errorf("computing quota %s usage: %w", quota.Name, err)
+		}
+
+		usage, err := quota.Usage(ctx, kube, obj)
+		if err != nil {
+			return fmt.Errorf("computing quota %s usage: %w", quota.Name, err)
+		}
+
+		if err := quota.Exceeded(current, usage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withOverride returns the items with the one of the same type and name as the override replaced by it (or with it
+// appended if there is none)
+func withOverride[T any, PT interface {
+	*T
+	kclient.Object
+}](items []T, override kclient.Object) []T {
+	over, ok := override.(PT)
+	if !ok {
+		return items
+	}
+
+	res := make([]T, 0, len(items)+1)
+	for idx := range items {
+		if PT(&items[idx]).GetName() != over.GetName() {
+			res = append(res, items[idx])
+		}
+	}
+
+	return append(res, *over)
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTenantQuotaExceeded(t *testing.T) {
+	q := &TenantQuota{Spec: TenantQuotaSpec{VPCs: 2, NATAddresses: 256}}
+
+	for _, tt := range []struct {
+		name    string
+		current TenantQuotaUsage
+		usage   TenantQuotaUsage
+		err     bool
+	}{
+		{"within", TenantQuotaUsage{VPCs: 1}, TenantQuotaUsage{VPCs: 2, NATAddresses: 256}, false},
+		{"vpcs", TenantQuotaUsage{VPCs: 2}, TenantQuotaUsage{VPCs: 3}, true},
+		{"nat", TenantQuotaUsage{}, TenantQuotaUsage{NATAddresses: 512}, true},
+		{"unlimited-peerings", TenantQuotaUsage{}, TenantQuotaUsage{Peerings: 1000}, false},
+		{"lowered-not-grown", TenantQuotaUsage{VPCs: 5}, TenantQuotaUsage{VPCs: 5}, false},
+		{"lowered-shrinking", TenantQuotaUsage{VPCs: 5}, TenantQuotaUsage{VPCs: 4}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := q.Exceeded(tt.current, tt.usage)
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTenantQuotaUsage(t *testing.T) {
+	vpcs := []kclient.Object{
+		&VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-1"},
+			Spec: VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{
+				"subnet-1": {CIDR: "10.1.0.0/24", CIDRs: []string{"fd00:1::/64"}},
+			}},
+		},
+		&VPCInfo{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-2"},
+			Spec:       VPCInfoSpec{Subnets: map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.2.0.0/24"}}},
+		},
+	}
+	expose := func(as ...string) *PeeringEntry {
+		entry := &PeeringEntry{Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}}
+		for _, cidr := range as {
+			entry.Expose[0].As = append(entry.Expose[0].As, PeeringEntryAs{CIDR: cidr})
+		}
+
+		return entry
+	}
+	peering := func(name string, vpc1, vpc2 *PeeringEntry) *Peering {
+		return &Peering{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       PeeringSpec{Peering: map[string]*PeeringEntry{"vpc-1": vpc1, "vpc-2": vpc2}},
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		peerings []kclient.Object
+		expected TenantQuotaUsage
+	}{
+		{"no-nat", []kclient.Object{peering("p-1", expose(), expose())}, TenantQuotaUsage{VPCs: 2, Peerings: 1, ExposedPrefixes: 3}},
+		{"ipv4-nat", []kclient.Object{
+			peering("p-1", expose(), expose("192.168.0.0/24")),
+			peering("p-2", expose(), expose("192.168.1.0/24")),
+		}, TenantQuotaUsage{VPCs: 2, Peerings: 2, ExposedPrefixes: 6, NATAddresses: 512}},
+		{"ipv6-nat-saturated", []kclient.Object{
+			peering("p-1", expose("192.168.0.0/24", "fd01::/64"), expose()),
+		}, TenantQuotaUsage{VPCs: 2, Peerings: 1, ExposedPrefixes: 3, NATAddresses: math.MaxUint64}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &TenantQuota{ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "quota"}}
+			usage, err := q.Usage(context.Background(), kubetest.NewReader(append(slices.Clone(vpcs), tt.peerings...)...), nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, usage)
+		})
+	}
+}
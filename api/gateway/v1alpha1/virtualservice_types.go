@@ -0,0 +1,341 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// VirtualServiceSpec defines the desired state of VirtualService.
+type VirtualServiceSpec struct {
+	// VPC is the name of the serving VPC (VPCInfo in the same namespace) the backends are running in
+	VPC string `json:"vpc,omitempty"`
+	// VIP is the virtual IP of the service, it has to be in the "as" pool of an expose of the VPC in at least one of
+	// its peerings, the service is reachable from the other side of all such peerings
+	VIP string `json:"vip,omitempty"`
+	// Protocol is the protocol of the service: tcp or udp, tcp by default
+	Protocol string `json:"protocol,omitempty"`
+	// Port is the port of the service on the VIP
+	Port uint16 `json:"port,omitempty"`
+	// Backends is the pool of the backends the flows are distributed across using consistent hashing, backends have
+	// to be in the "ips" of the expose the VIP belongs to
+	Backends []VirtualServiceBackend `json:"backends,omitempty"`
+	// HealthCheck defines how the health of the backends is checked, only healthy backends get new flows
+	HealthCheck VirtualServiceHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// VirtualServiceBackend is a backend of the virtual service
+type VirtualServiceBackend struct {
+	// IP is the address of the backend inside the VPC
+	IP string `json:"ip,omitempty"`
+	// Port is the port of the backend, same as the port of the service if not set
+	Port uint16 `json:"port,omitempty"`
+}
+
+// VirtualServiceHealthCheck defines the health check of the backends
+type VirtualServiceHealthCheck struct {
+	// Interval is the time between the checks, 5s by default
+	Interval *kmetav1.Duration `json:"interval,omitempty"`
+	// Timeout is the time to wait for a check to succeed, 2s by default
+	Timeout *kmetav1.Duration `json:"timeout,omitempty"`
+	// HealthyThreshold is the number of consecutive successful checks to consider a backend healthy, 2 by default
+	HealthyThreshold uint32 `json:"healthyThreshold,omitempty"`
+	// UnhealthyThreshold is the number of consecutive failed checks to consider a backend unhealthy, 3 by default
+	UnhealthyThreshold uint32 `json:"unhealthyThreshold,omitempty"`
+	// Port is the port to check, the port of the backend if not set
+	Port uint16 `json:"port,omitempty"`
+}
+
+// VirtualServiceBackendHealth is the health of a backend
+type VirtualServiceBackendHealth string
+
+const (
+	// VirtualServiceBackendHealthy means the backend passes the health checks and gets new flows
+	VirtualServiceBackendHealthy VirtualServiceBackendHealth = "Healthy"
+	// VirtualServiceBackendUnhealthy means the backend fails the health checks and doesn't get new flows
+	VirtualServiceBackendUnhealthy VirtualServiceBackendHealth = "Unhealthy"
+	// VirtualServiceBackendUnknown means the health of the backend isn't reported yet
+	VirtualServiceBackendUnknown VirtualServiceBackendHealth = "Unknown"
+)
+
+// VirtualServiceStatus defines the observed state of VirtualService.
+type VirtualServiceStatus struct {
+	// Error is the human readable reason the service isn't reachable, if any
+	Error string `json:"error,omitempty"`
+	// Peerings is the list of the peerings exposing the VIP of the service
+	Peerings []string `json:"peerings,omitempty"`
+	// Backends is the health of each of the backends as reported by the gateways
+	Backends []VirtualServiceBackendStatus `json:"backends,omitempty"`
+	// HealthyBackends is the number of healthy backends
+	HealthyBackends int `json:"healthyBackends,omitempty"`
+}
+
+// VirtualServiceBackendStatus is the health of a backend
+type VirtualServiceBackendStatus struct {
+	// Backend is the address and port of the backend
+	Backend string `json:"backend,omitempty"`
+	// Health is the health of the backend: Healthy, Unhealthy or Unknown, it's the worst one reported by the gateways
+	Health VirtualServiceBackendHealth `json:"health,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=vsvc
+// +kubebuilder:printcolumn:name="VPC",type=string,JSONPath=`.spec.vpc`,priority=0
+// +kubebuilder:printcolumn:name="VIP",type=string,JSONPath=`.spec.vip`,priority=0
+// +kubebuilder:printcolumn:name="Port",type=string,JSONPath=`.spec.port`,priority=0
+// +kubebuilder:printcolumn:name="Healthy",type=string,JSONPath=`.status.healthyBackends`,priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
+// VirtualService is the Schema for the virtualservices API. It's an L4 load balanced virtual IP exposed through the
+// peerings of the serving VPC with the flows distributed across its healthy backends. It's rejected until load
+// balancing is supported by the dataplane.
+type VirtualService struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualServiceSpec   `json:"spec,omitempty"`
+	Status VirtualServiceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VirtualServiceList contains a list of VirtualService.
+type VirtualServiceList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []VirtualService `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VirtualService{}, &VirtualServiceList{})
+}
+
+func (vs *VirtualService) Default() {
+	if vs.Labels == nil {
+		vs.Labels = map[string]string{}
+	}
+
+	// drop the label of the previous VPC if it's changed
+	prefix := ListLabelPrefix("vpc")
+	for label := range vs.Labels {
+		if vpcName, ok := strings.CutPrefix(label, prefix); ok && vpcName != vs.Spec.VPC {
+			delete(vs.Labels, label)
+		}
+	}
+	if vs.Spec.VPC != "" {
+		vs.Labels[ListLabelVPC(vs.Spec.VPC)] = ListLabelValue
+	}
+
+	if vs.Spec.Protocol == "" {
+		vs.Spec.Protocol = PortForwardProtocolTCP
+	}
+	for idx := range vs.Spec.Backends {
+		if vs.Spec.Backends[idx].Port == 0 {
+			vs.Spec.Backends[idx].Port = vs.Spec.Port
+		}
+	}
+
+	hc := &vs.Spec.HealthCheck
+	if hc.Interval == nil {
+		hc.Interval = &kmetav1.Duration{Duration: 5 * time.Second}
+	}
+	if hc.Timeout == nil {
+		hc.Timeout = &kmetav1.Duration{Duration: 2 * time.Second}
+	}
+	if hc.HealthyThreshold == 0 {
+		hc.HealthyThreshold = 2
+	}
+	if hc.UnhealthyThreshold == 0 {
+		hc.UnhealthyThreshold = 3
+	}
+}
+
+// Validate checks the virtual service and rejects it afterwards as the VIPs can't be programmed until load balancing
+// is supported by the dataplane
+func (vs *VirtualService) Validate(ctx context.Context, kube kclient.Reader) error {
+	if err := vs.validate(ctx, kube); err != nil {
+		return err
+	}
+
+	return vs.Spec.DataplaneUnsupported()
+}
+
+// DataplaneUnsupported returns the reason the virtual service can't be configured on the gateways yet, the ones
+// accepted before are only skipped by the gateway controller and reported in their status
+func (vs *VirtualServiceSpec) DataplaneUnsupported() error {
+	return fmt.Errorf("virtual services aren't supported by the dataplane yet") //nolint:goerr113
+}
+
+func (vs *VirtualService) validate(ctx context.Context, kube kclient.Reader) error {
+	if vs.Spec.VPC == "" {
+		return fmt.Errorf("vpc must be set") //nolint:goerr113
+	}
+	if err := vs.PortForward().Validate(); err != nil {
+		return err
+	}
+	if len(vs.Spec.Backends) == 0 {
+		return fmt.Errorf("at least one backend must be set") //nolint:goerr113
+	}
+
+	backends := map[string]bool{}
+	for _, backend := range vs.Spec.Backends {
+		addr, err := netip.ParseAddr(backend.IP)
+		if err != nil {
+			return fmt.Errorf("invalid backend IP %s: %w", backend.IP, err)
+		}
+
+		key := netip.AddrPortFrom(addr, backend.Port).String()
+		if backends[key] {
+			return fmt.Errorf("duplicate backend %s", key) //nolint:goerr113
+		}
+		backends[key] = true
+	}
+
+	hc := vs.Spec.HealthCheck
+	if hc.Interval != nil && hc.Interval.Duration <= 0 {
+		return fmt.Errorf("health check interval must be positive") //nolint:goerr113
+	}
+	if hc.Timeout != nil && hc.Timeout.Duration <= 0 {
+		return fmt.Errorf("health check timeout must be positive") //nolint:goerr113
+	}
+	if hc.Interval != nil && hc.Timeout != nil && hc.Timeout.Duration > hc.Interval.Duration {
+		return fmt.Errorf("health check timeout must not be longer than the interval") //nolint:goerr113
+	}
+
+	if kube == nil {
+		return nil
+	}
+
+	subnets, isExternal, err := GetPeeredSubnets(ctx, kube, vs.Namespace, vs.Spec.VPC)
+	if err != nil {
+		if kapierrors.IsNotFound(err) {
+			return fmt.Errorf("vpc %s not found", vs.Spec.VPC) //nolint:goerr113
+		}
+
+		return err
+	}
+	if isExternal {
+		return fmt.Errorf("%s must be a vpc, not an external", vs.Spec.VPC) //nolint:goerr113
+	}
+
+	peerings := &PeeringList{}
+	if err := kube.List(ctx, peerings, kclient.InNamespace(vs.Namespace), kclient.MatchingLabels{
+		ListLabelVPC(vs.Spec.VPC): ListLabelValue,
+	}); err != nil {
+		return fmt.Errorf("listing peerings for vpc %s: %w", vs.Spec.VPC, err)
+	}
+
+	exposing, err := vs.ExposingPeerings(peerings.Items, subnets)
+	if err != nil {
+		return err
+	}
+	if len(exposing) == 0 {
+		return fmt.Errorf("vip %s isn't in the as pool of any expose of vpc %s", vs.Spec.VIP, vs.Spec.VPC) //nolint:goerr113
+	}
+
+	// the VIP port could be used by a port forward or another virtual service of the same VPC
+	key, err := vs.PortForward().Key()
+	if err != nil {
+		return err
+	}
+	for _, peering := range peerings.Items {
+		for _, expose := range peering.Spec.Peering[vs.Spec.VPC].Expose {
+			for _, pf := range expose.PortForwards {
+				if other, err := pf.Key(); err == nil && other == key {
+					return fmt.Errorf("%s is already used by a port forward in peering %s", key, peering.Name) //nolint:goerr113
+				}
+			}
+		}
+	}
+
+	services := &VirtualServiceList{}
+	if err := kube.List(ctx, services, kclient.InNamespace(vs.Namespace), kclient.MatchingLabels{
+		ListLabelVPC(vs.Spec.VPC): ListLabelValue,
+	}); err != nil {
+		return fmt.Errorf("listing virtual services for vpc %s: %w", vs.Spec.VPC, err)
+	}
+	for _, other := range services.Items {
+		if otherKey, err := other.PortForward().Key(); err == nil && other.Name != vs.Name && otherKey == key {
+			return fmt.Errorf("%s is already used by virtual service %s", key, other.Name) //nolint:goerr113
+		}
+	}
+
+	return nil
+}
+
+// PortForward returns the VIP, protocol and port of the service as a port forward to the first backend to reuse
+// its validation and key
+func (vs *VirtualService) PortForward() *PeeringEntryPortForward {
+	pf := &PeeringEntryPortForward{
+		Protocol:     vs.Spec.Protocol,
+		ExternalIP:   vs.Spec.VIP,
+		ExternalPort: vs.Spec.Port,
+		InternalIP:   vs.Spec.VIP,
+	}
+	if len(vs.Spec.Backends) > 0 {
+		pf.InternalIP = vs.Spec.Backends[0].IP
+		pf.InternalPort = vs.Spec.Backends[0].Port
+	}
+
+	return pf
+}
+
+// ExposingPeerings returns the names of the peerings that expose the VIP of the service in the "as" pool of an
+// expose of the VPC, it fails if the backends aren't in the "ips" of such an expose. Subnets are the subnets of the
+// VPC (name to CIDRs).
+func (vs *VirtualService) ExposingPeerings(peerings []Peering, subnets map[string][]string) ([]string, error) {
+	vip, err := netip.ParseAddr(vs.Spec.VIP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vip %s: %w", vs.Spec.VIP, err)
+	}
+
+	res := []string{}
+	for _, peering := range peerings {
+		entry := peering.Spec.Peering[vs.Spec.VPC]
+		if entry == nil {
+			continue
+		}
+
+		for _, expose := range entry.Expose {
+			ips, as, err := expose.Sets(subnets)
+			if err != nil || !as.Contains(vip) {
+				continue
+			}
+
+			if err := vs.validateBackends(ips); err != nil {
+				return nil, fmt.Errorf("peering %s: %w", peering.Name, err)
+			}
+			res = append(res, peering.Name)
+
+			break
+		}
+	}
+
+	return res, nil
+}
+
+func (vs *VirtualService) validateBackends(ips *prefixset.Set) error {
+	for _, backend := range vs.Spec.Backends {
+		addr, err := netip.ParseAddr(backend.IP)
+		if err != nil {
+			return fmt.Errorf("invalid backend IP %s: %w", backend.IP, err)
+		}
+		if !ips.Contains(addr) {
+			return fmt.Errorf("backend %s isn't in the ips of the expose with the vip", backend.IP) //nolint:goerr113
+		}
+	}
+
+	return nil
+}
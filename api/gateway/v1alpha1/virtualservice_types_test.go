@@ -0,0 +1,80 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVirtualServiceExposingPeerings(t *testing.T) {
+	vs := &VirtualService{
+		Spec: VirtualServiceSpec{
+			VPC:      "vpc-1",
+			VIP:      "192.168.1.10",
+			Port:     443,
+			Backends: []VirtualServiceBackend{{IP: "10.1.1.25"}, {IP: "10.1.1.26"}},
+		},
+	}
+	vs.Default()
+	require.NoError(t, vs.validate(t.Context(), nil))
+	require.Equal(t, uint16(443), vs.Spec.Backends[1].Port)
+
+	peering := func(name, ips, as string) Peering {
+		return Peering{
+			ObjectMeta: kmetav1.ObjectMeta{Name: name},
+			Spec: PeeringSpec{Peering: map[string]*PeeringEntry{
+				"vpc-1": {Expose: []PeeringEntryExpose{{IPs: []PeeringEntryIP{{CIDR: ips}}, As: []PeeringEntryAs{{CIDR: as}}}}},
+				"vpc-2": {},
+			}},
+		}
+	}
+
+	exposing, err := vs.ExposingPeerings([]Peering{
+		peering("vpc-1--vpc-2", "10.1.1.0/24", "192.168.1.0/24"),
+		peering("vpc-1--vpc-3", "10.1.1.0/24", "192.168.2.0/24"),
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"vpc-1--vpc-2"}, exposing)
+
+	_, err = vs.ExposingPeerings([]Peering{peering("vpc-1--vpc-2", "10.1.1.0/28", "192.168.1.0/28")}, nil)
+	require.Error(t, err, "backends outside of the expose ips")
+}
+
+func TestVirtualServiceValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		spec VirtualServiceSpec
+		err  string
+	}{
+		{
+			name: "no-backends",
+			spec: VirtualServiceSpec{VPC: "vpc-1", VIP: "192.168.1.10", Protocol: "tcp", Port: 443},
+			err:  "at least one backend must be set",
+		},
+		{
+			name: "duplicate-backend",
+			spec: VirtualServiceSpec{
+				VPC: "vpc-1", VIP: "192.168.1.10", Protocol: "tcp", Port: 443,
+				Backends: []VirtualServiceBackend{{IP: "10.1.1.25", Port: 8443}, {IP: "10.1.1.25", Port: 8443}},
+			},
+			err: "duplicate backend 10.1.1.25:8443",
+		},
+		{
+			name: "valid",
+			spec: VirtualServiceSpec{
+				VPC: "vpc-1", VIP: "192.168.1.10", Protocol: "tcp", Port: 443,
+				Backends: []VirtualServiceBackend{{IP: "10.1.1.25", Port: 8443}},
+			},
+			err: "virtual services aren't supported by the dataplane yet",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := &VirtualService{Spec: tt.spec}
+			require.EqualError(t, vs.Validate(t.Context(), nil), tt.err)
+		})
+	}
+}
@@ -5,7 +5,12 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
 
+	"go.githedgehog.com/gateway/pkg/prefixset"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -21,8 +26,10 @@ type VPCInfoSpec struct {
 }
 
 type VPCInfoSubnet struct {
-	// CIDR is the subnet CIDR block, such as "10.0.0.0/24"
+	// CIDR is the subnet CIDR block, IPv4 or IPv6, such as "10.0.0.0/24" or "fd00:10::/64"
 	CIDR string `json:"cidr,omitempty"`
+	// CIDRs is the list of the additional CIDR blocks of the subnet, IPv4 or IPv6, for the dual-stack subnets
+	CIDRs []string `json:"cidrs,omitempty"`
 }
 
 // VPCInfoStatus defines the observed state of VPCInfo.
@@ -61,11 +68,68 @@ func (vpc *VPCInfo) IsReady() bool {
 	return vpc.Status.InternalID != ""
 }
 
+// AllCIDRs returns the primary and the additional CIDRs of the subnet
+func (s *VPCInfoSubnet) AllCIDRs() []string {
+	res := []string{}
+	if s.CIDR != "" {
+		res = append(res, s.CIDR)
+	}
+
+	return append(res, s.CIDRs...)
+}
+
 func (vpc *VPCInfo) Default() {
 	// TODO add defaulting logic
 }
 
-func (vpc *VPCInfo) Validate(_ context.Context, _ kclient.Reader) error {
-	// TODO add validation logic
+func (vpc *VPCInfo) Validate(ctx context.Context, kube kclient.Reader) error {
+	all := &prefixset.Set{}
+	for _, name := range slices.Sorted(maps.Keys(vpc.Spec.Subnets)) {
+		subnet := vpc.Spec.Subnets[name]
+		if subnet == nil || len(subnet.AllCIDRs()) == 0 {
+			return fmt.Errorf("subnet %s has no cidrs", name) //nolint:goerr113
+		}
+
+		for _, cidr := range subnet.AllCIDRs() {
+			prefix, err := netip.ParsePrefix(cidr)
+			if err != nil {
+				return fmt.Errorf("subnet %s: invalid cidr %s: %w", name, cidr, err)
+			}
+			if prefix.Masked() != prefix {
+				return fmt.Errorf("subnet %s: cidr %s is not a network address", name, cidr) //nolint:goerr113
+			}
+
+			set := prefixset.New(prefix)
+			if all.Overlaps(set) {
+				return fmt.Errorf("subnet %s: cidr %s overlaps with other cidrs of the vpc", name, cidr) //nolint:goerr113
+			}
+			all = all.Union(set)
+		}
+	}
+
+	if kube != nil {
+		// VPCs of all tenants (namespaces) and the externals end up in the same gateway config keyed by name, so the
+		// names have to be unique across all namespaces
+		exts := &ExternalList{}
+		if err := kube.List(ctx, exts); err != nil {
+			return fmt.Errorf("listing externals: %w", err)
+		}
+		for _, ext := range exts.Items {
+			if ext.Name == vpc.Name {
+				return fmt.Errorf("external with the same name %s already exists in namespace %s", vpc.Name, ext.Namespace) //nolint:goerr113
+			}
+		}
+
+		vpcs := &VPCInfoList{}
+		if err := kube.List(ctx, vpcs); err != nil {
+			return fmt.Errorf("listing vpcs: %w", err)
+		}
+		for _, other := range vpcs.Items {
+			if other.Name == vpc.Name && other.Namespace != vpc.Namespace {
+				return fmt.Errorf("vpc with the same name %s already exists in namespace %s", vpc.Name, other.Namespace) //nolint:goerr113
+			}
+		}
+	}
+
 	return nil
 }
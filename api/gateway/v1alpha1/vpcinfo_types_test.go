@@ -0,0 +1,79 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVPCInfoValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		subnets map[string]*VPCInfoSubnet
+		err     bool
+	}{
+		{"ipv4", map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.0.1.0/24"}}, false},
+		{"ipv6", map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "fd00:1::/64"}}, false},
+		{"dual-stack", map[string]*VPCInfoSubnet{
+			"subnet-1": {CIDR: "10.0.1.0/24", CIDRs: []string{"fd00:1::/64"}},
+			"subnet-2": {CIDRs: []string{"10.0.2.0/24", "10.0.3.0/24", "fd00:2::/64"}},
+		}, false},
+		{"no-cidrs", map[string]*VPCInfoSubnet{"subnet-1": {}}, true},
+		{"nil", map[string]*VPCInfoSubnet{"subnet-1": nil}, true},
+		{"invalid", map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.0.1.0/24", CIDRs: []string{"fd00:1::"}}}, true},
+		{"not-masked", map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "fd00:1::1/64"}}, true},
+		{"overlap-subnet", map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "fd00:1::/64", CIDRs: []string{"fd00:1::/80"}}}, true},
+		{"overlap-vpc", map[string]*VPCInfoSubnet{
+			"subnet-1": {CIDR: "10.0.1.0/24"},
+			"subnet-2": {CIDR: "10.0.0.0/16"},
+		}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			vpc := &VPCInfo{Spec: VPCInfoSpec{Subnets: tt.subnets}}
+			err := vpc.Validate(context.Background(), nil)
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVPCInfoValidateNames(t *testing.T) {
+	kube := kubetest.NewReader(
+		&VPCInfo{ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-a", Name: "vpc-1"}},
+		&External{ObjectMeta: kmetav1.ObjectMeta{Namespace: "fab", Name: "ext-1"}},
+	)
+	subnets := map[string]*VPCInfoSubnet{"subnet-1": {CIDR: "10.0.1.0/24"}}
+
+	for _, tt := range []struct {
+		name      string
+		namespace string
+		err       string
+	}{
+		{"vpc-1", "tenant-a", ""},
+		{"vpc-2", "tenant-b", ""},
+		{"vpc-1", "tenant-b", "vpc with the same name vpc-1 already exists in namespace tenant-a"},
+		{"ext-1", "tenant-b", "external with the same name ext-1 already exists in namespace fab"},
+	} {
+		t.Run(tt.namespace+"/"+tt.name, func(t *testing.T) {
+			vpc := &VPCInfo{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: tt.namespace, Name: tt.name},
+				Spec:       VPCInfoSpec{Subnets: subnets},
+			}
+			err := vpc.Validate(context.Background(), kube)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
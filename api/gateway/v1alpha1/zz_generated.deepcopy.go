@@ -8,6 +8,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -92,6 +93,192 @@ func (in *AlloyTarget) DeepCopy() *AlloyTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *External) DeepCopyInto(out *External) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new External.
+func (in *External) DeepCopy() *External {
+	if in == nil {
+		return nil
+	}
+	out := new(External)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *External) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalBGPNeighbor) DeepCopyInto(out *ExternalBGPNeighbor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalBGPNeighbor.
+func (in *ExternalBGPNeighbor) DeepCopy() *ExternalBGPNeighbor {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalBGPNeighbor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalList) DeepCopyInto(out *ExternalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]External, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalList.
+func (in *ExternalList) DeepCopy() *ExternalList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExternalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalNextHopMonitor) DeepCopyInto(out *ExternalNextHopMonitor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalNextHopMonitor.
+func (in *ExternalNextHopMonitor) DeepCopy() *ExternalNextHopMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalNextHopMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalPrefixFilter) DeepCopyInto(out *ExternalPrefixFilter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalPrefixFilter.
+func (in *ExternalPrefixFilter) DeepCopy() *ExternalPrefixFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalPrefixFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSpec) DeepCopyInto(out *ExternalSpec) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Neighbors != nil {
+		in, out := &in.Neighbors, &out.Neighbors
+		*out = make([]ExternalBGPNeighbor, len(*in))
+		copy(*out, *in)
+	}
+	if in.StaticRoutes != nil {
+		in, out := &in.StaticRoutes, &out.StaticRoutes
+		*out = make([]ExternalStaticRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = make([]ExternalPrefixFilter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = make([]ExternalPrefixFilter, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSpec.
+func (in *ExternalSpec) DeepCopy() *ExternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalStaticRoute) DeepCopyInto(out *ExternalStaticRoute) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Monitor != nil {
+		in, out := &in.Monitor, &out.Monitor
+		*out = new(ExternalNextHopMonitor)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStaticRoute.
+func (in *ExternalStaticRoute) DeepCopy() *ExternalStaticRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalStaticRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalStatus) DeepCopyInto(out *ExternalStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStatus.
+func (in *ExternalStatus) DeepCopy() *ExternalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Gateway) DeepCopyInto(out *Gateway) {
 	*out = *in
@@ -134,6 +321,41 @@ func (in *GatewayBGPNeighbor) DeepCopy() *GatewayBGPNeighbor {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayFlowCollector) DeepCopyInto(out *GatewayFlowCollector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayFlowCollector.
+func (in *GatewayFlowCollector) DeepCopy() *GatewayFlowCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayFlowCollector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayFlowExport) DeepCopyInto(out *GatewayFlowExport) {
+	*out = *in
+	if in.Collectors != nil {
+		in, out := &in.Collectors, &out.Collectors
+		*out = make([]GatewayFlowCollector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayFlowExport.
+func (in *GatewayFlowExport) DeepCopy() *GatewayFlowExport {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayFlowExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayInterface) DeepCopyInto(out *GatewayInterface) {
 	*out = *in
@@ -202,6 +424,11 @@ func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 		copy(*out, *in)
 	}
 	in.Alloy.DeepCopyInto(&out.Alloy)
+	if in.FlowExport != nil {
+		in, out := &in.FlowExport, &out.FlowExport
+		*out = new(GatewayFlowExport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
@@ -230,7 +457,7 @@ func (in *GatewayStatus) DeepCopy() *GatewayStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Peering) DeepCopyInto(out *Peering) {
+func (in *MirrorSession) DeepCopyInto(out *MirrorSession) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -238,18 +465,18 @@ func (in *Peering) DeepCopyInto(out *Peering) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Peering.
-func (in *Peering) DeepCopy() *Peering {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorSession.
+func (in *MirrorSession) DeepCopy() *MirrorSession {
 	if in == nil {
 		return nil
 	}
-	out := new(Peering)
+	out := new(MirrorSession)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Peering) DeepCopyObject() runtime.Object {
+func (in *MirrorSession) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -257,108 +484,71 @@ func (in *Peering) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PeeringEntry) DeepCopyInto(out *PeeringEntry) {
-	*out = *in
-	if in.Expose != nil {
-		in, out := &in.Expose, &out.Expose
-		*out = make([]PeeringEntryExpose, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntry.
-func (in *PeeringEntry) DeepCopy() *PeeringEntry {
-	if in == nil {
-		return nil
-	}
-	out := new(PeeringEntry)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PeeringEntryAs) DeepCopyInto(out *PeeringEntryAs) {
+func (in *MirrorSessionDestination) DeepCopyInto(out *MirrorSessionDestination) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryAs.
-func (in *PeeringEntryAs) DeepCopy() *PeeringEntryAs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorSessionDestination.
+func (in *MirrorSessionDestination) DeepCopy() *MirrorSessionDestination {
 	if in == nil {
 		return nil
 	}
-	out := new(PeeringEntryAs)
+	out := new(MirrorSessionDestination)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PeeringEntryExpose) DeepCopyInto(out *PeeringEntryExpose) {
+func (in *MirrorSessionFilter) DeepCopyInto(out *MirrorSessionFilter) {
 	*out = *in
-	if in.IPs != nil {
-		in, out := &in.IPs, &out.IPs
-		*out = make([]PeeringEntryIP, len(*in))
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.As != nil {
-		in, out := &in.As, &out.As
-		*out = make([]PeeringEntryAs, len(*in))
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]uint16, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryExpose.
-func (in *PeeringEntryExpose) DeepCopy() *PeeringEntryExpose {
-	if in == nil {
-		return nil
-	}
-	out := new(PeeringEntryExpose)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PeeringEntryIP) DeepCopyInto(out *PeeringEntryIP) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryIP.
-func (in *PeeringEntryIP) DeepCopy() *PeeringEntryIP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorSessionFilter.
+func (in *MirrorSessionFilter) DeepCopy() *MirrorSessionFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(PeeringEntryIP)
+	out := new(MirrorSessionFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PeeringList) DeepCopyInto(out *PeeringList) {
+func (in *MirrorSessionList) DeepCopyInto(out *MirrorSessionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Peering, len(*in))
+		*out = make([]MirrorSession, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringList.
-func (in *PeeringList) DeepCopy() *PeeringList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorSessionList.
+func (in *MirrorSessionList) DeepCopy() *MirrorSessionList {
 	if in == nil {
 		return nil
 	}
-	out := new(PeeringList)
+	out := new(MirrorSessionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PeeringList) DeepCopyObject() runtime.Object {
+func (in *MirrorSessionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -366,72 +556,58 @@ func (in *PeeringList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PeeringSpec) DeepCopyInto(out *PeeringSpec) {
+func (in *MirrorSessionSpec) DeepCopyInto(out *MirrorSessionSpec) {
 	*out = *in
-	if in.Peering != nil {
-		in, out := &in.Peering, &out.Peering
-		*out = make(map[string]*PeeringEntry, len(*in))
-		for key, val := range *in {
-			var outVal *PeeringEntry
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = new(PeeringEntry)
-				(*in).DeepCopyInto(*out)
-			}
-			(*out)[key] = outVal
-		}
-	}
+	in.Filter.DeepCopyInto(&out.Filter)
+	out.Destination = in.Destination
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringSpec.
-func (in *PeeringSpec) DeepCopy() *PeeringSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorSessionSpec.
+func (in *MirrorSessionSpec) DeepCopy() *MirrorSessionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PeeringSpec)
+	out := new(MirrorSessionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PeeringStatus) DeepCopyInto(out *PeeringStatus) {
+func (in *MirrorSessionStatus) DeepCopyInto(out *MirrorSessionStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringStatus.
-func (in *PeeringStatus) DeepCopy() *PeeringStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorSessionStatus.
+func (in *MirrorSessionStatus) DeepCopy() *MirrorSessionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PeeringStatus)
+	out := new(MirrorSessionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VPCInfo) DeepCopyInto(out *VPCInfo) {
+func (in *NATPool) DeepCopyInto(out *NATPool) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCInfo.
-func (in *VPCInfo) DeepCopy() *VPCInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATPool.
+func (in *NATPool) DeepCopy() *NATPool {
 	if in == nil {
 		return nil
 	}
-	out := new(VPCInfo)
+	out := new(NATPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VPCInfo) DeepCopyObject() runtime.Object {
+func (in *NATPool) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -439,10 +615,1298 @@ func (in *VPCInfo) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VPCInfoList) DeepCopyInto(out *VPCInfoList) {
+func (in *NATPoolAllocation) DeepCopyInto(out *NATPoolAllocation) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.ReleasedAt != nil {
+		in, out := &in.ReleasedAt, &out.ReleasedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATPoolAllocation.
+func (in *NATPoolAllocation) DeepCopy() *NATPoolAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(NATPoolAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATPoolList) DeepCopyInto(out *NATPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NATPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATPoolList.
+func (in *NATPoolList) DeepCopy() *NATPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(NATPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NATPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATPoolSpec) DeepCopyInto(out *NATPoolSpec) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReleaseCooldown != nil {
+		in, out := &in.ReleaseCooldown, &out.ReleaseCooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATPoolSpec.
+func (in *NATPoolSpec) DeepCopy() *NATPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NATPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATPoolStatus) DeepCopyInto(out *NATPoolStatus) {
+	*out = *in
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make(map[string]NATPoolAllocation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Released != nil {
+		in, out := &in.Released, &out.Released
+		*out = make([]NATPoolAllocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATPoolStatus.
+func (in *NATPoolStatus) DeepCopy() *NATPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NATPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Peering) DeepCopyInto(out *Peering) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Peering.
+func (in *Peering) DeepCopy() *Peering {
+	if in == nil {
+		return nil
+	}
+	out := new(Peering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Peering) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringASAllocation) DeepCopyInto(out *PeeringASAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringASAllocation.
+func (in *PeeringASAllocation) DeepCopy() *PeeringASAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringASAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringAcceptance) DeepCopyInto(out *PeeringAcceptance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringAcceptance.
+func (in *PeeringAcceptance) DeepCopy() *PeeringAcceptance {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringAcceptance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringAcceptance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringAcceptanceList) DeepCopyInto(out *PeeringAcceptanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PeeringAcceptance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringAcceptanceList.
+func (in *PeeringAcceptanceList) DeepCopy() *PeeringAcceptanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringAcceptanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringAcceptanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringAcceptanceSpec) DeepCopyInto(out *PeeringAcceptanceSpec) {
+	*out = *in
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = make([]PeeringEntryExpose, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringAcceptanceSpec.
+func (in *PeeringAcceptanceSpec) DeepCopy() *PeeringAcceptanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringAcceptanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringAcceptanceStatus) DeepCopyInto(out *PeeringAcceptanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringAcceptanceStatus.
+func (in *PeeringAcceptanceStatus) DeepCopy() *PeeringAcceptanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringAcceptanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringAdvertisement) DeepCopyInto(out *PeeringAdvertisement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringAdvertisement.
+func (in *PeeringAdvertisement) DeepCopy() *PeeringAdvertisement {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringAdvertisement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringEntry) DeepCopyInto(out *PeeringEntry) {
+	*out = *in
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = make([]PeeringEntryExpose, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QoS != nil {
+		in, out := &in.QoS, &out.QoS
+		*out = new(PeeringEntryQoS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntry.
+func (in *PeeringEntry) DeepCopy() *PeeringEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringEntryASPool) DeepCopyInto(out *PeeringEntryASPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryASPool.
+func (in *PeeringEntryASPool) DeepCopy() *PeeringEntryASPool {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringEntryASPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringEntryAs) DeepCopyInto(out *PeeringEntryAs) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryAs.
+func (in *PeeringEntryAs) DeepCopy() *PeeringEntryAs {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringEntryAs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringEntryExpose) DeepCopyInto(out *PeeringEntryExpose) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]PeeringEntryIP, len(*in))
+		copy(*out, *in)
+	}
+	if in.As != nil {
+		in, out := &in.As, &out.As
+		*out = make([]PeeringEntryAs, len(*in))
+		copy(*out, *in)
+	}
+	if in.ASPool != nil {
+		in, out := &in.ASPool, &out.ASPool
+		*out = new(PeeringEntryASPool)
+		**out = **in
+	}
+	if in.Translation != nil {
+		in, out := &in.Translation, &out.Translation
+		*out = new(PeeringEntryTranslation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PortForwards != nil {
+		in, out := &in.PortForwards, &out.PortForwards
+		*out = make([]PeeringEntryPortForward, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryExpose.
+func (in *PeeringEntryExpose) DeepCopy() *PeeringEntryExpose {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringEntryExpose)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringEntryIP) DeepCopyInto(out *PeeringEntryIP) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryIP.
+func (in *PeeringEntryIP) DeepCopy() *PeeringEntryIP {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringEntryIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringEntryPortForward) DeepCopyInto(out *PeeringEntryPortForward) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryPortForward.
+func (in *PeeringEntryPortForward) DeepCopy() *PeeringEntryPortForward {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringEntryPortForward)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringEntryQoS) DeepCopyInto(out *PeeringEntryQoS) {
+	*out = *in
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(PeeringQoSPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(PeeringQoSPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryQoS.
+func (in *PeeringEntryQoS) DeepCopy() *PeeringEntryQoS {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringEntryQoS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringEntryTranslation) DeepCopyInto(out *PeeringEntryTranslation) {
+	*out = *in
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make([]PeeringTranslationMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringEntryTranslation.
+func (in *PeeringEntryTranslation) DeepCopy() *PeeringEntryTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringEntryTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringList) DeepCopyInto(out *PeeringList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Peering, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringList.
+func (in *PeeringList) DeepCopy() *PeeringList {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringMesh) DeepCopyInto(out *PeeringMesh) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringMesh.
+func (in *PeeringMesh) DeepCopy() *PeeringMesh {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringMesh)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringMesh) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringMeshList) DeepCopyInto(out *PeeringMeshList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PeeringMesh, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringMeshList.
+func (in *PeeringMeshList) DeepCopy() *PeeringMeshList {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringMeshList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringMeshList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringMeshPair) DeepCopyInto(out *PeeringMeshPair) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringMeshPair.
+func (in *PeeringMeshPair) DeepCopy() *PeeringMeshPair {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringMeshPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringMeshPairStatus) DeepCopyInto(out *PeeringMeshPairStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringMeshPairStatus.
+func (in *PeeringMeshPairStatus) DeepCopy() *PeeringMeshPairStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringMeshPairStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringMeshSpec) DeepCopyInto(out *PeeringMeshSpec) {
+	*out = *in
+	if in.Peering != nil {
+		in, out := &in.Peering, &out.Peering
+		*out = make(map[string]*PeeringEntry, len(*in))
+		for key, val := range *in {
+			var outVal *PeeringEntry
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = new(PeeringEntry)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringMeshSpec.
+func (in *PeeringMeshSpec) DeepCopy() *PeeringMeshSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringMeshSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringMeshStatus) DeepCopyInto(out *PeeringMeshStatus) {
+	*out = *in
+	if in.Pairs != nil {
+		in, out := &in.Pairs, &out.Pairs
+		*out = make(map[string]PeeringMeshPairStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringMeshStatus.
+func (in *PeeringMeshStatus) DeepCopy() *PeeringMeshStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringMeshStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringNATStatus) DeepCopyInto(out *PeeringNATStatus) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedFrom != nil {
+		in, out := &in.ExcludedFrom, &out.ExcludedFrom
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedTo != nil {
+		in, out := &in.ExcludedTo, &out.ExcludedTo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringNATStatus.
+func (in *PeeringNATStatus) DeepCopy() *PeeringNATStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringNATStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringPolicy) DeepCopyInto(out *PeeringPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringPolicy.
+func (in *PeeringPolicy) DeepCopy() *PeeringPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringPolicyConflict) DeepCopyInto(out *PeeringPolicyConflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringPolicyConflict.
+func (in *PeeringPolicyConflict) DeepCopy() *PeeringPolicyConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringPolicyConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringPolicyList) DeepCopyInto(out *PeeringPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PeeringPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringPolicyList.
+func (in *PeeringPolicyList) DeepCopy() *PeeringPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringPolicySpec) DeepCopyInto(out *PeeringPolicySpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = make([]PeeringEntryExpose, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PeerExpose != nil {
+		in, out := &in.PeerExpose, &out.PeerExpose
+		*out = make([]PeeringEntryExpose, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringPolicySpec.
+func (in *PeeringPolicySpec) DeepCopy() *PeeringPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringPolicyStatus) DeepCopyInto(out *PeeringPolicyStatus) {
+	*out = *in
+	if in.Peerings != nil {
+		in, out := &in.Peerings, &out.Peerings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]PeeringPolicyConflict, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringPolicyStatus.
+func (in *PeeringPolicyStatus) DeepCopy() *PeeringPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringQoSPolicy) DeepCopyInto(out *PeeringQoSPolicy) {
+	*out = *in
+	if in.DSCP != nil {
+		in, out := &in.DSCP, &out.DSCP
+		*out = new(uint8)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringQoSPolicy.
+func (in *PeeringQoSPolicy) DeepCopy() *PeeringQoSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringQoSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringQoSPolicyStatus) DeepCopyInto(out *PeeringQoSPolicyStatus) {
+	*out = *in
+	in.PeeringQoSPolicy.DeepCopyInto(&out.PeeringQoSPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringQoSPolicyStatus.
+func (in *PeeringQoSPolicyStatus) DeepCopy() *PeeringQoSPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringQoSPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringQoSStatus) DeepCopyInto(out *PeeringQoSStatus) {
+	*out = *in
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(PeeringQoSPolicyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(PeeringQoSPolicyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringQoSStatus.
+func (in *PeeringQoSStatus) DeepCopy() *PeeringQoSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringQoSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringRequest) DeepCopyInto(out *PeeringRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringRequest.
+func (in *PeeringRequest) DeepCopy() *PeeringRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringRequestList) DeepCopyInto(out *PeeringRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PeeringRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringRequestList.
+func (in *PeeringRequestList) DeepCopy() *PeeringRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeeringRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringRequestSpec) DeepCopyInto(out *PeeringRequestSpec) {
+	*out = *in
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = make([]PeeringEntryExpose, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringRequestSpec.
+func (in *PeeringRequestSpec) DeepCopy() *PeeringRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringRequestStatus) DeepCopyInto(out *PeeringRequestStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringRequestStatus.
+func (in *PeeringRequestStatus) DeepCopy() *PeeringRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringServiceChain) DeepCopyInto(out *PeeringServiceChain) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringServiceChain.
+func (in *PeeringServiceChain) DeepCopy() *PeeringServiceChain {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringServiceChain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringSpec) DeepCopyInto(out *PeeringSpec) {
+	*out = *in
+	if in.Peering != nil {
+		in, out := &in.Peering, &out.Peering
+		*out = make(map[string]*PeeringEntry, len(*in))
+		for key, val := range *in {
+			var outVal *PeeringEntry
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = new(PeeringEntry)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ActiveFrom != nil {
+		in, out := &in.ActiveFrom, &out.ActiveFrom
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.DeleteAfterExpiry != nil {
+		in, out := &in.DeleteAfterExpiry, &out.DeleteAfterExpiry
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Advertisement != nil {
+		in, out := &in.Advertisement, &out.Advertisement
+		*out = new(PeeringAdvertisement)
+		**out = **in
+	}
+	if in.ServiceChain != nil {
+		in, out := &in.ServiceChain, &out.ServiceChain
+		*out = new(PeeringServiceChain)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringSpec.
+func (in *PeeringSpec) DeepCopy() *PeeringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringStatus) DeepCopyInto(out *PeeringStatus) {
+	*out = *in
+	if in.VPCs != nil {
+		in, out := &in.VPCs, &out.VPCs
+		*out = make(map[string]PeeringVPCStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = new(PeeringTrafficStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringStatus.
+func (in *PeeringStatus) DeepCopy() *PeeringStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringTrafficStatus) DeepCopyInto(out *PeeringTrafficStatus) {
+	*out = *in
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+	if in.VPCs != nil {
+		in, out := &in.VPCs, &out.VPCs
+		*out = make(map[string]PeeringVPCTrafficStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringTrafficStatus.
+func (in *PeeringTrafficStatus) DeepCopy() *PeeringTrafficStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringTrafficStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringTransitPath) DeepCopyInto(out *PeeringTransitPath) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringTransitPath.
+func (in *PeeringTransitPath) DeepCopy() *PeeringTransitPath {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringTransitPath)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringTranslationMapping) DeepCopyInto(out *PeeringTranslationMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringTranslationMapping.
+func (in *PeeringTranslationMapping) DeepCopy() *PeeringTranslationMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringTranslationMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringVPCStatus) DeepCopyInto(out *PeeringVPCStatus) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceNAT != nil {
+		in, out := &in.SourceNAT, &out.SourceNAT
+		*out = make([]PeeringNATStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DestinationNAT != nil {
+		in, out := &in.DestinationNAT, &out.DestinationNAT
+		*out = make([]PeeringNATStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Transit != nil {
+		in, out := &in.Transit, &out.Transit
+		*out = make([]PeeringTransitPath, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllocatedAs != nil {
+		in, out := &in.AllocatedAs, &out.AllocatedAs
+		*out = make([]PeeringASAllocation, len(*in))
+		copy(*out, *in)
+	}
+	if in.QoS != nil {
+		in, out := &in.QoS, &out.QoS
+		*out = new(PeeringQoSStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringVPCStatus.
+func (in *PeeringVPCStatus) DeepCopy() *PeeringVPCStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringVPCStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringVPCTrafficStatus) DeepCopyInto(out *PeeringVPCTrafficStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringVPCTrafficStatus.
+func (in *PeeringVPCTrafficStatus) DeepCopy() *PeeringVPCTrafficStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringVPCTrafficStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuota) DeepCopyInto(out *TenantQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuota.
+func (in *TenantQuota) DeepCopy() *TenantQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuotaList) DeepCopyInto(out *TenantQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuotaList.
+func (in *TenantQuotaList) DeepCopy() *TenantQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuotaSpec) DeepCopyInto(out *TenantQuotaSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuotaSpec.
+func (in *TenantQuotaSpec) DeepCopy() *TenantQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuotaStatus) DeepCopyInto(out *TenantQuotaStatus) {
+	*out = *in
+	out.Usage = in.Usage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuotaStatus.
+func (in *TenantQuotaStatus) DeepCopy() *TenantQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuotaUsage) DeepCopyInto(out *TenantQuotaUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuotaUsage.
+func (in *TenantQuotaUsage) DeepCopy() *TenantQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCInfo) DeepCopyInto(out *VPCInfo) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCInfo.
+func (in *VPCInfo) DeepCopy() *VPCInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VPCInfo) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCInfoList) DeepCopyInto(out *VPCInfoList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]VPCInfo, len(*in))
@@ -484,7 +1948,7 @@ func (in *VPCInfoSpec) DeepCopyInto(out *VPCInfoSpec) {
 				inVal := (*in)[key]
 				in, out := &inVal, &outVal
 				*out = new(VPCInfoSubnet)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 			(*out)[key] = outVal
 		}
@@ -519,6 +1983,11 @@ func (in *VPCInfoStatus) DeepCopy() *VPCInfoStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPCInfoSubnet) DeepCopyInto(out *VPCInfoSubnet) {
 	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCInfoSubnet.
@@ -530,3 +1999,163 @@ func (in *VPCInfoSubnet) DeepCopy() *VPCInfoSubnet {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualService) DeepCopyInto(out *VirtualService) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualService.
+func (in *VirtualService) DeepCopy() *VirtualService {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualService) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualServiceBackend) DeepCopyInto(out *VirtualServiceBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceBackend.
+func (in *VirtualServiceBackend) DeepCopy() *VirtualServiceBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualServiceBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualServiceBackendStatus) DeepCopyInto(out *VirtualServiceBackendStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceBackendStatus.
+func (in *VirtualServiceBackendStatus) DeepCopy() *VirtualServiceBackendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualServiceBackendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualServiceHealthCheck) DeepCopyInto(out *VirtualServiceHealthCheck) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceHealthCheck.
+func (in *VirtualServiceHealthCheck) DeepCopy() *VirtualServiceHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualServiceHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualServiceList) DeepCopyInto(out *VirtualServiceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceList.
+func (in *VirtualServiceList) DeepCopy() *VirtualServiceList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualServiceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualServiceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualServiceSpec) DeepCopyInto(out *VirtualServiceSpec) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]VirtualServiceBackend, len(*in))
+		copy(*out, *in)
+	}
+	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceSpec.
+func (in *VirtualServiceSpec) DeepCopy() *VirtualServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualServiceStatus) DeepCopyInto(out *VirtualServiceStatus) {
+	*out = *in
+	if in.Peerings != nil {
+		in, out := &in.Peerings, &out.Peerings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]VirtualServiceBackendStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceStatus.
+func (in *VirtualServiceStatus) DeepCopy() *VirtualServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -15,13 +15,24 @@ type VPCInfoData struct {
 	gwapi.VPCInfoStatus `json:",inline"`
 }
 
+type ExternalData struct {
+	gwapi.ExternalSpec   `json:",inline"`
+	gwapi.ExternalStatus `json:",inline"`
+}
+
 // GatewayAgentSpec defines the desired state of GatewayAgent.
 type GatewayAgentSpec struct {
 	// AgentVersion is the desired version of the gateway agent to trigger generation changes on controller upgrades
-	AgentVersion string                       `json:"agentVersion,omitempty"`
-	Gateway      gwapi.GatewaySpec            `json:"gateway,omitempty"`
-	VPCs         map[string]VPCInfoData       `json:"vpcs,omitempty"`
-	Peerings     map[string]gwapi.PeeringSpec `json:"peerings,omitempty"`
+	AgentVersion string                  `json:"agentVersion,omitempty"`
+	Gateway      gwapi.GatewaySpec       `json:"gateway,omitempty"`
+	VPCs         map[string]VPCInfoData  `json:"vpcs,omitempty"`
+	Externals    map[string]ExternalData `json:"externals,omitempty"`
+	// Peerings are keyed by the names passed to the dataplane: the name of the peering in the gateway namespace,
+	// "<namespace>..<name>" in the other namespaces, "<namespace>..mesh..<mesh>..<pair>" for the mesh pairs and
+	// "<namespace>..request..<request>..<remote namespace>" for the accepted requests
+	Peerings map[string]gwapi.PeeringSpec `json:"peerings,omitempty"`
+	// VirtualServices are the load balanced VIPs keyed the same way as the peerings
+	VirtualServices map[string]gwapi.VirtualServiceSpec `json:"virtualServices,omitempty"`
 }
 
 // GatewayAgentStatus defines the observed state of GatewayAgent.
@@ -32,6 +43,35 @@ type GatewayAgentStatus struct {
 	LastAppliedTime kmetav1.Time `json:"lastAppliedTime,omitempty"`
 	// Generation of the last successful configuration application
 	LastAppliedGen int64 `json:"lastAppliedGen,omitempty"`
+	// VirtualServices is the health of the backends of each virtual service as seen by the gateway
+	VirtualServices map[string]VirtualServiceAgentStatus `json:"virtualServices,omitempty"`
+	// Peerings is the per VPC state of each peering on the gateway keyed the same way as in the spec
+	Peerings map[string]PeeringAgentStatus `json:"peerings,omitempty"`
+	// TrafficUpdatedAt is the time the traffic counters of the peerings were last scraped from the dataplane
+	TrafficUpdatedAt *kmetav1.Time `json:"trafficUpdatedAt,omitempty"`
+}
+
+// PeeringAgentStatus is the state of a peering on the gateway
+type PeeringAgentStatus struct {
+	// VPCs is the state of each side of the peering keyed by the VPC name
+	VPCs map[string]PeeringVPCAgentStatus `json:"vpcs,omitempty"`
+}
+
+// PeeringVPCAgentStatus is the state of a side of a peering on the gateway
+type PeeringVPCAgentStatus struct {
+	// PeeringVPCTrafficStatus is the traffic of the VPC through the gateway as reported by the dataplane
+	gwapi.PeeringVPCTrafficStatus `json:",inline"`
+	// EgressDroppedPackets is the number of packets from the VPC dropped by the QoS policer, not set if the gateway
+	// doesn't report the policer counters
+	EgressDroppedPackets *uint64 `json:"egressDroppedPackets,omitempty"`
+	// IngressDroppedPackets is the number of packets to the VPC dropped by the QoS policer, not set if the gateway
+	// doesn't report the policer counters
+	IngressDroppedPackets *uint64 `json:"ingressDroppedPackets,omitempty"`
+}
+
+// VirtualServiceAgentStatus is the health of the backends of a virtual service as seen by the gateway
+type VirtualServiceAgentStatus struct {
+	Backends []gwapi.VirtualServiceBackendStatus `json:"backends,omitempty"`
 }
 
 // +kubebuilder:object:root=true
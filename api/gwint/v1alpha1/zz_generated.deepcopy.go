@@ -12,6 +12,23 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalData) DeepCopyInto(out *ExternalData) {
+	*out = *in
+	in.ExternalSpec.DeepCopyInto(&out.ExternalSpec)
+	out.ExternalStatus = in.ExternalStatus
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalData.
+func (in *ExternalData) DeepCopy() *ExternalData {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalData)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayAgent) DeepCopyInto(out *GatewayAgent) {
 	*out = *in
@@ -82,6 +99,13 @@ func (in *GatewayAgentSpec) DeepCopyInto(out *GatewayAgentSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Externals != nil {
+		in, out := &in.Externals, &out.Externals
+		*out = make(map[string]ExternalData, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.Peerings != nil {
 		in, out := &in.Peerings, &out.Peerings
 		*out = make(map[string]gatewayv1alpha1.PeeringSpec, len(*in))
@@ -89,6 +113,13 @@ func (in *GatewayAgentSpec) DeepCopyInto(out *GatewayAgentSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.VirtualServices != nil {
+		in, out := &in.VirtualServices, &out.VirtualServices
+		*out = make(map[string]gatewayv1alpha1.VirtualServiceSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAgentSpec.
@@ -105,6 +136,24 @@ func (in *GatewayAgentSpec) DeepCopy() *GatewayAgentSpec {
 func (in *GatewayAgentStatus) DeepCopyInto(out *GatewayAgentStatus) {
 	*out = *in
 	in.LastAppliedTime.DeepCopyInto(&out.LastAppliedTime)
+	if in.VirtualServices != nil {
+		in, out := &in.VirtualServices, &out.VirtualServices
+		*out = make(map[string]VirtualServiceAgentStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Peerings != nil {
+		in, out := &in.Peerings, &out.Peerings
+		*out = make(map[string]PeeringAgentStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.TrafficUpdatedAt != nil {
+		in, out := &in.TrafficUpdatedAt, &out.TrafficUpdatedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAgentStatus.
@@ -117,6 +166,54 @@ func (in *GatewayAgentStatus) DeepCopy() *GatewayAgentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringAgentStatus) DeepCopyInto(out *PeeringAgentStatus) {
+	*out = *in
+	if in.VPCs != nil {
+		in, out := &in.VPCs, &out.VPCs
+		*out = make(map[string]PeeringVPCAgentStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringAgentStatus.
+func (in *PeeringAgentStatus) DeepCopy() *PeeringAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringVPCAgentStatus) DeepCopyInto(out *PeeringVPCAgentStatus) {
+	*out = *in
+	out.PeeringVPCTrafficStatus = in.PeeringVPCTrafficStatus
+	if in.EgressDroppedPackets != nil {
+		in, out := &in.EgressDroppedPackets, &out.EgressDroppedPackets
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.IngressDroppedPackets != nil {
+		in, out := &in.IngressDroppedPackets, &out.IngressDroppedPackets
+		*out = new(uint64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringVPCAgentStatus.
+func (in *PeeringVPCAgentStatus) DeepCopy() *PeeringVPCAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringVPCAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPCInfoData) DeepCopyInto(out *VPCInfoData) {
 	*out = *in
@@ -133,3 +230,23 @@ func (in *VPCInfoData) DeepCopy() *VPCInfoData {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualServiceAgentStatus) DeepCopyInto(out *VirtualServiceAgentStatus) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]gatewayv1alpha1.VirtualServiceBackendStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceAgentStatus.
+func (in *VirtualServiceAgentStatus) DeepCopy() *VirtualServiceAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualServiceAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -4,7 +4,10 @@
 package meta
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type GatewayCtrlConfig struct {
@@ -23,10 +26,16 @@ type GatewayCtrlConfig struct {
 	AlloyImageName       string              `json:"alloyImageName,omitempty"`
 	AlloyImageVersion    string              `json:"alloyImageVersion,omitempty"`
 	ControlProxyURL      string              `json:"controlProxyURL,omitempty"`
+	TrafficResolution    kmetav1.Duration    `json:"trafficResolution,omitempty"`
 }
 
 type AgentConfig struct {
-	Name             string `json:"name,omitempty"`
-	Namespace        string `json:"namespace,omitempty"`
-	DataplaneAddress string `json:"dataplaneAddress,omitempty"`
+	Name                    string           `json:"name,omitempty"`
+	Namespace               string           `json:"namespace,omitempty"`
+	DataplaneAddress        string           `json:"dataplaneAddress,omitempty"`
+	DataplaneMetricsAddress string           `json:"dataplaneMetricsAddress,omitempty"`
+	TrafficResolution       kmetav1.Duration `json:"trafficResolution,omitempty"`
 }
+
+// DefaultTrafficResolution is how often the traffic counters of the peerings are refreshed if not configured
+const DefaultTrafficResolution = time.Minute
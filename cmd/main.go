@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"log/slog"
@@ -85,6 +86,9 @@ func run() error {
 	if err := kyaml.Unmarshal(cfgData, cfg); err != nil {
 		return fmt.Errorf("unmarshalling config file: %w", err)
 	}
+	if cfg.TrafficResolution.Duration == 0 {
+		cfg.TrafficResolution.Duration = meta.DefaultTrafficResolution
+	}
 
 	// Disabling http/2 will prevent from being vulnerable to the HTTP/2 Stream Cancellation and Rapid Reset CVEs.
 	// For more information see:
@@ -133,6 +137,10 @@ func run() error {
 		return fmt.Errorf("creating manager: %w", err)
 	}
 
+	if err := ctrl.SetupIndexesWith(context.Background(), mgr); err != nil {
+		return fmt.Errorf("setting up indexes: %w", err)
+	}
+
 	// Controllers
 	if err := ctrl.SetupGatewayReconcilerWith(mgr, cfg); err != nil {
 		return fmt.Errorf("setting up gateway controller: %w", err)
@@ -140,6 +148,33 @@ func run() error {
 	if err := ctrl.SetupVPCInfoReconcilerWith(mgr); err != nil {
 		return fmt.Errorf("setting up vpcinfo controller: %w", err)
 	}
+	if err := ctrl.SetupExternalReconcilerWith(mgr); err != nil {
+		return fmt.Errorf("setting up external controller: %w", err)
+	}
+	if err := ctrl.SetupPeeringReconcilerWith(mgr, cfg); err != nil {
+		return fmt.Errorf("setting up peering controller: %w", err)
+	}
+	if err := ctrl.SetupPeeringPolicyReconcilerWith(mgr); err != nil {
+		return fmt.Errorf("setting up peeringpolicy controller: %w", err)
+	}
+	if err := ctrl.SetupPeeringMeshReconcilerWith(mgr); err != nil {
+		return fmt.Errorf("setting up peeringmesh controller: %w", err)
+	}
+	if err := ctrl.SetupPeeringRequestReconcilerWith(mgr); err != nil {
+		return fmt.Errorf("setting up peeringrequest controller: %w", err)
+	}
+	if err := ctrl.SetupTenantQuotaReconcilerWith(mgr); err != nil {
+		return fmt.Errorf("setting up tenantquota controller: %w", err)
+	}
+	if err := ctrl.SetupVirtualServiceReconcilerWith(mgr); err != nil {
+		return fmt.Errorf("setting up virtualservice controller: %w", err)
+	}
+	if err := ctrl.SetupMirrorSessionReconcilerWith(mgr); err != nil {
+		return fmt.Errorf("setting up mirrorsession controller: %w", err)
+	}
+	if err := ctrl.SetupNATPoolReconcilerWith(mgr); err != nil {
+		return fmt.Errorf("setting up natpool controller: %w", err)
+	}
 
 	// Webhooks
 	if err := ctrl.SetupGatewayWebhookWith(mgr); err != nil {
@@ -151,6 +186,33 @@ func run() error {
 	if err := ctrl.SetupVPCInfoWebhookWith(mgr); err != nil {
 		return fmt.Errorf("setting up vpcinfo webhook: %w", err)
 	}
+	if err := ctrl.SetupExternalWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up external webhook: %w", err)
+	}
+	if err := ctrl.SetupPeeringPolicyWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up peeringpolicy webhook: %w", err)
+	}
+	if err := ctrl.SetupPeeringMeshWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up peeringmesh webhook: %w", err)
+	}
+	if err := ctrl.SetupPeeringRequestWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up peeringrequest webhook: %w", err)
+	}
+	if err := ctrl.SetupPeeringAcceptanceWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up peeringacceptance webhook: %w", err)
+	}
+	if err := ctrl.SetupTenantQuotaWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up tenantquota webhook: %w", err)
+	}
+	if err := ctrl.SetupVirtualServiceWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up virtualservice webhook: %w", err)
+	}
+	if err := ctrl.SetupMirrorSessionWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up mirrorsession webhook: %w", err)
+	}
+	if err := ctrl.SetupNATPoolWebhookWith(mgr); err != nil {
+		return fmt.Errorf("setting up natpool webhook: %w", err)
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -21,9 +21,11 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/status"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
 	kctrl "sigs.k8s.io/controller-runtime"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
@@ -139,6 +141,12 @@ func (svc *Service) watchAgent(ctx context.Context) error {
 	enforce := time.NewTicker(5 * time.Second)
 	defer enforce.Stop()
 
+	trafficResolution := svc.cfg.TrafficResolution.Duration
+	if trafficResolution == 0 {
+		trafficResolution = meta.DefaultTrafficResolution
+	}
+	lastTraffic := time.Time{}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -222,8 +230,42 @@ func (svc *Service) watchAgent(ctx context.Context) error {
 					return fmt.Errorf("updating agent status (enforcer): %w", err)
 				}
 			}
+
+			if svc.cfg.DataplaneMetricsAddress != "" && time.Since(lastTraffic) >= trafficResolution {
+				lastTraffic = time.Now()
+
+				if err := svc.publishTraffic(ctx); err != nil {
+					return fmt.Errorf("publishing traffic: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// publishTraffic updates the agent status with the traffic counters of the peerings scraped from the dataplane, the
+// scrape errors are only logged as the counters are just for information
+func (svc *Service) publishTraffic(ctx context.Context) error {
+	peerings, err := svc.scrapeTraffic(ctx, svc.curr)
+	if err != nil {
+		slog.Warn("Failed to scrape traffic counters, will retry", "error", err)
+
+		return nil
+	}
+
+	svc.curr.Status.Peerings = peerings
+	svc.curr.Status.TrafficUpdatedAt = ptr.To(kmetav1.Now())
+
+	if err := svc.kube.Status().Update(ctx, svc.curr); err != nil {
+		if kapierrors.IsConflict(err) {
+			slog.Warn("Agent object changed, will publish traffic counters later", "error", err)
+
+			return nil
 		}
+
+		return fmt.Errorf("updating agent status (traffic): %w", err)
 	}
+
+	return nil
 }
 
 func (svc *Service) enforceDataplaneConfig(ctx context.Context, ag *gwintapi.GatewayAgent) error {
@@ -4,11 +4,18 @@
 package agent
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net/netip"
+	"slices"
 
 	"go.githedgehog.com/gateway-proto/pkg/dataplane"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
 	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -69,7 +76,7 @@ func buildDataplaneConfig(ag *gwintapi.GatewayAgent) (*dataplane.GatewayConfig,
 		})
 	}
 
-	vpcSubnets := map[string]map[string]string{}
+	vpcSubnets := subnetsOf(ag.Spec.VPCs)
 	vpcs := []*dataplane.VPC{}
 	for vpcName, vpc := range ag.Spec.VPCs {
 		vpcs = append(vpcs, &dataplane.VPC{
@@ -77,29 +84,282 @@ func buildDataplaneConfig(ag *gwintapi.GatewayAgent) (*dataplane.GatewayConfig,
 			Id:   vpc.InternalID,
 			Vni:  vpc.VNI,
 		})
+	}
 
-		vpcSubnets[vpcName] = map[string]string{}
-		for subnetName, subnet := range vpc.Subnets {
-			vpcSubnets[vpcName][subnetName] = subnet.CIDR
+	vrfs := []*dataplane.VRF{}
+	externals := map[string]gwintapi.ExternalData{}
+	for _, extName := range slices.Sorted(maps.Keys(ag.Spec.Externals)) {
+		ext := ag.Spec.Externals[extName]
+		// a single unsupported external must not take down the other externals and peerings of the gateway, so it's
+		// skipped together with its peerings, "not" entries of the external exposes are passed with the peerings and
+		// act as route filters
+		if err := ext.DataplaneUnsupported(); err != nil {
+			slog.Warn("Skipping external", "name", extName, "error", err)
+
+			continue
+		}
+
+		iface := &dataplane.Interface{
+			Name:    ext.Interface,
+			Ipaddrs: ext.IPs,
+			Type:    dataplane.IfType_IF_TYPE_ETHERNET,
+			Role:    dataplane.IfRole_IF_ROLE_EXTERNAL,
+		}
+		if ext.VLAN != 0 {
+			iface.Name = fmt.Sprintf("%s.%d", ext.Interface, ext.VLAN)
+			iface.Type = dataplane.IfType_IF_TYPE_VLAN
+			iface.Vlan = ptr.To(uint32(ext.VLAN))
+			iface.SystemName = ptr.To(ext.Interface)
+		}
+		if ext.MTU != 0 {
+			iface.Mtu = ptr.To(ext.MTU)
+		}
+
+		asn := ext.ASN
+		if asn == 0 {
+			asn = ag.Spec.Gateway.ASN
+		}
+
+		extNeighs := []*dataplane.BgpNeighbor{}
+		invalid := false
+		for _, neigh := range ext.Neighbors {
+			neighIP, err := netip.ParseAddr(neigh.IP)
+			if err != nil {
+				slog.Warn("Skipping external", "name", extName, "error", fmt.Errorf("invalid neighbor IP %s: %w", neigh.IP, err))
+				invalid = true
+
+				break
+			}
+			extNeighs = append(extNeighs, &dataplane.BgpNeighbor{
+				Address:   neighIP.String(),
+				RemoteAsn: fmt.Sprintf("%d", neigh.ASN),
+				AfActivate: []dataplane.BgpAF{
+					dataplane.BgpAF_IPV4_UNICAST,
+				},
+			})
+		}
+
+		if invalid {
+			continue
 		}
+		externals[extName] = ext
+
+		// externals are modeled as VPCs so they could be referenced by the peerings, while the handoff interface and
+		// the BGP sessions with the external live in the dedicated VRF
+		vpcs = append(vpcs, &dataplane.VPC{
+			Name: extName,
+			Id:   ext.InternalID,
+		})
+
+		vrfs = append(vrfs, &dataplane.VRF{
+			Name:       extName,
+			Interfaces: []*dataplane.Interface{iface},
+			Router: &dataplane.RouterConfig{
+				Asn:         fmt.Sprintf("%d", asn),
+				RouterId:    protoIP.Addr().String(),
+				Neighbors:   extNeighs,
+				Ipv4Unicast: &dataplane.BgpAddressFamilyIPv4{},
+			},
+		})
 	}
 
 	peerings := []*dataplane.VpcPeering{}
-	for peeringName, peering := range ag.Spec.Peerings {
-		p := &dataplane.VpcPeering{
-			Name: peeringName,
-			For:  []*dataplane.PeeringEntryFor{},
+	for _, peeringName := range slices.Sorted(maps.Keys(ag.Spec.Peerings)) {
+		peering := ag.Spec.Peerings[peeringName]
+		p, err := buildPeering(peeringName, &peering, vpcSubnets, externals)
+		if err != nil {
+			// a single invalid or unsupported peering must not take down the other peerings of the gateway, the
+			// controller already skips them and reports the reason in the status of the objects they come from
+			slog.Warn("Skipping peering", "name", peeringName, "error", err)
+
+			continue
+		}
+
+		peerings = append(peerings, p)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(ag.Spec.VirtualServices)) {
+		vs := ag.Spec.VirtualServices[name]
+		if err := validateVirtualService(&vs, vpcSubnets); err != nil {
+			slog.Warn("Skipping virtual service", "name", name, "error", err)
+
+			continue
 		}
 
-		for vpcName, vpc := range peering.Peering {
-			exposes := []*dataplane.Expose{}
+		// TODO pass the VIP, backends and health check to the dataplane to distribute the flows across the healthy
+		// backends using consistent hashing and report their health once load balancing is supported by the
+		// dataplane API, the controller doesn't pass any virtual services until then
+		slog.Warn("Skipping virtual service", "name", name, "error", vs.DataplaneUnsupported())
+	}
+
+	// TODO pass the collectors to the dataplane and enable the export for the peerings with FlowExport set once flow
+	// export is supported by the dataplane API, the controller doesn't pass it until then
+	if fe := ag.Spec.Gateway.FlowExport; fe != nil {
+		slog.Warn("Skipping flow export", "error", fe.DataplaneUnsupported())
+	}
 
-			for _, expose := range vpc.Expose {
+	return &dataplane.GatewayConfig{
+		Generation: ag.Generation,
+		Device: &dataplane.Device{
+			Driver:   dataplane.PacketDriver_KERNEL,
+			Hostname: ag.Name,
+			Loglevel: dataplane.LogLevel_DEBUG,
+		},
+		Underlay: &dataplane.Underlay{
+			Vrfs: append([]*dataplane.VRF{
+				{
+					Name:       "default",
+					Interfaces: ifaces,
+					Router: &dataplane.RouterConfig{
+						Asn:       fmt.Sprintf("%d", ag.Spec.Gateway.ASN),
+						RouterId:  protoIP.Addr().String(),
+						Neighbors: neighs,
+						Ipv4Unicast: &dataplane.BgpAddressFamilyIPv4{
+							Networks:              []string{ag.Spec.Gateway.VTEPIP},
+							RedistributeConnected: false,
+							RedistributeStatic:    false,
+						},
+						L2VpnEvpn: &dataplane.BgpAddressFamilyL2VpnEvpn{
+							AdvertiseAllVni: true,
+						},
+					},
+				},
+			}, vrfs...),
+		},
+		Overlay: &dataplane.Overlay{
+			Vpcs:     vpcs,
+			Peerings: peerings,
+		},
+	}, nil
+}
+
+// subnetsOf returns the CIDRs of the subnets of each VPC keyed by the VPC and subnet names
+func subnetsOf(vpcs map[string]gwintapi.VPCInfoData) map[string]map[string][]string {
+	res := map[string]map[string][]string{}
+	for vpcName, vpc := range vpcs {
+		res[vpcName] = map[string][]string{}
+		for subnetName, subnet := range vpc.Subnets {
+			if subnet != nil {
+				res[vpcName][subnetName] = subnet.AllCIDRs()
+			}
+		}
+	}
+
+	return res
+}
+
+// buildPeering builds the dataplane config of the peering, it fails if the peering is invalid or uses a feature the
+// dataplane doesn't support yet
+func buildPeering(peeringName string, peering *gwapi.PeeringSpec, vpcSubnets map[string]map[string][]string, externals map[string]gwintapi.ExternalData) (*dataplane.VpcPeering, error) {
+	// TODO pass peering.Advertisement to the dataplane once it's supported by the dataplane API, the routes are
+	// always enumerated and the intended mode for each VPC is only reported in the peering status for now
+	// the routes re-exposed by the transit VPCs are already added to their exposes by the controller, so the
+	// transit flag of the entries isn't passed to the dataplane
+	p := &dataplane.VpcPeering{
+		Name: peeringName,
+		For:  []*dataplane.PeeringEntryFor{},
+	}
+
+	for _, vpcName := range slices.Sorted(maps.Keys(peering.Peering)) {
+		_, isVPC := vpcSubnets[vpcName]
+		_, isExternal := externals[vpcName]
+		if !isVPC && !isExternal {
+			return nil, fmt.Errorf("unknown vpc or external %s in peering %s", vpcName, peeringName) //nolint:goerr113
+		}
+	}
+
+	// the dataplane can't only allow the return traffic of the flows started by the consume-only side yet
+	for _, vpcName := range slices.Sorted(maps.Keys(peering.Peering)) {
+		if entry := peering.Peering[vpcName]; entry == nil || len(entry.Expose) == 0 {
+			return nil, fmt.Errorf("consume-only vpc %s in peering %s isn't supported", vpcName, peeringName) //nolint:goerr113
+		}
+	}
+
+	if peering.FlowExport {
+		return nil, fmt.Errorf("flow export in peering %s isn't supported", peeringName) //nolint:goerr113
+	}
+
+	if chain := peering.ServiceChain; chain != nil {
+		if err := validateServiceChain(chain, slices.Collect(maps.Keys(peering.Peering)), vpcSubnets); err != nil {
+			return nil, fmt.Errorf("invalid service chain in peering %s: %w", peeringName, err)
+		}
+
+		// the traffic must never bypass the appliance, so the peering can't be passed to the dataplane until the
+		// redirect could be configured
+		// TODO pass the routes of the peered prefixes via the ingress IP in the inspection VRF and back via the
+		// egress IP once static routes are supported by the dataplane API
+		return nil, fmt.Errorf("service chain in peering %s isn't supported", peeringName) //nolint:goerr113
+	}
+
+	translated := false
+	for vpcName, vpc := range peering.Peering {
+		if vpc == nil {
+			continue
+		}
+		for _, expose := range vpc.Expose {
+			if expose.Translation == nil {
+				continue
+			}
+			if err := expose.Translation.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid translation in peering %s / vpc %s: %w", peeringName, vpcName, err)
+			}
+			translated = true
+		}
+	}
+	if translated {
+		// passing the translated addresses as a plain "as" pool would make the dataplane NAT within the same
+		// address family, so the peering can't be passed to the dataplane until it could translate
+		// TODO pass the translation mode, the NAT64 prefix and the NAT46 mappings once NAT64/NAT46 is supported by
+		// the dataplane API
+		return nil, fmt.Errorf("translation in peering %s isn't supported", peeringName) //nolint:goerr113
+	}
+
+	// the external VRFs only exchange IPv4 unicast routes, so only the IPv4 part of the dual-stack exposes of the
+	// VPCs is passed for the peerings with the externals
+	// TODO activate IPv6 unicast for the externals once they could be configured with IPv6 addresses and neighbors
+	withExternal := false
+	for vpcName := range peering.Peering {
+		if _, isExternal := externals[vpcName]; isExternal {
+			withExternal = true
+		}
+	}
+
+	for vpcName, vpc := range peering.Peering {
+		exposes := []*dataplane.Expose{}
+
+		if vpc != nil && vpc.QoS != nil {
+			if err := vpc.QoS.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid qos in peering %s / vpc %s: %w", peeringName, vpcName, err)
+			}
+
+			// TODO configure the policers and the marking for both directions and report their drops in the agent
+			// status once QoS is supported by the dataplane API
+			return nil, fmt.Errorf("qos in peering %s / vpc %s isn't supported", peeringName, vpcName) //nolint:goerr113
+		}
+
+		for _, expose := range vpc.Expose {
+			if err := expose.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid expose in peering %s / vpc %s: %w", peeringName, vpcName, err)
+			}
+
+			familyExposes := []*dataplane.Expose{}
+
+			if _, isExternal := externals[vpcName]; isExternal {
 				ips := []*dataplane.PeeringIPs{}
 				as := []*dataplane.PeeringAs{}
 
+				// not entries of the externals are route filters, so they're passed as is
 				for _, ipEntry := range expose.IPs {
-					// TODO validate
+					if ipEntry.GE != 0 || ipEntry.LE != 0 {
+						return nil, fmt.Errorf("ge/le route filters aren't supported in peering %s / vpc %s", peeringName, vpcName) //nolint:goerr113
+					}
+
+					for _, cidr := range []string{ipEntry.CIDR, ipEntry.Not} {
+						if prefix, err := netip.ParsePrefix(cidr); err == nil && prefix.Addr().Is6() {
+							return nil, fmt.Errorf("ipv6 routes of external aren't supported in peering %s / vpc %s", peeringName, vpcName) //nolint:goerr113
+						}
+					}
+
 					switch {
 					case ipEntry.CIDR != "":
 						ips = append(ips, &dataplane.PeeringIPs{
@@ -110,10 +370,12 @@ func buildDataplaneConfig(ag *gwintapi.GatewayAgent) (*dataplane.GatewayConfig,
 							Rule: &dataplane.PeeringIPs_Not{Not: ipEntry.Not},
 						})
 					case ipEntry.VPCSubnet != "":
-						if subnetCIDR, ok := vpcSubnets[vpcName][ipEntry.VPCSubnet]; ok {
-							ips = append(ips, &dataplane.PeeringIPs{
-								Rule: &dataplane.PeeringIPs_Cidr{Cidr: subnetCIDR},
-							})
+						if subnetCIDRs, ok := vpcSubnets[vpcName][ipEntry.VPCSubnet]; ok {
+							for _, subnetCIDR := range subnetCIDRs {
+								ips = append(ips, &dataplane.PeeringIPs{
+									Rule: &dataplane.PeeringIPs_Cidr{Cidr: subnetCIDR},
+								})
+							}
 						} else {
 							return nil, fmt.Errorf("unknown VPC subnet %s in peering %s / vpc %s", ipEntry.VPCSubnet, peeringName, vpcName) //nolint:goerr113
 						}
@@ -123,7 +385,6 @@ func buildDataplaneConfig(ag *gwintapi.GatewayAgent) (*dataplane.GatewayConfig,
 				}
 
 				for _, asEntry := range expose.As {
-					// TODO validate
 					switch {
 					case asEntry.CIDR != "":
 						as = append(as, &dataplane.PeeringAs{
@@ -138,52 +399,116 @@ func buildDataplaneConfig(ag *gwintapi.GatewayAgent) (*dataplane.GatewayConfig,
 					}
 				}
 
-				exposes = append(exposes, &dataplane.Expose{
+				familyExposes = append(familyExposes, &dataplane.Expose{
 					Ips: ips,
 					As:  as,
 				})
+			} else {
+				// an expose without ips would be ambiguous for the dataplane, a VPC exposing nothing has to omit the expose
+				if len(expose.IPs) == 0 {
+					return nil, fmt.Errorf("expose without ips in peering %s / vpc %s", peeringName, vpcName) //nolint:goerr113
+				}
+
+				// not entries of the VPCs are just a shorthand, so the dataplane gets the minimal set of prefixes
+				ipSet, asSet, err := expose.Sets(vpcSubnets[vpcName])
+				if err != nil {
+					return nil, fmt.Errorf("invalid expose in peering %s / vpc %s: %w", peeringName, vpcName, err)
+				}
+				// the addresses of the services would be exposed as a whole instead of the forwarded ports only
+				if len(expose.PortForwards) > 0 {
+					return nil, fmt.Errorf("port forwards aren't supported in peering %s / vpc %s", peeringName, vpcName) //nolint:goerr113
+				}
+
+				// the dataplane NATs the ips of an expose to its whole "as" pool, so the dual-stack exposes are split
+				// per address family to keep the NAT within the family
+				ipsByFamily, asByFamily := gwapi.SplitFamilies(ipSet), gwapi.SplitFamilies(asSet)
+				for idx := range ipsByFamily {
+					if ipsByFamily[idx].IsEmpty() || withExternal && idx == 1 {
+						continue
+					}
+
+					familyExpose := &dataplane.Expose{
+						Ips: []*dataplane.PeeringIPs{},
+						As:  []*dataplane.PeeringAs{},
+					}
+					for _, prefix := range ipsByFamily[idx].Strings() {
+						familyExpose.Ips = append(familyExpose.Ips, &dataplane.PeeringIPs{
+							Rule: &dataplane.PeeringIPs_Cidr{Cidr: prefix},
+						})
+					}
+					for _, prefix := range asByFamily[idx].Strings() {
+						familyExpose.As = append(familyExpose.As, &dataplane.PeeringAs{
+							Rule: &dataplane.PeeringAs_Cidr{Cidr: prefix},
+						})
+					}
+					familyExposes = append(familyExposes, familyExpose)
+				}
+				if len(familyExposes) == 0 {
+					return nil, fmt.Errorf("ipv6 exposes toward externals aren't supported in peering %s / vpc %s", peeringName, vpcName) //nolint:goerr113
+				}
 			}
 
-			p.For = append(p.For, &dataplane.PeeringEntryFor{
-				Vpc:    vpcName,
-				Expose: exposes,
-			})
+			// expose.Metric is rejected by the webhooks until the dataplane API supports the route metrics
+			exposes = append(exposes, familyExposes...)
 		}
 
-		peerings = append(peerings, p)
+		p.For = append(p.For, &dataplane.PeeringEntryFor{
+			Vpc:    vpcName,
+			Expose: exposes,
+		})
 	}
 
-	return &dataplane.GatewayConfig{
-		Generation: ag.Generation,
-		Device: &dataplane.Device{
-			Driver:   dataplane.PacketDriver_KERNEL,
-			Hostname: ag.Name,
-			Loglevel: dataplane.LogLevel_DEBUG,
-		},
-		Underlay: &dataplane.Underlay{
-			Vrfs: []*dataplane.VRF{
-				{
-					Name:       "default",
-					Interfaces: ifaces,
-					Router: &dataplane.RouterConfig{
-						Asn:       fmt.Sprintf("%d", ag.Spec.Gateway.ASN),
-						RouterId:  protoIP.Addr().String(),
-						Neighbors: neighs,
-						Ipv4Unicast: &dataplane.BgpAddressFamilyIPv4{
-							Networks:              []string{ag.Spec.Gateway.VTEPIP},
-							RedistributeConnected: false,
-							RedistributeStatic:    false,
-						},
-						L2VpnEvpn: &dataplane.BgpAddressFamilyL2VpnEvpn{
-							AdvertiseAllVni: true,
-						},
-					},
-				},
-			},
-		},
-		Overlay: &dataplane.Overlay{
-			Vpcs:     vpcs,
-			Peerings: peerings,
-		},
-	}, nil
+	return p, nil
+}
+
+// validateServiceChain checks that the inspection VPC is known and the appliance addresses are in its subnets
+func validateServiceChain(chain *gwapi.PeeringServiceChain, peered []string, vpcSubnets map[string]map[string][]string) error {
+	if err := chain.Validate(context.Background(), nil, "", peered); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	subnets, ok := vpcSubnets[chain.VPC]
+	if !ok {
+		return fmt.Errorf("unknown inspection vpc %s", chain.VPC) //nolint:goerr113
+	}
+
+	set, err := prefixset.Parse(gwapi.SubnetCIDRs(subnets)...)
+	if err != nil {
+		return fmt.Errorf("parsing inspection vpc %s subnets: %w", chain.VPC, err)
+	}
+	for _, ip := range []string{chain.IngressIP, chain.EgressIP} {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return fmt.Errorf("invalid appliance IP %s: %w", ip, err)
+		}
+		if !set.Contains(addr) {
+			return fmt.Errorf("appliance IP %s isn't in the subnets of inspection vpc %s", ip, chain.VPC) //nolint:goerr113
+		}
+	}
+
+	return nil
+}
+
+// validateVirtualService checks that the VPC of the virtual service is known and the backends are in its subnets
+func validateVirtualService(vs *gwapi.VirtualServiceSpec, vpcSubnets map[string]map[string][]string) error {
+	subnets, ok := vpcSubnets[vs.VPC]
+	if !ok {
+		return fmt.Errorf("unknown vpc %s", vs.VPC) //nolint:goerr113
+	}
+
+	set, err := prefixset.Parse(gwapi.SubnetCIDRs(subnets)...)
+	if err != nil {
+		return fmt.Errorf("parsing vpc %s subnets: %w", vs.VPC, err)
+	}
+	for _, backend := range vs.Backends {
+		addr, err := netip.ParseAddr(backend.IP)
+		if err != nil {
+			return fmt.Errorf("invalid backend IP %s: %w", backend.IP, err)
+		}
+		if !set.Contains(addr) {
+			return fmt.Errorf("backend %s isn't in the subnets of vpc %s", backend.IP, vs.VPC) //nolint:goerr113
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,418 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway-proto/pkg/dataplane"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func testAgent(peering gwapi.PeeringSpec) *gwintapi.GatewayAgent {
+	return &gwintapi.GatewayAgent{
+		ObjectMeta: kmetav1.ObjectMeta{Name: "gw", Generation: 1},
+		Spec: gwintapi.GatewayAgentSpec{
+			Gateway: gwapi.GatewaySpec{
+				ProtocolIP: "172.30.8.2/32",
+				VTEPIP:     "172.30.12.2/32",
+				VTEPMAC:    "ca:fe:ba:be:00:01",
+				ASN:        65534,
+			},
+			VPCs: map[string]gwintapi.VPCInfoData{
+				"vpc-1": {
+					VPCInfoSpec: gwapi.VPCInfoSpec{
+						VNI:     100,
+						Subnets: map[string]*gwapi.VPCInfoSubnet{"subnet-1": {CIDR: "10.1.1.0/24"}},
+					},
+					VPCInfoStatus: gwapi.VPCInfoStatus{InternalID: "00001"},
+				},
+				"vpc-2": {
+					VPCInfoSpec: gwapi.VPCInfoSpec{
+						VNI:     200,
+						Subnets: map[string]*gwapi.VPCInfoSubnet{"subnet-1": {CIDR: "10.1.1.0/24"}},
+					},
+					VPCInfoStatus: gwapi.VPCInfoStatus{InternalID: "00002"},
+				},
+			},
+			Peerings: map[string]gwapi.PeeringSpec{
+				"vpc-1--vpc-2": peering,
+			},
+		},
+	}
+}
+
+func exposeFor(t *testing.T, cfg *dataplane.GatewayConfig, vpcName string) *dataplane.Expose {
+	t.Helper()
+
+	require.Len(t, cfg.Overlay.Peerings, 1)
+	for _, entry := range cfg.Overlay.Peerings[0].For {
+		if entry.Vpc == vpcName {
+			require.Len(t, entry.Expose, 1)
+
+			return entry.Expose[0]
+		}
+	}
+	require.Fail(t, "vpc not found in peering", vpcName)
+
+	return nil
+}
+
+// peeringError checks that the peering of the test agent is skipped without failing the whole config and returns why
+func peeringError(t *testing.T, ag *gwintapi.GatewayAgent) error {
+	t.Helper()
+
+	cfg, err := buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.Empty(t, cfg.Overlay.Peerings)
+
+	peering := ag.Spec.Peerings["vpc-1--vpc-2"]
+	_, err = buildPeering("vpc-1--vpc-2", &peering, subnetsOf(ag.Spec.VPCs), ag.Spec.Externals)
+
+	return err
+}
+
+func TestBuildDataplaneConfigPeeringNot(t *testing.T) {
+	cfg, err := buildDataplaneConfig(testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{CIDR: "10.1.1.0/24"}, {Not: "10.1.1.0/25"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/24"}, {Not: "192.168.1.128/25"}},
+			}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.2.0/24"}},
+			}}},
+		},
+	}))
+	require.NoError(t, err)
+
+	vpc1 := exposeFor(t, cfg, "vpc-1")
+	require.Equal(t, []*dataplane.PeeringIPs{{Rule: &dataplane.PeeringIPs_Cidr{Cidr: "10.1.1.128/25"}}}, vpc1.Ips)
+	require.Equal(t, []*dataplane.PeeringAs{{Rule: &dataplane.PeeringAs_Cidr{Cidr: "192.168.1.0/25"}}}, vpc1.As)
+
+	vpc2 := exposeFor(t, cfg, "vpc-2")
+	require.Equal(t, []*dataplane.PeeringIPs{{Rule: &dataplane.PeeringIPs_Cidr{Cidr: "10.1.1.0/24"}}}, vpc2.Ips)
+	require.Equal(t, []*dataplane.PeeringAs{{Rule: &dataplane.PeeringAs_Cidr{Cidr: "192.168.2.0/24"}}}, vpc2.As)
+}
+
+func TestBuildDataplaneConfigPeeringPoolMismatch(t *testing.T) {
+	err := peeringError(t, testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{CIDR: "10.1.1.0/24"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/25"}},
+			}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+			}}},
+		},
+	}))
+	require.Error(t, err)
+}
+
+func TestBuildDataplaneConfigSkipsInvalidPeering(t *testing.T) {
+	expose := []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}
+	ag := testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{"vpc-1": {Expose: expose}, "vpc-2": {Expose: expose}},
+	})
+	ag.Spec.Peerings["vpc-1--vpc-3"] = gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{"vpc-1": {Expose: expose}, "vpc-3": {Expose: expose}},
+	}
+
+	cfg, err := buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.Len(t, cfg.Overlay.Peerings, 1)
+	require.Equal(t, "vpc-1--vpc-2", cfg.Overlay.Peerings[0].Name)
+
+	peering := ag.Spec.Peerings["vpc-1--vpc-3"]
+	_, err = buildPeering("vpc-1--vpc-3", &peering, subnetsOf(ag.Spec.VPCs), ag.Spec.Externals)
+	require.ErrorContains(t, err, "unknown vpc or external vpc-3 in peering vpc-1--vpc-3")
+}
+
+func TestBuildDataplaneConfigPeeringConsumeOnly(t *testing.T) {
+	err := peeringError(t, testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{CIDR: "10.1.1.0/24"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/24"}},
+			}}},
+			"vpc-2": nil,
+		},
+	}))
+	require.ErrorContains(t, err, "consume-only vpc vpc-2 in peering vpc-1--vpc-2 isn't supported")
+
+	err = peeringError(t, testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{"vpc-1": {}, "vpc-2": nil},
+	}))
+	require.ErrorContains(t, err, "consume-only vpc vpc-1 in peering vpc-1--vpc-2 isn't supported")
+
+	err = peeringError(t, testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+		},
+	}))
+	require.Error(t, err)
+}
+
+func TestBuildDataplaneConfigPeeringServiceChain(t *testing.T) {
+	spec := gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{CIDR: "10.1.1.0/24"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/24"}},
+			}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.2.0/24"}},
+			}}},
+		},
+		ServiceChain: &gwapi.PeeringServiceChain{VPC: "vpc-fw", IngressIP: "10.9.0.10", EgressIP: "10.9.0.11"},
+	}
+
+	err := peeringError(t, testAgent(spec))
+	require.ErrorContains(t, err, "unknown inspection vpc")
+
+	ag := testAgent(spec)
+	ag.Spec.VPCs["vpc-fw"] = gwintapi.VPCInfoData{
+		VPCInfoSpec: gwapi.VPCInfoSpec{
+			VNI:     300,
+			Subnets: map[string]*gwapi.VPCInfoSubnet{"subnet-1": {CIDR: "10.9.0.0/24"}},
+		},
+		VPCInfoStatus: gwapi.VPCInfoStatus{InternalID: "00003"},
+	}
+	err = peeringError(t, ag)
+	require.ErrorContains(t, err, "service chain in peering vpc-1--vpc-2 isn't supported")
+
+	ag.Spec.Peerings["vpc-1--vpc-2"].ServiceChain.EgressIP = "10.9.1.11"
+	err = peeringError(t, ag)
+	require.ErrorContains(t, err, "isn't in the subnets")
+}
+
+func TestBuildDataplaneConfigPeeringTranslation(t *testing.T) {
+	spec := gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs:         []gwapi.PeeringEntryIP{{CIDR: "10.1.1.0/24"}},
+				Translation: &gwapi.PeeringEntryTranslation{Mode: gwapi.PeeringTranslationModeNAT64},
+			}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+			}}},
+		},
+	}
+
+	err := peeringError(t, testAgent(spec))
+	require.ErrorContains(t, err, "translation in peering vpc-1--vpc-2 isn't supported")
+
+	spec.Peering["vpc-1"].Expose[0].Translation.Prefix = "2001:db8::/80"
+	err = peeringError(t, testAgent(spec))
+	require.ErrorContains(t, err, "invalid translation")
+}
+
+func TestBuildDataplaneConfigPeeringDualStack(t *testing.T) {
+	ag := testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/24"}, {CIDR: "fd01::/64"}},
+			}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+			}}},
+		},
+	})
+	for _, vpcName := range []string{"vpc-1", "vpc-2"} {
+		vpc := ag.Spec.VPCs[vpcName]
+		vpc.Subnets["subnet-1"].CIDRs = []string{"fd00:1::/64"}
+		ag.Spec.VPCs[vpcName] = vpc
+	}
+
+	cfg, err := buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.Len(t, cfg.Overlay.Peerings, 1)
+
+	for _, entry := range cfg.Overlay.Peerings[0].For {
+		require.Len(t, entry.Expose, 2, "dual-stack expose of %s must be split per address family", entry.Vpc)
+		require.Equal(t, "10.1.1.0/24", entry.Expose[0].Ips[0].GetCidr())
+		require.Equal(t, "fd00:1::/64", entry.Expose[1].Ips[0].GetCidr())
+
+		if entry.Vpc == "vpc-1" {
+			require.Equal(t, "192.168.1.0/24", entry.Expose[0].As[0].GetCidr())
+			require.Equal(t, "fd01::/64", entry.Expose[1].As[0].GetCidr())
+		} else {
+			require.Empty(t, entry.Expose[0].As)
+			require.Empty(t, entry.Expose[1].As)
+		}
+	}
+}
+
+func TestBuildDataplaneConfigExternal(t *testing.T) {
+	ag := testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+			"ext-1": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "0.0.0.0/0"}, {Not: "10.0.0.0/8"}}}}},
+		},
+	})
+	ag.Spec.Externals = map[string]gwintapi.ExternalData{
+		"ext-1": {
+			ExternalSpec: gwapi.ExternalSpec{
+				Interface: "eth1",
+				VLAN:      100,
+				IPs:       []string{"192.168.1.2/30"},
+				MTU:       1500,
+				ASN:       65100,
+				Neighbors: []gwapi.ExternalBGPNeighbor{{IP: "192.168.1.1", ASN: 65000}},
+			},
+			ExternalStatus: gwapi.ExternalStatus{InternalID: "00009"},
+		},
+	}
+
+	cfg, err := buildDataplaneConfig(ag)
+	require.NoError(t, err)
+
+	require.Contains(t, cfg.Overlay.Vpcs, &dataplane.VPC{Name: "ext-1", Id: "00009"})
+
+	require.Len(t, cfg.Underlay.Vrfs, 2)
+	vrf := cfg.Underlay.Vrfs[1]
+	require.Equal(t, "ext-1", vrf.Name)
+	require.Equal(t, []*dataplane.Interface{{
+		Name:       "eth1.100",
+		Ipaddrs:    []string{"192.168.1.2/30"},
+		Type:       dataplane.IfType_IF_TYPE_VLAN,
+		Role:       dataplane.IfRole_IF_ROLE_EXTERNAL,
+		Vlan:       ptr.To(uint32(100)),
+		SystemName: ptr.To("eth1"),
+		Mtu:        ptr.To(uint32(1500)),
+	}}, vrf.Interfaces)
+	require.Equal(t, "65100", vrf.Router.Asn)
+	require.Equal(t, "172.30.8.2", vrf.Router.RouterId)
+	require.Equal(t, []*dataplane.BgpNeighbor{{
+		Address:    "192.168.1.1",
+		RemoteAsn:  "65000",
+		AfActivate: []dataplane.BgpAF{dataplane.BgpAF_IPV4_UNICAST},
+	}}, vrf.Router.Neighbors)
+
+	ext := exposeFor(t, cfg, "ext-1")
+	require.Equal(t, []*dataplane.PeeringIPs{
+		{Rule: &dataplane.PeeringIPs_Cidr{Cidr: "0.0.0.0/0"}},
+		{Rule: &dataplane.PeeringIPs_Not{Not: "10.0.0.0/8"}},
+	}, ext.Ips)
+
+	// only the IPv4 part of the dual-stack exposes is passed with the externals
+	vpc1 := ag.Spec.VPCs["vpc-1"]
+	vpc1.Subnets["subnet-1"].CIDRs = []string{"fd00:1::/64"}
+	cfg, err = buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.Equal(t, []*dataplane.PeeringIPs{
+		{Rule: &dataplane.PeeringIPs_Cidr{Cidr: "10.1.1.0/24"}},
+	}, exposeFor(t, cfg, "vpc-1").Ips)
+
+	ag.Spec.Peerings["vpc-1--vpc-2"].Peering["ext-1"].Expose[0].IPs = []gwapi.PeeringEntryIP{{CIDR: "::/0"}}
+	err = peeringError(t, ag)
+	require.ErrorContains(t, err, "ipv6 routes of external aren't supported")
+	ag.Spec.Peerings["vpc-1--vpc-2"].Peering["ext-1"].Expose[0].IPs = []gwapi.PeeringEntryIP{{CIDR: "0.0.0.0/0"}}
+
+	// the unsupported externals are skipped together with their peerings
+	ext1 := ag.Spec.Externals["ext-1"]
+	ext1.Export = []gwapi.ExternalPrefixFilter{{CIDR: "10.1.1.0/24"}}
+	ag.Spec.Externals["ext-1"] = ext1
+	cfg, err = buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.NotContains(t, cfg.Overlay.Vpcs, &dataplane.VPC{Name: "ext-1", Id: "00009"})
+	require.Len(t, cfg.Underlay.Vrfs, 1)
+	require.Empty(t, cfg.Overlay.Peerings)
+
+	ext1.Export = nil
+	ext1.Neighbors = nil
+	ext1.StaticRoutes = []gwapi.ExternalStaticRoute{{NextHop: "192.168.1.1", Prefixes: []string{"0.0.0.0/0"}}}
+	ag.Spec.Externals["ext-1"] = ext1
+	cfg, err = buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.Len(t, cfg.Underlay.Vrfs, 1)
+	require.Empty(t, cfg.Overlay.Peerings)
+}
+
+func TestBuildDataplaneConfigVirtualService(t *testing.T) {
+	ag := testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/24"}},
+			}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.2.0/24"}},
+			}}},
+		},
+	})
+	ag.Spec.VirtualServices = map[string]gwapi.VirtualServiceSpec{
+		"web": {VPC: "vpc-1", VIP: "192.168.1.10", Protocol: "tcp", Port: 443, Backends: []gwapi.VirtualServiceBackend{{IP: "10.1.2.10", Port: 8443}}},
+	}
+
+	vs := ag.Spec.VirtualServices["web"]
+	require.ErrorContains(t, validateVirtualService(&vs, subnetsOf(ag.Spec.VPCs)), "backend 10.1.2.10 isn't in the subnets of vpc vpc-1")
+
+	// the services are skipped without failing the peerings of the gateway
+	cfg, err := buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.Len(t, cfg.Overlay.Peerings, 1)
+
+	ag.Spec.VirtualServices["web"].Backends[0].IP = "10.1.1.10"
+	cfg, err = buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.Len(t, cfg.Overlay.Peerings, 1)
+}
+
+func TestBuildDataplaneConfigFlowExport(t *testing.T) {
+	spec := gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/24"}},
+			}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.2.0/24"}},
+			}}},
+		},
+		FlowExport: true,
+	}
+
+	err := peeringError(t, testAgent(spec))
+	require.ErrorContains(t, err, "flow export in peering vpc-1--vpc-2 isn't supported")
+
+	spec.FlowExport = false
+	ag := testAgent(spec)
+	ag.Spec.Gateway.FlowExport = &gwapi.GatewayFlowExport{
+		Collectors: []gwapi.GatewayFlowCollector{{Address: "10.9.0.10:4739", Protocol: gwapi.FlowExportProtocolIPFIX}},
+	}
+	cfg, err := buildDataplaneConfig(ag)
+	require.NoError(t, err)
+	require.Len(t, cfg.Overlay.Peerings, 1)
+}
+
+func TestBuildDataplaneConfigQoS(t *testing.T) {
+	err := peeringError(t, testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {
+				Expose: []gwapi.PeeringEntryExpose{{
+					IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+					As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/24"}},
+				}},
+				QoS: &gwapi.PeeringEntryQoS{Egress: &gwapi.PeeringQoSPolicy{Bandwidth: "100M"}},
+			},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.2.0/24"}},
+			}}},
+		},
+	}))
+	require.ErrorContains(t, err, "qos in peering vpc-1--vpc-2 / vpc vpc-1 isn't supported")
+}
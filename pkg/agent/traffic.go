@@ -0,0 +1,142 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+)
+
+// Metrics of the traffic crossing the peerings expected from the dataplane on its metrics endpoint, all of them are
+// labeled with the name of the peering (as passed to the dataplane) and the VPC, the bytes and packets are also
+// labeled with the direction relative to the VPC. The dataplane API (gateway-proto) doesn't define any metrics, so
+// these names and labels are the contract the dataplane has to follow, the agent reports no traffic counters (rather
+// than zeros) until the dataplane exports them.
+const (
+	metricPeeringBytes       = "peering_bytes_total"
+	metricPeeringPackets     = "peering_packets_total"
+	metricPeeringNATSessions = "peering_nat_sessions"
+
+	labelPeering   = "peering"
+	labelVPC       = "vpc"
+	labelDirection = "direction"
+
+	directionEgress  = "egress"
+	directionIngress = "ingress"
+)
+
+// scrapeTraffic returns the traffic counters of the peerings of the agent scraped from the dataplane metrics endpoint
+func (svc *Service) scrapeTraffic(ctx context.Context, ag *gwintapi.GatewayAgent) (map[string]gwintapi.PeeringAgentStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+svc.cfg.DataplaneMetricsAddress+"/metrics", nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("scraping dataplane metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scraping dataplane metrics: unexpected status %s", resp.Status) //nolint:goerr113
+	}
+
+	return parseTraffic(resp.Body, ag)
+}
+
+// parseTraffic returns the traffic counters of the peerings of the agent from the metrics in the Prometheus text
+// format, the counters of the peerings and VPCs unknown to the agent are ignored. It fails if the agent has peerings
+// but the dataplane exports none of the peering metrics, so no counters are published as if there was no traffic.
+func parseTraffic(in io.Reader, ag *gwintapi.GatewayAgent) (map[string]gwintapi.PeeringAgentStatus, error) {
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return nil, fmt.Errorf("parsing dataplane metrics: %w", err)
+	}
+
+	names := []string{metricPeeringBytes, metricPeeringPackets, metricPeeringNATSessions}
+	if len(ag.Spec.Peerings) > 0 && !slices.ContainsFunc(names, func(name string) bool { return families[name] != nil }) {
+		return nil, fmt.Errorf("dataplane doesn't export any of the peering metrics %v", names) //nolint:goerr113
+	}
+
+	res := map[string]gwintapi.PeeringAgentStatus{}
+	for _, name := range names {
+		family, ok := families[name]
+		if !ok {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			peeringName, vpcName := labels[labelPeering], labels[labelVPC]
+			if _, ok := ag.Spec.Peerings[peeringName].Peering[vpcName]; !ok {
+				continue
+			}
+
+			value := metricValue(metric)
+
+			peering := res[peeringName]
+			if peering.VPCs == nil {
+				peering.VPCs = map[string]gwintapi.PeeringVPCAgentStatus{}
+			}
+			vpc := peering.VPCs[vpcName]
+
+			switch {
+			case name == metricPeeringNATSessions:
+				vpc.NATSessions += value
+			case name == metricPeeringBytes && labels[labelDirection] == directionEgress:
+				vpc.EgressBytes += value
+			case name == metricPeeringBytes && labels[labelDirection] == directionIngress:
+				vpc.IngressBytes += value
+			case name == metricPeeringPackets && labels[labelDirection] == directionEgress:
+				vpc.EgressPackets += value
+			case name == metricPeeringPackets && labels[labelDirection] == directionIngress:
+				vpc.IngressPackets += value
+			default:
+				continue
+			}
+
+			peering.VPCs[vpcName] = vpc
+			res[peeringName] = peering
+		}
+	}
+
+	return res, nil
+}
+
+func metricValue(metric *dto.Metric) uint64 {
+	value := metric.GetUntyped().GetValue()
+	switch {
+	case metric.GetCounter() != nil:
+		value = metric.GetCounter().GetValue()
+	case metric.GetGauge() != nil:
+		value = metric.GetGauge().GetValue()
+	}
+
+	if value < 0 || math.IsNaN(value) {
+		return 0
+	}
+	if value >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+
+	return uint64(value)
+}
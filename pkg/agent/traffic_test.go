@@ -0,0 +1,72 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+)
+
+func TestParseTraffic(t *testing.T) {
+	ag := testAgent(gwapi.PeeringSpec{
+		Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.1.1.0/24"}}}}},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+		},
+	})
+
+	metrics := `# HELP peering_bytes_total Bytes crossing the peering
+# TYPE peering_bytes_total counter
+peering_bytes_total{peering="vpc-1--vpc-2",vpc="vpc-1",direction="egress"} 1500
+peering_bytes_total{peering="vpc-1--vpc-2",vpc="vpc-1",direction="ingress"} 300
+peering_bytes_total{peering="vpc-1--vpc-2",vpc="vpc-2",direction="egress"} 300
+peering_bytes_total{peering="vpc-1--vpc-2",vpc="vpc-2",direction="ingress"} 1500
+peering_bytes_total{peering="vpc-1--vpc-2",vpc="vpc-3",direction="egress"} 42
+peering_bytes_total{peering="other",vpc="vpc-1",direction="egress"} 42
+# TYPE peering_packets_total counter
+peering_packets_total{peering="vpc-1--vpc-2",vpc="vpc-1",direction="egress"} 3
+peering_packets_total{peering="vpc-1--vpc-2",vpc="vpc-1",direction="ingress"} 2
+peering_packets_total{peering="vpc-1--vpc-2",vpc="vpc-1",direction="sideways"} 42
+# TYPE peering_nat_sessions gauge
+peering_nat_sessions{peering="vpc-1--vpc-2",vpc="vpc-1"} 7
+# TYPE unrelated_total counter
+unrelated_total{peering="vpc-1--vpc-2",vpc="vpc-1"} 42
+`
+
+	res, err := parseTraffic(strings.NewReader(metrics), ag)
+	require.NoError(t, err)
+	require.Equal(t, map[string]gwintapi.PeeringAgentStatus{
+		"vpc-1--vpc-2": {VPCs: map[string]gwintapi.PeeringVPCAgentStatus{
+			"vpc-1": {PeeringVPCTrafficStatus: gwapi.PeeringVPCTrafficStatus{
+				EgressBytes: 1500, EgressPackets: 3, IngressBytes: 300, IngressPackets: 2, NATSessions: 7,
+			}},
+			"vpc-2": {PeeringVPCTrafficStatus: gwapi.PeeringVPCTrafficStatus{
+				EgressBytes: 300, IngressBytes: 1500,
+			}},
+		}},
+	}, res)
+
+	_, err = parseTraffic(strings.NewReader("peering_bytes_total{peering=}"), ag)
+	require.Error(t, err)
+
+	// a dataplane not exporting the peering metrics yet
+	metrics = `# HELP process_cpu_seconds_total Total user and system CPU time spent in seconds.
+# TYPE process_cpu_seconds_total counter
+process_cpu_seconds_total 12.5
+# HELP process_resident_memory_bytes Resident memory size in bytes.
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes 1.048576e+08
+`
+	_, err = parseTraffic(strings.NewReader(metrics), ag)
+	require.ErrorContains(t, err, "dataplane doesn't export any of the peering metrics")
+
+	ag.Spec.Peerings = nil
+	res, err = parseTraffic(strings.NewReader(metrics), ag)
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
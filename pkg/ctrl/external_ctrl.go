@@ -0,0 +1,79 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+
+type ExternalReconciler struct {
+	kclient.Client
+	apiReader kclient.Reader
+}
+
+func SetupExternalReconcilerWith(mgr kctrl.Manager) error {
+	r := &ExternalReconciler{
+		Client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("External").
+		For(&gwapi.External{}).
+		Watches(&gwapi.External{}, handler.EnqueueRequestsFromMapFunc(enqueueInternalIDDuplicates(r, internalIDKindExternal))).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(enqueueInternalIDDuplicates(r, internalIDKindExternal))).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExternalReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	ext := &gwapi.External{}
+	if err := r.Get(ctx, req.NamespacedName, ext); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("getting external: %w", err)
+	}
+
+	if ext.DeletionTimestamp != nil {
+		l.Info("External is being deleted, skipping", "name", req.Name, "namespace", req.Namespace)
+
+		return kctrl.Result{}, nil
+	}
+
+	// the gateway controller skips the external and its peerings, so the reason is reported in the status
+	status := gwapi.ExternalStatus{InternalID: ext.Status.InternalID}
+	if err := ext.Spec.DataplaneUnsupported(); err != nil {
+		status.Error = "not configured on the gateway: " + err.Error()
+	}
+
+	if ext.Status != status {
+		l.Info("Updating External status", "name", req.Name, "namespace", req.Namespace, "error", status.Error)
+
+		ext.Status = status
+		if err := r.Status().Update(ctx, ext); err != nil {
+			return kctrl.Result{}, fmt.Errorf("updating external status: %w", err)
+		}
+	}
+
+	return ensureInternalID(ctx, r, r.apiReader, internalIDKindExternal, ext, &ext.Status.InternalID)
+}
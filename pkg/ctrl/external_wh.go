@@ -0,0 +1,59 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-external,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=externals,verbs=create;update;delete,versions=v1alpha1,name=mexternal.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gateway-githedgehog-com-v1alpha1-external,mutating=false,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=externals,verbs=create;update;delete,versions=v1alpha1,name=vexternal.kb.io,admissionReviewVersions=v1
+
+type ExternalWebhook struct {
+	kclient.Reader
+}
+
+func SetupExternalWebhookWith(mgr kctrl.Manager) error {
+	w := &ExternalWebhook{
+		Reader: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewWebhookManagedBy(mgr).
+		For(&gwapi.External{}).
+		WithDefaulter(FromTypedDefaulter(w)).
+		WithValidator(FromTypedValidator(w)).
+		Complete(); err != nil {
+		return fmt.Errorf("creating webhook: %w", err) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (w *ExternalWebhook) Default(_ context.Context, obj *gwapi.External) error {
+	obj.Default()
+
+	return nil
+}
+
+func (w *ExternalWebhook) ValidateCreate(ctx context.Context, obj *gwapi.External) (admission.Warnings, error) {
+	return nil, obj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *ExternalWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.External, newObj *gwapi.External) (admission.Warnings, error) {
+	// TODO validate diff between oldObj and newObj if needed
+	_ = oldObj
+
+	return nil, newObj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *ExternalWebhook) ValidateDelete(_ context.Context, _ *gwapi.External) (admission.Warnings, error) {
+	return nil, nil
+}
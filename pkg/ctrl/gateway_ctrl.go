@@ -8,7 +8,9 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 	"time"
@@ -56,6 +58,11 @@ const (
 
 	// TODO switch to unix socket: "unix://" + filepath.Join(dataplaneRunMountPath, dataplaneSocketName),
 	dataplaneAPIAddress = "[::1]:50051"
+
+	// agentNameSep joins the parts of the names of the peerings and the virtual services passed to the gateways (and
+	// so to the dataplane), it's never part of the object names (DNS subdomains) so the joined names are unambiguous
+	// and only use the characters allowed in the object names
+	agentNameSep = ".."
 )
 
 //go:embed alloy_config.tmpl
@@ -72,6 +79,12 @@ var alloyValuesTmpl string
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gateways/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringmeshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringrequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringacceptances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=natpools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=virtualservices,verbs=get;list;watch
 
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
@@ -80,6 +93,26 @@ var alloyValuesTmpl string
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=helm.cattle.io,resources=helmcharts,verbs=get;list;watch;create;update;patch;delete
 
+// agentName returns the name of the peering or virtual service passed to the gateways, which is its name in the
+// namespace of the gateway and "<namespace>..<name>" otherwise
+func agentName(gwNamespace, namespace, name string) string {
+	if namespace == gwNamespace {
+		return name
+	}
+
+	return namespace + agentNameSep + name
+}
+
+// meshPeeringName returns the name of the pair of the mesh passed to the gateways
+func meshPeeringName(namespace, mesh, pair string) string {
+	return strings.Join([]string{namespace, "mesh", mesh, pair}, agentNameSep)
+}
+
+// requestPeeringName returns the name of the peering of the accepted request passed to the gateways
+func requestPeeringName(namespace, request, remoteNamespace string) string {
+	return strings.Join([]string{namespace, "request", request, remoteNamespace}, agentNameSep)
+}
+
 type GatewayReconciler struct {
 	kclient.Client
 	cfg *meta.GatewayCtrlConfig
@@ -100,6 +133,12 @@ func SetupGatewayReconcilerWith(mgr kctrl.Manager, cfg *meta.GatewayCtrlConfig)
 		For(&gwapi.Gateway{}).
 		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
 		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
+		Watches(&gwapi.External{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
+		Watches(&gwapi.PeeringMesh{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
+		Watches(&gwapi.PeeringRequest{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
+		Watches(&gwapi.PeeringAcceptance{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
+		Watches(&gwapi.VirtualService{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
+		Watches(&gwapi.NATPool{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
 		Complete(r); err != nil {
 		return fmt.Errorf("setting up controller: %w", err)
 	}
@@ -158,6 +197,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 		return kctrl.Result{}, fmt.Errorf("listing vpcinfos: %w", err)
 	}
 	vpcs := map[string]gwintapi.VPCInfoData{}
+	vpcNamespaces := map[string]string{}
 	for _, vpc := range vpcList.Items {
 		if !vpc.IsReady() {
 			l.Info("VPCInfo not ready, retrying", "name", vpc.Name, "namespace", vpc.Namespace)
@@ -169,6 +209,61 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 			VPCInfoSpec:   vpc.Spec,
 			VPCInfoStatus: vpc.Status,
 		}
+		vpcNamespaces[vpc.Name] = vpc.Namespace
+	}
+
+	extList := &gwapi.ExternalList{}
+	if err := r.List(ctx, extList, kclient.InNamespace(gw.Namespace), kclient.MatchingLabels{
+		gwapi.LabelGateway: gw.Name,
+	}); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing externals: %w", err)
+	}
+	externals := map[string]gwintapi.ExternalData{}
+	for _, ext := range extList.Items {
+		if !ext.IsReady() {
+			l.Info("External not ready, retrying", "name", ext.Name, "namespace", ext.Namespace)
+
+			return kctrl.Result{Requeue: true, RequeueAfter: 1 * time.Second}, nil
+		}
+
+		// the webhook rejects it, but the externals accepted before are only skipped with their peerings so they
+		// don't break the others
+		if err := ext.Spec.DataplaneUnsupported(); err != nil {
+			l.Info("External isn't supported by the dataplane, skipping", "name", ext.Name, "namespace", ext.Namespace, "reason", err.Error())
+
+			continue
+		}
+
+		// the webhooks reject it as well, the VPCs and the externals share the names in the gateway config
+		if ns, isVPC := vpcNamespaces[ext.Name]; isVPC {
+			l.Info("External has the same name as a VPC, skipping", "name", ext.Name, "namespace", ext.Namespace, "vpcNamespace", ns)
+
+			continue
+		}
+
+		externals[ext.Name] = gwintapi.ExternalData{
+			ExternalSpec:   ext.Spec,
+			ExternalStatus: ext.Status,
+		}
+	}
+
+	// tenants could only peer VPCs (and externals) from their own namespace, cross-namespace peerings are only
+	// possible using the peering requests accepted by the other side
+	exists := func(namespace, name string) bool {
+		if ns, isVPC := vpcNamespaces[name]; isVPC {
+			return ns == namespace
+		}
+		_, isExternal := externals[name]
+
+		return isExternal && namespace == gw.Namespace
+	}
+	isExternal := func(namespace, name string) bool {
+		if _, isVPC := vpcNamespaces[name]; isVPC {
+			return false
+		}
+		_, isExternal := externals[name]
+
+		return isExternal && namespace == gw.Namespace
 	}
 
 	peeringList := &gwapi.PeeringList{}
@@ -176,12 +271,24 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 		return kctrl.Result{}, fmt.Errorf("listing peerings: %w", err)
 	}
 	peerings := map[string]gwapi.PeeringSpec{}
+	now := time.Now()
+	nextWindow := time.Time{}
 	for _, peering := range peeringList.Items {
+		state, next := peering.Spec.WindowState(now)
+		if !next.IsZero() && (nextWindow.IsZero() || next.Before(nextWindow)) {
+			nextWindow = next
+		}
+		if state != gwapi.PeeringWindowStateActive {
+			l.Info("Peering isn't active, skipping", "peering", peering.Name, "ns", peering.Namespace, "state", state)
+
+			continue
+		}
+
 		missingVPC := false
 
 		for peerVPC := range peering.Spec.Peering {
-			if _, exists := vpcs[peerVPC]; !exists {
-				l.Info("Peered VPC not found, skipping", "peering", peering.Name, "vpc", peerVPC, "ns", peering.Namespace)
+			if !exists(peering.Namespace, peerVPC) {
+				l.Info("Peered VPC or external not found, skipping", "peering", peering.Name, "vpc", peerVPC, "ns", peering.Namespace)
 
 				missingVPC = true
 
@@ -189,11 +296,114 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 			}
 		}
 
+		if chain := peering.Spec.ServiceChain; chain != nil && !missingVPC && !exists(peering.Namespace, chain.VPC) {
+			l.Info("Inspection VPC not found, skipping", "peering", peering.Name, "vpc", chain.VPC, "ns", peering.Namespace)
+
+			missingVPC = true
+		}
+
 		if missingVPC {
 			continue
 		}
 
-		peerings[peering.Name] = peering.Spec
+		// the webhook rejects it, but the peerings accepted before are only skipped so they don't break the others
+		if err := peering.Spec.DataplaneUnsupported(func(name string) bool { return isExternal(peering.Namespace, name) }); err != nil {
+			l.Info("Peering isn't supported by the dataplane, skipping", "peering", peering.Name, "ns", peering.Namespace, "reason", err.Error())
+
+			continue
+		}
+
+		resolved, _, pending, err := resolveASPools(ctx, r, &peering)
+		if err != nil {
+			return kctrl.Result{}, err
+		}
+		if pending != "" {
+			l.Info("NAT pool allocation pending, skipping", "peering", peering.Name, "ns", peering.Namespace, "reason", pending)
+
+			continue
+		}
+
+		name := agentName(gw.Namespace, peering.Namespace, peering.Name)
+		peerings[name] = resolved.Spec
+	}
+
+	meshList := &gwapi.PeeringMeshList{}
+	if err := r.List(ctx, meshList); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peering meshes: %w", err)
+	}
+	// meshes are expanded into the pairwise peerings so the dataplane only ever sees pairs
+	meshPeerings, _ := expandMeshes(meshList.Items, peeringList.Items, now, exists, isExternal)
+	maps.Copy(peerings, meshPeerings)
+
+	reqList := &gwapi.PeeringRequestList{}
+	if err := r.List(ctx, reqList); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peering requests: %w", err)
+	}
+	accList := &gwapi.PeeringAcceptanceList{}
+	if err := r.List(ctx, accList); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peering acceptances: %w", err)
+	}
+	// accepted requests for the VPCs already peered by a peering or a mesh are reported as conflicts and skipped
+	reqPeerings, _ := expandPeeringRequests(ctx, r, reqList.Items, accList.Items, peeredPairs(peeringList.Items, meshPeerings, now), exists, isExternal)
+	maps.Copy(peerings, reqPeerings)
+
+	// the routes re-exposed by the transit VPCs are passed to the dataplane as the exposes of the transit VPCs
+	nsTransitEdges := map[string][]transitEdge{}
+	for _, peering := range peeringList.Items {
+		name := agentName(gw.Namespace, peering.Namespace, peering.Name)
+
+		spec, active := peerings[name]
+		if !active || !slices.ContainsFunc(slices.Collect(maps.Values(spec.Peering)), func(e *gwapi.PeeringEntry) bool { return e != nil && e.Transit }) {
+			continue
+		}
+
+		edges, ok := nsTransitEdges[peering.Namespace]
+		if !ok {
+			var err error
+			if edges, err = transitEdges(ctx, r, peering.Namespace, nil); err != nil {
+				return kctrl.Result{}, err
+			}
+			nsTransitEdges[peering.Namespace] = edges
+		}
+
+		transit, err := computeTransit(edges, peering.Name)
+		if err != nil {
+			l.Info("Invalid transit, skipping", "peering", peering.Name, "ns", peering.Namespace, "reason", err.Error())
+			delete(peerings, name)
+
+			continue
+		}
+
+		spec = *withTransitRoutes(&spec, transit[peering.Name])
+		if slices.ContainsFunc(slices.Collect(maps.Values(spec.Peering)), func(e *gwapi.PeeringEntry) bool { return e == nil || len(e.Expose) == 0 }) {
+			l.Info("Transit VPC has nothing to re-expose yet, skipping", "peering", peering.Name, "ns", peering.Namespace)
+			delete(peerings, name)
+
+			continue
+		}
+		peerings[name] = spec
+	}
+
+	vsList := &gwapi.VirtualServiceList{}
+	if err := r.List(ctx, vsList); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing virtual services: %w", err)
+	}
+	services := map[string]gwapi.VirtualServiceSpec{}
+	for _, vs := range vsList.Items {
+		if !exists(vs.Namespace, vs.Spec.VPC) {
+			l.Info("Virtual service VPC not found, skipping", "service", vs.Name, "vpc", vs.Spec.VPC, "ns", vs.Namespace)
+
+			continue
+		}
+
+		// the webhook rejects it, but the services accepted before are only skipped and reported in their status
+		if err := vs.Spec.DataplaneUnsupported(); err != nil {
+			l.Info("Virtual service isn't supported by the dataplane, skipping", "service", vs.Name, "ns", vs.Namespace, "reason", err.Error())
+
+			continue
+		}
+
+		services[agentName(gw.Namespace, vs.Namespace, vs.Name)] = vs.Spec
 	}
 
 	gwAg := &gwintapi.GatewayAgent{ObjectMeta: kmetav1.ObjectMeta{Namespace: gw.Namespace, Name: gw.Name}}
@@ -206,18 +416,30 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 
 		gwAg.Spec.AgentVersion = version.Version
 		gwAg.Spec.Gateway = gw.Spec
+		// the webhook rejects it, but the gateways accepted before would otherwise pass it to the agents
+		if fe := gwAg.Spec.Gateway.FlowExport; fe != nil {
+			l.Info("Flow export isn't supported by the dataplane, skipping", "reason", fe.DataplaneUnsupported().Error())
+			gwAg.Spec.Gateway.FlowExport = nil
+		}
 		gwAg.Spec.VPCs = vpcs
+		gwAg.Spec.Externals = externals
 		gwAg.Spec.Peerings = peerings
+		gwAg.Spec.VirtualServices = services
 
 		return nil
 	}); err != nil {
 		return kctrl.Result{}, fmt.Errorf("creating or updating gateway agent: %w", err)
 	}
 
-	if err := r.deployGateway(ctx, gw); err != nil {
+	if err := r.deployGateway(ctx, gw, externals); err != nil {
 		return kctrl.Result{}, fmt.Errorf("deploying gateway: %w", err)
 	}
 
+	// reconcile again when the next peering becomes active or expires
+	if !nextWindow.IsZero() {
+		return kctrl.Result{RequeueAfter: nextWindow.Sub(now)}, nil
+	}
+
 	return kctrl.Result{}, nil
 }
 
@@ -229,7 +451,7 @@ func entityName(gwName string, t ...string) string {
 	return fmt.Sprintf("gw--%s--%s", gwName, strings.Join(t, "-"))
 }
 
-func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway) error {
+func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway, externals map[string]gwintapi.ExternalData) error {
 	saName := entityName(gw.Name)
 
 	{
@@ -319,11 +541,17 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 	}
 
 	{
-		agCfgData, err := kyaml.Marshal(&meta.AgentConfig{
-			Name:             gw.Name,
-			Namespace:        gw.Namespace,
-			DataplaneAddress: dataplaneAPIAddress,
-		})
+		agCfg := &meta.AgentConfig{
+			Name:              gw.Name,
+			Namespace:         gw.Namespace,
+			DataplaneAddress:  dataplaneAPIAddress,
+			TrafficResolution: r.cfg.TrafficResolution,
+		}
+		if r.cfg.DataplaneMetricsPort != 0 {
+			agCfg.DataplaneMetricsAddress = fmt.Sprintf("127.0.0.1:%d", r.cfg.DataplaneMetricsPort)
+		}
+
+		agCfgData, err := kyaml.Marshal(agCfg)
 		if err != nil {
 			return fmt.Errorf("marshalling agent config: %w", err)
 		}
@@ -434,7 +662,13 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 	}
 
 	{
-		ifaceFlags := lo.Flatten(lo.Map(lo.Keys(gw.Spec.Interfaces),
+		ifaceNames := lo.Keys(gw.Spec.Interfaces)
+		for _, ext := range externals {
+			ifaceNames = append(ifaceNames, ext.Interface)
+		}
+		slices.Sort(ifaceNames)
+
+		ifaceFlags := lo.Flatten(lo.Map(slices.Compact(ifaceNames),
 			func(ifaceName string, _ int) []string {
 				return []string{"--interface", ifaceName}
 			}))
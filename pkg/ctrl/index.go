@@ -0,0 +1,37 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetupIndexesWith registers the field indexes used by the controllers and webhooks, it should be called before
+// setting them up
+func SetupIndexesWith(ctx context.Context, mgr kctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &gwapi.Peering{}, gwapi.PeeringVPCPairField, peeringVPCPairIndex); err != nil {
+		return fmt.Errorf("indexing peerings by vpc pair: %w", err)
+	}
+
+	return nil
+}
+
+// peeringVPCPairIndex indexes the peerings by the VPC pair from their labels
+func peeringVPCPairIndex(obj kclient.Object) []string {
+	peering, ok := obj.(*gwapi.Peering)
+	if !ok {
+		return nil
+	}
+
+	if pair := peering.LabeledVPCPair(); pair != "" {
+		return []string{pair}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	internalIDKindVPC      = "vpc"
+	internalIDKindExternal = "external"
+)
+
+// internalIDHolder is a VPC or an external holding an internal ID, they share the same ID space
+type internalIDHolder struct {
+	kind    string
+	key     ktypes.NamespacedName
+	created kmetav1.Time
+	id      string
+}
+
+// keeps returns true if the holder keeps the ID it shares with the other one, the older one wins and the ties are
+// broken by the kind and the name so all controllers agree on which one has to release it
+func (h internalIDHolder) keeps(other internalIDHolder) bool {
+	if !h.created.Equal(&other.created) {
+		return h.created.Before(&other.created)
+	}
+
+	return cmp.Or(
+		cmp.Compare(h.kind, other.kind),
+		cmp.Compare(h.key.Namespace, other.key.Namespace),
+		cmp.Compare(h.key.Name, other.key.Name),
+	) < 0
+}
+
+// internalIDHolders returns all VPCs and externals with the internal IDs they hold (if any), it's expected to be
+// called with the API reader so the allocation doesn't depend on the cache being up to date
+func internalIDHolders(ctx context.Context, kube kclient.Reader) ([]internalIDHolder, error) {
+	vpcs := &gwapi.VPCInfoList{}
+	if err := kube.List(ctx, vpcs); err != nil {
+		return nil, fmt.Errorf("listing vpc info: %w", err)
+	}
+
+	exts := &gwapi.ExternalList{}
+	if err := kube.List(ctx, exts); err != nil {
+		return nil, fmt.Errorf("listing externals: %w", err)
+	}
+
+	res := make([]internalIDHolder, 0, len(vpcs.Items)+len(exts.Items))
+	for _, vpc := range vpcs.Items {
+		res = append(res, internalIDHolder{
+			kind:    internalIDKindVPC,
+			key:     kclient.ObjectKeyFromObject(&vpc),
+			created: vpc.CreationTimestamp,
+			id:      vpc.Status.InternalID,
+		})
+	}
+	for _, ext := range exts.Items {
+		res = append(res, internalIDHolder{
+			kind:    internalIDKindExternal,
+			key:     kclient.ObjectKeyFromObject(&ext),
+			created: ext.CreationTimestamp,
+			id:      ext.Status.InternalID,
+		})
+	}
+
+	return res, nil
+}
+
+// nextInternalID returns the first internal ID that isn't taken by any VPC or external
+func nextInternalID(holders []internalIDHolder) (string, error) {
+	taken := map[uint32]bool{}
+	for _, holder := range holders {
+		if holder.id == "" {
+			continue
+		}
+
+		id, err := VPCID.Decode(holder.id)
+		if err != nil {
+			return "", fmt.Errorf("decoding vpc id: %w", err)
+		}
+		taken[id] = true
+	}
+
+	for i := range VPCID.GetMaxValue() {
+		if !taken[i] {
+			return VPCID.Encode(i) //nolint:wrapcheck
+		}
+	}
+
+	return "", fmt.Errorf("no available vpc id") //nolint:err113
+}
+
+// internalIDOwner returns the holder that keeps the internal ID of the given one if it's allocated more than once
+func internalIDOwner(holders []internalIDHolder, self internalIDHolder) (internalIDHolder, bool) {
+	for _, holder := range holders {
+		if holder.id != self.id || holder.kind == self.kind && holder.key == self.key {
+			continue
+		}
+		if holder.keeps(self) {
+			return holder, true
+		}
+	}
+
+	return internalIDHolder{}, false
+}
+
+// ensureInternalID allocates the internal ID of the VPC or external if it doesn't have one yet and releases it if
+// it turns out to be allocated to another one as well. The IDs taken are read directly from the API server and the
+// status update fails on conflict if the object has changed since it was read. The allocation could still race with
+// another object's reconcile (incl. in another controller process), so it's requeued to check the ID is unique with
+// a fresh read once it's stored, and the one that doesn't keep the ID releases it and gets a new one.
+func ensureInternalID(ctx context.Context, kube kclient.Client, apiReader kclient.Reader, kind string, obj kclient.Object, id *string) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	holders, err := internalIDHolders(ctx, apiReader)
+	if err != nil {
+		return kctrl.Result{}, err
+	}
+
+	self := internalIDHolder{
+		kind:    kind,
+		key:     kclient.ObjectKeyFromObject(obj),
+		created: obj.GetCreationTimestamp(),
+		id:      *id,
+	}
+
+	if self.id != "" {
+		owner, duplicate := internalIDOwner(holders, self)
+		if !duplicate {
+			return kctrl.Result{}, nil
+		}
+
+		l.Info("Releasing duplicate internal ID", "id", self.id, "owner", owner.kind+" "+owner.key.String())
+		*id = ""
+	} else {
+		if *id, err = nextInternalID(holders); err != nil {
+			return kctrl.Result{}, err
+		}
+
+		l.Info("Allocating internal ID", "id", *id)
+	}
+
+	if err := kube.Status().Update(ctx, obj); err != nil {
+		if kapierrors.IsConflict(err) {
+			return kctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("updating %s status: %w", kind, err)
+	}
+
+	return kctrl.Result{RequeueAfter: 1 * time.Second}, nil
+}
+
+// enqueueInternalIDDuplicates returns a map func enqueueing the VPCs or externals (depending on the kind) holding the
+// same internal ID as the changed object, so the one that has to release the ID rechecks it even if it was stored first
+func enqueueInternalIDDuplicates(kube kclient.Reader, kind string) func(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	return func(ctx context.Context, obj kclient.Object) []reconcile.Request {
+		id := ""
+		switch obj := obj.(type) {
+		case *gwapi.VPCInfo:
+			id = obj.Status.InternalID
+		case *gwapi.External:
+			id = obj.Status.InternalID
+		}
+		if id == "" {
+			return nil
+		}
+
+		holders, err := internalIDHolders(ctx, kube)
+		if err != nil {
+			kctrllog.FromContext(ctx).Error(err, "error listing internal ID holders")
+
+			return nil
+		}
+
+		res := []reconcile.Request{}
+		for _, holder := range holders {
+			if holder.kind != kind || holder.id != id || holder.key == kclient.ObjectKeyFromObject(obj) {
+				continue
+			}
+
+			res = append(res, reconcile.Request{NamespacedName: holder.key})
+		}
+
+		return res
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=mirrorsessions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=mirrorsessions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
+
+type MirrorSessionReconciler struct {
+	kclient.Client
+}
+
+func SetupMirrorSessionReconcilerWith(mgr kctrl.Manager) error {
+	r := &MirrorSessionReconciler{
+		Client: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("MirrorSession").
+		For(&gwapi.MirrorSession{}).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueueSessionsInNamespace)).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueSessionsInNamespace)).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MirrorSessionReconciler) enqueueSessions(ctx context.Context, opts ...kclient.ListOption) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	sessions := &gwapi.MirrorSessionList{}
+	if err := r.List(ctx, sessions, opts...); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing mirror sessions to reconcile")
+
+		return nil
+	}
+
+	for _, ms := range sessions.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: ms.Namespace,
+			Name:      ms.Name,
+		}})
+	}
+
+	return res
+}
+
+func (r *MirrorSessionReconciler) enqueueSessionsInNamespace(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	return r.enqueueSessions(ctx, kclient.InNamespace(obj.GetNamespace()))
+}
+
+func (r *MirrorSessionReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	ms := &gwapi.MirrorSession{}
+	if err := r.Get(ctx, req.NamespacedName, ms); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("getting mirror session: %w", err)
+	}
+
+	if ms.DeletionTimestamp != nil {
+		return kctrl.Result{}, nil
+	}
+
+	status := gwapi.MirrorSessionStatus{}
+
+	// the session could become invalid if the peering or the monitoring VPC is changed or deleted afterwards, the
+	// valid ones are still reported as unsupported by the dataplane as the gateway controller doesn't pass them on
+	if err := ms.Validate(ctx, r); err != nil {
+		status.Error = err.Error()
+	}
+
+	if equality.Semantic.DeepEqual(ms.Status, status) {
+		return kctrl.Result{}, nil
+	}
+
+	l.Info("Updating MirrorSession status", "name", req.Name, "namespace", req.Namespace, "error", status.Error)
+
+	ms.Status = status
+	if err := r.Status().Update(ctx, ms); err != nil {
+		return kctrl.Result{}, fmt.Errorf("updating mirror session status: %w", err)
+	}
+
+	return kctrl.Result{}, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-mirrorsession,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=mirrorsessions,verbs=create;update;delete,versions=v1alpha1,name=mmirrorsession.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gateway-githedgehog-com-v1alpha1-mirrorsession,mutating=false,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=mirrorsessions,verbs=create;update;delete,versions=v1alpha1,name=vmirrorsession.kb.io,admissionReviewVersions=v1
+
+type MirrorSessionWebhook struct {
+	kclient.Reader
+}
+
+func SetupMirrorSessionWebhookWith(mgr kctrl.Manager) error {
+	w := &MirrorSessionWebhook{
+		Reader: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewWebhookManagedBy(mgr).
+		For(&gwapi.MirrorSession{}).
+		WithDefaulter(FromTypedDefaulter(w)).
+		WithValidator(FromTypedValidator(w)).
+		Complete(); err != nil {
+		return fmt.Errorf("creating webhook: %w", err) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (w *MirrorSessionWebhook) Default(_ context.Context, obj *gwapi.MirrorSession) error {
+	obj.Default()
+
+	return nil
+}
+
+func (w *MirrorSessionWebhook) ValidateCreate(ctx context.Context, obj *gwapi.MirrorSession) (admission.Warnings, error) {
+	return nil, obj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *MirrorSessionWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.MirrorSession, newObj *gwapi.MirrorSession) (admission.Warnings, error) {
+	// TODO validate diff between oldObj and newObj if needed
+	_ = oldObj
+
+	return nil, newObj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *MirrorSessionWebhook) ValidateDelete(_ context.Context, _ *gwapi.MirrorSession) (admission.Warnings, error) {
+	return nil, nil
+}
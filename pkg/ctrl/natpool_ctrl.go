@@ -0,0 +1,366 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=natpools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=natpools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+
+type NATPoolReconciler struct {
+	kclient.Client
+}
+
+func SetupNATPoolReconcilerWith(mgr kctrl.Manager) error {
+	r := &NATPoolReconciler{
+		Client: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("NATPool").
+		For(&gwapi.NATPool{}).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePoolsForPeering)).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+// enqueuePoolsForPeering enqueues the pools the peering is labeled with, the labels are still there for the deleted
+// peerings so their allocations are released
+func (r *NATPoolReconciler) enqueuePoolsForPeering(_ context.Context, obj kclient.Object) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	prefix := gwapi.ListLabelPrefix("natpool")
+	for label := range obj.GetLabels() {
+		if poolName, ok := strings.CutPrefix(label, prefix); ok {
+			res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      poolName,
+			}})
+		}
+	}
+
+	return res
+}
+
+func (r *NATPoolReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	pool := &gwapi.NATPool{}
+	if err := r.Get(ctx, req.NamespacedName, pool); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("getting nat pool: %w", err)
+	}
+
+	if pool.DeletionTimestamp != nil {
+		return kctrl.Result{}, nil
+	}
+
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings, kclient.InNamespace(pool.Namespace), kclient.MatchingLabels{
+		gwapi.ListLabelNATPool(pool.Name): gwapi.ListLabelValue,
+	}); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peerings for nat pool: %w", err)
+	}
+
+	reserved, err := natPoolReserved(ctx, r, pool.Namespace)
+	if err != nil {
+		return kctrl.Result{}, err
+	}
+
+	status, next := allocateNATPool(pool, natPoolRequests(pool.Name, peerings.Items), reserved, time.Now())
+
+	res := kctrl.Result{}
+	if next > 0 {
+		res.RequeueAfter = next
+	}
+
+	if equality.Semantic.DeepEqual(pool.Status, status) {
+		return res, nil
+	}
+
+	l.Info("Updating NATPool status", "name", req.Name, "namespace", req.Namespace, "allocated", status.Allocated, "free", status.Free, "error", status.Error)
+
+	pool.Status = status
+	if err := r.Status().Update(ctx, pool); err != nil {
+		return kctrl.Result{}, fmt.Errorf("updating nat pool status: %w", err)
+	}
+
+	return res, nil
+}
+
+// natPoolRequest is a prefix of the specific length requested from a pool by an expose
+type natPoolRequest struct {
+	peering   string
+	vpc       string
+	ips       string
+	prefixLen int
+}
+
+// natPoolRequests returns the prefixes requested from the pool by the exposes of the peerings keyed by
+// gwapi.NATPoolAllocationKey, the peerings being deleted are skipped so their allocations are released
+func natPoolRequests(poolName string, peerings []gwapi.Peering) map[string]natPoolRequest {
+	res := map[string]natPoolRequest{}
+
+	for _, peering := range peerings {
+		if peering.DeletionTimestamp != nil {
+			continue
+		}
+
+		for vpcName, entry := range peering.Spec.Peering {
+			if entry == nil {
+				continue
+			}
+
+			for _, expose := range entry.Expose {
+				if expose.ASPool == nil || expose.ASPool.Pool != poolName {
+					continue
+				}
+
+				ips := expose.IPsKey()
+				res[gwapi.NATPoolAllocationKey(peering.Name, vpcName, ips)] = natPoolRequest{
+					peering:   peering.Name,
+					vpc:       vpcName,
+					ips:       ips,
+					prefixLen: int(expose.ASPool.PrefixLen),
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// natPoolReserved returns the addresses in the namespace the prefixes can't be allocated from: the subnets of the
+// VPCs and the "as" pools listed in the peerings
+func natPoolReserved(ctx context.Context, kube kclient.Reader, namespace string) (*prefixset.Set, error) {
+	res := &prefixset.Set{}
+
+	vpcs := &gwapi.VPCInfoList{}
+	if err := kube.List(ctx, vpcs, kclient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("listing vpcs: %w", err)
+	}
+	for _, vpc := range vpcs.Items {
+		for _, subnet := range vpc.Spec.Subnets {
+			if subnet == nil {
+				continue
+			}
+			if set, err := prefixset.Parse(subnet.CIDR); err == nil {
+				res = res.Union(set)
+			}
+		}
+	}
+
+	peerings := &gwapi.PeeringList{}
+	if err := kube.List(ctx, peerings, kclient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("listing peerings: %w", err)
+	}
+	for _, peering := range peerings.Items {
+		for _, entry := range peering.Spec.Peering {
+			if entry == nil {
+				continue
+			}
+			for _, expose := range entry.Expose {
+				for _, as := range expose.As {
+					if as.CIDR == "" {
+						continue
+					}
+					if set, err := prefixset.Parse(as.CIDR); err == nil {
+						res = res.Union(set)
+					}
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// allocateNATPool returns the pool status with the allocations for the requests. The existing allocations are kept as
+// long as they're requested with the same prefix length, the others are released and their addresses aren't reused
+// until the cooldown is over. The new prefixes are allocated from the smallest free block they fit into, excluding
+// the reserved addresses, to limit the fragmentation. It also returns the time until the next released allocation is
+// over the cooldown, if any.
+func allocateNATPool(pool *gwapi.NATPool, requests map[string]natPoolRequest, reserved *prefixset.Set, now time.Time) (gwapi.NATPoolStatus, time.Duration) {
+	status := gwapi.NATPoolStatus{
+		Allocations: map[string]gwapi.NATPoolAllocation{},
+		Released:    []gwapi.NATPoolAllocation{},
+	}
+	set := pool.Set()
+	used := set.Intersect(reserved)
+	cooldown := pool.ReleaseCooldownDuration()
+	released := slices.Clone(pool.Status.Released)
+	next := time.Duration(0)
+
+	for _, key := range slices.Sorted(maps.Keys(pool.Status.Allocations)) {
+		alloc := pool.Status.Allocations[key]
+		prefix, err := netip.ParsePrefix(alloc.CIDR)
+		if err != nil || !set.ContainsPrefix(prefix) {
+			continue
+		}
+
+		if req, ok := requests[key]; ok && req.prefixLen == prefix.Bits() {
+			status.Allocations[key] = alloc
+			used = used.Union(prefixset.New(prefix))
+
+			continue
+		}
+
+		alloc.ReleasedAt = &kmetav1.Time{Time: now}
+		released = append(released, alloc)
+	}
+
+	for _, alloc := range released {
+		prefix, err := netip.ParsePrefix(alloc.CIDR)
+		if err != nil || !set.ContainsPrefix(prefix) || alloc.ReleasedAt == nil {
+			continue
+		}
+
+		// the same expose asking again during the cooldown gets the same prefix back
+		key := gwapi.NATPoolAllocationKey(alloc.Peering, alloc.VPC, alloc.IPs)
+		if _, exists := status.Allocations[key]; !exists {
+			if req, ok := requests[key]; ok && req.prefixLen == prefix.Bits() && !used.Overlaps(prefixset.New(prefix)) {
+				alloc.ReleasedAt = nil
+				status.Allocations[key] = alloc
+				used = used.Union(prefixset.New(prefix))
+
+				continue
+			}
+		}
+
+		left := alloc.ReleasedAt.Add(cooldown).Sub(now)
+		if left <= 0 {
+			continue
+		}
+		if next == 0 || left < next {
+			next = left
+		}
+
+		status.Released = append(status.Released, alloc)
+		used = used.Union(prefixset.New(prefix))
+	}
+
+	errs := []string{}
+	for _, key := range slices.Sorted(maps.Keys(requests)) {
+		if _, exists := status.Allocations[key]; exists {
+			continue
+		}
+		req := requests[key]
+
+		var block netip.Prefix
+		for _, free := range set.Subtract(used).Prefixes() {
+			if free.Bits() <= req.prefixLen && (!block.IsValid() || free.Bits() > block.Bits()) {
+				block = free
+			}
+		}
+		if !block.IsValid() {
+			errs = append(errs, fmt.Sprintf("no free /%d for %s", req.prefixLen, key))
+
+			continue
+		}
+
+		prefix := netip.PrefixFrom(block.Addr(), req.prefixLen)
+		status.Allocations[key] = gwapi.NATPoolAllocation{
+			CIDR:    prefix.String(),
+			Peering: req.peering,
+			VPC:     req.vpc,
+			IPs:     req.ips,
+		}
+		used = used.Union(prefixset.New(prefix))
+	}
+
+	if len(status.Allocations) == 0 {
+		status.Allocations = nil
+	}
+	if len(status.Released) == 0 {
+		status.Released = nil
+	}
+	status.Allocated = len(status.Allocations)
+	status.Free = set.Subtract(used).Size().String()
+	status.Error = strings.Join(errs, ", ")
+
+	return status, next
+}
+
+// resolveASPools returns a copy of the peering with the "as" pools of the exposes allocated from the NAT pools set to
+// the allocated prefixes and the allocations of each VPC. If any of the prefixes isn't allocated (yet) it returns the
+// reason instead.
+func resolveASPools(ctx context.Context, kube kclient.Reader, peering *gwapi.Peering) (*gwapi.Peering, map[string][]gwapi.PeeringASAllocation, string, error) {
+	res := peering.DeepCopy()
+	allocs := map[string][]gwapi.PeeringASAllocation{}
+	pools := map[string]*gwapi.NATPool{}
+
+	for _, vpcName := range slices.Sorted(maps.Keys(res.Spec.Peering)) {
+		entry := res.Spec.Peering[vpcName]
+		if entry == nil {
+			continue
+		}
+
+		for idx, expose := range entry.Expose {
+			if expose.ASPool == nil {
+				continue
+			}
+
+			pool, ok := pools[expose.ASPool.Pool]
+			if !ok {
+				pool = &gwapi.NATPool{}
+				if err := kube.Get(ctx, kclient.ObjectKey{Namespace: peering.Namespace, Name: expose.ASPool.Pool}, pool); err != nil {
+					if kapierrors.IsNotFound(err) {
+						return nil, nil, fmt.Sprintf("nat pool %s not found", expose.ASPool.Pool), nil
+					}
+
+					return nil, nil, "", fmt.Errorf("getting nat pool %s: %w", expose.ASPool.Pool, err)
+				}
+				pools[expose.ASPool.Pool] = pool
+			}
+
+			cidr, ok := pool.Allocation(gwapi.NATPoolAllocationKey(peering.Name, vpcName, expose.IPsKey()))
+			if !ok {
+				return nil, nil, fmt.Sprintf("waiting for /%d to be allocated from nat pool %s for vpc %s expose %d",
+					expose.ASPool.PrefixLen, pool.Name, vpcName, idx), nil
+			}
+			// the request could have changed since the allocation
+			if prefix, err := netip.ParsePrefix(cidr); err != nil || prefix.Bits() != int(expose.ASPool.PrefixLen) {
+				return nil, nil, fmt.Sprintf("waiting for /%d to be allocated from nat pool %s for vpc %s expose %d",
+					expose.ASPool.PrefixLen, pool.Name, vpcName, idx), nil
+			}
+
+			entry.Expose[idx] = expose.WithAllocatedAs(cidr)
+			allocs[vpcName] = append(allocs[vpcName], gwapi.PeeringASAllocation{
+				Expose: idx,
+				Pool:   pool.Name,
+				CIDR:   cidr,
+			})
+		}
+	}
+
+	return res, allocs, "", nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAllocateNATPool(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	pool := &gwapi.NATPool{Spec: gwapi.NATPoolSpec{CIDRs: []string{"192.168.0.0/24", "192.168.1.0/28"}}}
+	pool.Default()
+
+	req := func(peering string, prefixLen int) natPoolRequest {
+		return natPoolRequest{peering: peering, vpc: "vpc-1", ips: "subnet-1", prefixLen: prefixLen}
+	}
+	key := func(peering string) string {
+		return gwapi.NATPoolAllocationKey(peering, "vpc-1", "subnet-1")
+	}
+
+	// the /28 goes into the smallest block it fits into
+	status, next := allocateNATPool(pool, map[string]natPoolRequest{
+		key("p-1"): req("p-1", 28),
+		key("p-2"): req("p-2", 25),
+	}, nil, now)
+	require.Empty(t, status.Error)
+	require.Zero(t, next)
+	require.Equal(t, 2, status.Allocated)
+	require.Equal(t, "192.168.1.0/28", status.Allocations[key("p-1")].CIDR)
+	require.Equal(t, "192.168.0.0/25", status.Allocations[key("p-2")].CIDR)
+	require.Equal(t, "128", status.Free)
+
+	// allocations are stable
+	pool.Status = status
+	status, _ = allocateNATPool(pool, map[string]natPoolRequest{
+		key("p-0"): req("p-0", 26),
+		key("p-1"): req("p-1", 28),
+		key("p-2"): req("p-2", 25),
+	}, nil, now)
+	require.Empty(t, status.Error)
+	require.Equal(t, "192.168.1.0/28", status.Allocations[key("p-1")].CIDR)
+	require.Equal(t, "192.168.0.0/25", status.Allocations[key("p-2")].CIDR)
+	require.Equal(t, "192.168.0.128/26", status.Allocations[key("p-0")].CIDR)
+
+	// released prefixes aren't reused during the cooldown
+	pool.Status = status
+	status, next = allocateNATPool(pool, map[string]natPoolRequest{
+		key("p-0"): req("p-0", 26),
+		key("p-1"): req("p-1", 28),
+		key("p-3"): req("p-3", 25),
+	}, nil, now)
+	require.Equal(t, "no free /25 for "+key("p-3"), status.Error)
+	require.Equal(t, time.Hour, next)
+	require.Len(t, status.Released, 1)
+	require.Equal(t, "192.168.0.0/25", status.Released[0].CIDR)
+
+	// the released prefix is given back to the same expose during the cooldown
+	pool.Status = status
+	status, _ = allocateNATPool(pool, map[string]natPoolRequest{
+		key("p-0"): req("p-0", 26),
+		key("p-1"): req("p-1", 28),
+		key("p-2"): req("p-2", 25),
+	}, nil, now.Add(time.Minute))
+	require.Empty(t, status.Error)
+	require.Empty(t, status.Released)
+	require.Equal(t, "192.168.0.0/25", status.Allocations[key("p-2")].CIDR)
+	require.Nil(t, status.Allocations[key("p-2")].ReleasedAt)
+
+	// and reused by the others after the cooldown
+	pool.Status = status
+	status, _ = allocateNATPool(pool, map[string]natPoolRequest{
+		key("p-0"): req("p-0", 26),
+		key("p-1"): req("p-1", 28),
+	}, nil, now)
+	pool.Status = status
+	status, next = allocateNATPool(pool, map[string]natPoolRequest{
+		key("p-0"): req("p-0", 26),
+		key("p-1"): req("p-1", 28),
+		key("p-3"): req("p-3", 25),
+	}, nil, now.Add(2*time.Hour))
+	require.Empty(t, status.Error)
+	require.Zero(t, next)
+	require.Empty(t, status.Released)
+	require.Equal(t, "192.168.0.0/25", status.Allocations[key("p-3")].CIDR)
+}
+
+func TestAllocateNATPoolReserved(t *testing.T) {
+	pool := &gwapi.NATPool{Spec: gwapi.NATPoolSpec{CIDRs: []string{"192.168.0.0/24"}}}
+	pool.Default()
+
+	kube := kubetest.NewReader(
+		testVPC("vpc-1", "10.1.0.0/24"),
+		testPeering("vpc-1--vpc-2", map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+				IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}},
+				As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.0.0/25"}},
+			}}},
+		}),
+	)
+	reserved, err := natPoolReserved(t.Context(), kube, "default")
+	require.NoError(t, err)
+	require.True(t, reserved.Equal(prefixset.MustParse("10.1.0.0/24", "192.168.0.0/25")))
+
+	key := gwapi.NATPoolAllocationKey("vpc-1--vpc-3", "vpc-1", "subnet-1")
+	status, _ := allocateNATPool(pool, map[string]natPoolRequest{
+		key: {peering: "vpc-1--vpc-3", vpc: "vpc-1", ips: "subnet-1", prefixLen: 25},
+	}, reserved, time.Now())
+	require.Empty(t, status.Error)
+	require.Equal(t, "192.168.0.128/25", status.Allocations[key].CIDR)
+	require.Equal(t, "0", status.Free)
+
+	status, _ = allocateNATPool(pool, map[string]natPoolRequest{
+		key: {peering: "vpc-1--vpc-3", vpc: "vpc-1", ips: "subnet-1", prefixLen: 24},
+	}, reserved, time.Now())
+	require.Equal(t, "no free /24 for "+key, status.Error)
+}
+
+func TestNATPoolRequestsReordered(t *testing.T) {
+	expose := func(subnet string, prefixLen uint8) gwapi.PeeringEntryExpose {
+		return gwapi.PeeringEntryExpose{
+			IPs:    []gwapi.PeeringEntryIP{{VPCSubnet: subnet}},
+			ASPool: &gwapi.PeeringEntryASPool{Pool: "pool-1", PrefixLen: prefixLen},
+		}
+	}
+	peering := func(exposes ...gwapi.PeeringEntryExpose) gwapi.Peering {
+		return gwapi.Peering{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "vpc-1--vpc-2"},
+			Spec: gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{
+				"vpc-1": {Expose: exposes},
+			}},
+		}
+	}
+
+	pool := &gwapi.NATPool{Spec: gwapi.NATPoolSpec{CIDRs: []string{"192.168.0.0/24"}}}
+	pool.Default()
+
+	status, _ := allocateNATPool(pool, natPoolRequests("pool-1", []gwapi.Peering{
+		peering(expose("subnet-1", 25), expose("subnet-2", 26)),
+	}), nil, time.Now())
+	require.Empty(t, status.Error)
+
+	// the exposes keep their prefixes when they're reordered
+	pool.Status = status
+	reordered, _ := allocateNATPool(pool, natPoolRequests("pool-1", []gwapi.Peering{
+		peering(expose("subnet-2", 26), expose("subnet-1", 25)),
+	}), nil, time.Now())
+	require.Equal(t, status, reordered)
+	require.Equal(t, "192.168.0.0/25", reordered.Allocations[gwapi.NATPoolAllocationKey("vpc-1--vpc-2", "vpc-1", "subnet-1")].CIDR)
+	require.Equal(t, "192.168.0.128/26", reordered.Allocations[gwapi.NATPoolAllocationKey("vpc-1--vpc-2", "vpc-1", "subnet-2")].CIDR)
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-natpool,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=natpools,verbs=create;update;delete,versions=v1alpha1,name=mnatpool.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gateway-githedgehog-com-v1alpha1-natpool,mutating=false,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=natpools,verbs=create;update;delete,versions=v1alpha1,name=vnatpool.kb.io,admissionReviewVersions=v1
+
+type NATPoolWebhook struct {
+	kclient.Reader
+}
+
+func SetupNATPoolWebhookWith(mgr kctrl.Manager) error {
+	w := &NATPoolWebhook{
+		Reader: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewWebhookManagedBy(mgr).
+		For(&gwapi.NATPool{}).
+		WithDefaulter(FromTypedDefaulter(w)).
+		WithValidator(FromTypedValidator(w)).
+		Complete(); err != nil {
+		return fmt.Errorf("creating webhook: %w", err) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (w *NATPoolWebhook) Default(_ context.Context, obj *gwapi.NATPool) error {
+	obj.Default()
+
+	return nil
+}
+
+func (w *NATPoolWebhook) ValidateCreate(ctx context.Context, obj *gwapi.NATPool) (admission.Warnings, error) {
+	return nil, obj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *NATPoolWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.NATPool, newObj *gwapi.NATPool) (admission.Warnings, error) {
+	if err := newObj.Validate(ctx, w.Reader); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	// the pool can't shrink below the prefixes that are in use or cooling down
+	return nil, newObj.CheckAllocations(oldObj.Status) //nolint:wrapcheck
+}
+
+func (w *NATPoolWebhook) ValidateDelete(_ context.Context, obj *gwapi.NATPool) (admission.Warnings, error) {
+	if obj.Status.Allocated > 0 {
+		return nil, fmt.Errorf("nat pool has %d allocations, delete the peerings using it first", obj.Status.Allocated) //nolint:goerr113
+	}
+
+	return nil, nil
+}
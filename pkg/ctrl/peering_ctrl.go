@@ -0,0 +1,647 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	"go.githedgehog.com/gateway/api/meta"
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PeeringStatusMaxRoutes is the max number of routes enumerated in the peering status for a single VPC, only the
+// aggregates are listed if there are more of them
+const PeeringStatusMaxRoutes = 64
+
+var defaultRoute = netip.MustParsePrefix("0.0.0.0/0")
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=natpools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringmeshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringrequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringacceptances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gwint.githedgehog.com,resources=gatewayagents,verbs=get;list;watch
+
+type PeeringReconciler struct {
+	kclient.Client
+	trafficResolution time.Duration
+}
+
+func SetupPeeringReconcilerWith(mgr kctrl.Manager, cfg *meta.GatewayCtrlConfig) error {
+	r := &PeeringReconciler{
+		Client:            mgr.GetClient(),
+		trafficResolution: cfg.TrafficResolution.Duration,
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("Peering").
+		For(&gwapi.Peering{}).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePeeringsFor)).
+		Watches(&gwapi.External{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePeeringsFor)).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueTransitPeerings)).
+		Watches(&gwapi.PeeringMesh{}, handler.EnqueueRequestsFromMapFunc(r.enqueueTransitPeerings)).
+		Watches(&gwapi.PeeringRequest{}, handler.EnqueueRequestsFromMapFunc(r.enqueueTransitPeerings)).
+		Watches(&gwapi.PeeringAcceptance{}, handler.EnqueueRequestsFromMapFunc(r.enqueueTransitPeerings)).
+		Watches(&gwintapi.GatewayAgent{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAgentPeerings)).
+		Watches(&gwapi.NATPool{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePoolPeerings)).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+// enqueuePeeringsFor enqueues all peerings of the VPC or external, the ones inspected by it and the transit ones
+func (r *PeeringReconciler) enqueuePeeringsFor(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings, kclient.InNamespace(obj.GetNamespace()), kclient.MatchingLabels{
+		gwapi.ListLabelVPC(obj.GetName()): gwapi.ListLabelValue,
+	}); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing peerings to reconcile", "vpc", obj.GetName())
+
+		return nil
+	}
+
+	chained := &gwapi.PeeringList{}
+	if err := r.List(ctx, chained, kclient.InNamespace(obj.GetNamespace()), kclient.MatchingLabels{
+		gwapi.LabelInspectionVPC: obj.GetName(),
+	}); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing chained peerings to reconcile", "vpc", obj.GetName())
+
+		return nil
+	}
+
+	for _, peering := range append(peerings.Items, chained.Items...) {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: peering.Namespace,
+			Name:      peering.Name,
+		}})
+	}
+
+	return append(res, r.enqueueTransitPeerings(ctx, obj)...)
+}
+
+// enqueueTransitPeerings enqueues all peerings with a transit VPC in the namespace (and in the other namespace of the
+// cross-namespace peerings) as the routes they re-expose could come from any other peering, mesh or request
+func (r *PeeringReconciler) enqueueTransitPeerings(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	namespaces := []string{obj.GetNamespace()}
+	switch obj := obj.(type) {
+	case *gwapi.PeeringRequest:
+		namespaces = append(namespaces, obj.Spec.RemoteNamespace)
+	case *gwapi.PeeringAcceptance:
+		namespaces = append(namespaces, obj.Spec.RequestNamespace)
+	}
+
+	peerings := &gwapi.PeeringList{}
+	for _, namespace := range slices.Compact(namespaces) {
+		nsPeerings := &gwapi.PeeringList{}
+		if err := r.List(ctx, nsPeerings, kclient.InNamespace(namespace)); err != nil {
+			kctrllog.FromContext(ctx).Error(err, "error listing transit peerings to reconcile", "namespace", namespace)
+
+			return nil
+		}
+		peerings.Items = append(peerings.Items, nsPeerings.Items...)
+	}
+
+	for _, peering := range peerings.Items {
+		for _, entry := range peering.Spec.Peering {
+			if entry != nil && entry.Transit {
+				res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+					Namespace: peering.Namespace,
+					Name:      peering.Name,
+				}})
+
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// enqueuePoolPeerings enqueues the peerings with the exposes allocated from the NAT pool
+func (r *PeeringReconciler) enqueuePoolPeerings(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings, kclient.InNamespace(obj.GetNamespace()), kclient.MatchingLabels{
+		gwapi.ListLabelNATPool(obj.GetName()): gwapi.ListLabelValue,
+	}); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing peerings to reconcile", "natpool", obj.GetName())
+
+		return nil
+	}
+
+	for _, peering := range peerings.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: peering.Namespace,
+			Name:      peering.Name,
+		}})
+	}
+
+	return res
+}
+
+// enqueueAgentPeerings enqueues the peerings configured on the gateway or reported in its status, the keys of the
+// meshes and the accepted requests don't reference peering objects so they're skipped
+func (r *PeeringReconciler) enqueueAgentPeerings(_ context.Context, obj kclient.Object) []reconcile.Request {
+	ag, ok := obj.(*gwintapi.GatewayAgent)
+	if !ok {
+		return nil
+	}
+
+	keys := map[string]bool{}
+	for key := range ag.Spec.Peerings {
+		keys[key] = true
+	}
+	for key := range ag.Status.Peerings {
+		keys[key] = true
+	}
+
+	res := []reconcile.Request{}
+	for key := range keys {
+		parts := strings.Split(key, agentNameSep)
+		switch len(parts) {
+		case 1:
+			res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{Namespace: ag.Namespace, Name: key}})
+		case 2:
+			res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{Namespace: parts[0], Name: parts[1]}})
+		}
+	}
+
+	return res
+}
+
+func (r *PeeringReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	peering := &gwapi.Peering{}
+	if err := r.Get(ctx, req.NamespacedName, peering); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("getting peering: %w", err)
+	}
+
+	if peering.DeletionTimestamp != nil {
+		return kctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	state, next := peering.Spec.WindowState(now)
+	if state == gwapi.PeeringWindowStateExpired && peering.Spec.DeleteAfterExpiry != nil {
+		deleteAt := peering.Spec.ExpiresAt.Add(peering.Spec.DeleteAfterExpiry.Duration)
+		if !now.Before(deleteAt) {
+			l.Info("Deleting expired Peering", "name", req.Name, "namespace", req.Namespace, "expiresAt", peering.Spec.ExpiresAt)
+
+			if err := r.Delete(ctx, peering); err != nil && !kapierrors.IsNotFound(err) {
+				return kctrl.Result{}, fmt.Errorf("deleting expired peering: %w", err)
+			}
+
+			return kctrl.Result{}, nil
+		}
+		next = deleteAt
+	}
+
+	// reconcile again at the next window boundary to update the state
+	res := kctrl.Result{}
+	if !next.IsZero() {
+		res.RequeueAfter = next.Sub(now)
+	}
+
+	status, err := peeringStatus(ctx, r, peering)
+	if err != nil {
+		return kctrl.Result{}, err
+	}
+	status.ObservedGeneration = peering.Generation
+	status.State = state
+
+	// the traffic counters change all the time, so they're only refreshed once per the resolution
+	status.Traffic = peering.Status.Traffic
+	if status.Traffic == nil || now.Sub(status.Traffic.UpdatedAt.Time) >= r.trafficResolution {
+		agents := &gwintapi.GatewayAgentList{}
+		if err := r.List(ctx, agents); err != nil {
+			return kctrl.Result{}, fmt.Errorf("listing gateway agents: %w", err)
+		}
+		status.Traffic = peeringTraffic(peering, agents.Items, now)
+	}
+	if status.Traffic != nil {
+		refresh := status.Traffic.UpdatedAt.Add(r.trafficResolution).Sub(now)
+		if refresh <= 0 {
+			refresh = r.trafficResolution
+		}
+		if res.RequeueAfter == 0 || refresh < res.RequeueAfter {
+			res.RequeueAfter = refresh
+		}
+	}
+
+	if equality.Semantic.DeepEqual(peering.Status, status) {
+		return res, nil
+	}
+
+	l.Info("Updating Peering status", "name", req.Name, "namespace", req.Namespace, "state", status.State, "error", status.Error)
+
+	peering.Status = status
+	if err := r.Status().Update(ctx, peering); err != nil {
+		return kctrl.Result{}, fmt.Errorf("updating peering status: %w", err)
+	}
+
+	return res, nil
+}
+
+// peeringStatus computes the routes advertised into and the NAT mappings applied in the VRF of each side of the
+// peering, the errors caused by the peering itself or missing VPCs are reported in the status
+func peeringStatus(ctx context.Context, kube kclient.Reader, peering *gwapi.Peering) (gwapi.PeeringStatus, error) {
+	status := gwapi.PeeringStatus{}
+
+	subnets := map[string]map[string][]string{}
+	externals := map[string]bool{}
+	for _, name := range slices.Sorted(maps.Keys(peering.Spec.Peering)) {
+		vpcSubnets, isExternal, err := gwapi.GetPeeredSubnets(ctx, kube, peering.Namespace, name)
+		if err != nil {
+			if kapierrors.IsNotFound(err) {
+				status.Error = fmt.Sprintf("vpc or external %s not found", name)
+
+				return status, nil
+			}
+
+			return status, fmt.Errorf("getting peered %s: %w", name, err)
+		}
+
+		subnets[name] = vpcSubnets
+		externals[name] = isExternal
+	}
+
+	if chain := peering.Spec.ServiceChain; chain != nil {
+		if _, _, err := gwapi.GetPeeredSubnets(ctx, kube, peering.Namespace, chain.VPC); err != nil {
+			if kapierrors.IsNotFound(err) {
+				status.Error = fmt.Sprintf("inspection vpc %s not found", chain.VPC)
+
+				return status, nil
+			}
+
+			return status, fmt.Errorf("getting inspection vpc %s: %w", chain.VPC, err)
+		}
+	}
+
+	// the gateways skip the peering, so there is no effective result to report
+	if err := peering.Spec.DataplaneUnsupported(func(name string) bool { return externals[name] }); err != nil {
+		status.Error = "not configured on the gateways: " + err.Error()
+
+		return status, nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(externals)) {
+		if !externals[name] {
+			continue
+		}
+
+		ext := &gwapi.External{}
+		if err := kube.Get(ctx, kclient.ObjectKey{Namespace: peering.Namespace, Name: name}, ext); err != nil {
+			return status, fmt.Errorf("getting external %s: %w", name, err)
+		}
+		if err := ext.Spec.DataplaneUnsupported(); err != nil {
+			status.Error = fmt.Sprintf("not configured on the gateways: external %s: %s", name, err)
+
+			return status, nil
+		}
+	}
+
+	// the routes and the NAT mappings are computed with the "as" pools allocated from the NAT pools
+	peering, allocated, pending, err := resolveASPools(ctx, kube, peering)
+	if err != nil {
+		return status, err
+	}
+	if pending != "" {
+		status.Error = pending
+
+		return status, nil
+	}
+
+	status.VPCs = map[string]gwapi.PeeringVPCStatus{}
+	for vpcName := range peering.Spec.Peering {
+		vpcStatus := gwapi.PeeringVPCStatus{
+			AllocatedAs: allocated[vpcName],
+		}
+		routes, aggregates := &prefixset.Set{}, &prefixset.Set{}
+		unbounded := false
+
+		for peerName, peer := range peering.Spec.Peering {
+			if peer == nil {
+				continue
+			}
+
+			for idx := range peer.Expose {
+				expose := &peer.Expose[idx]
+
+				if peerName == vpcName {
+					if !externals[vpcName] && (len(expose.As) > 0 || expose.Translation != nil) {
+						nat, err := exposeNAT(expose, subnets[vpcName])
+						if err != nil {
+							status.Error = fmt.Sprintf("expose of %s: %s", vpcName, err)
+
+							return status, nil
+						}
+						vpcStatus.SourceNAT = append(vpcStatus.SourceNAT, nat)
+					}
+
+					continue
+				}
+
+				if externals[peerName] {
+					exposeRoutes, err := expose.ExternalRoutes()
+					if err != nil {
+						status.Error = fmt.Sprintf("expose of %s: %s", peerName, err)
+
+						return status, nil
+					}
+					// the BGP sessions with the externals only carry IPv4 unicast, so no IPv6 routes are learned
+					exposeRoutes = gwapi.SplitFamilies(exposeRoutes)[0]
+					unbounded = unbounded || exposeRoutes.ContainsPrefix(defaultRoute)
+					exposeAggregates := gwapi.SplitFamilies(entriesSet(expose.IPs, nil, defaultRoute.String()))[0]
+
+					// the VPC reaches the IPv4 external through the addresses they're embedded into
+					if expose.Translation != nil {
+						translated, err := expose.Translation.Translate(exposeRoutes)
+						if err != nil {
+							status.Error = fmt.Sprintf("expose of %s: %s", peerName, err)
+
+							return status, nil
+						}
+						vpcStatus.DestinationNAT = append(vpcStatus.DestinationNAT, gwapi.PeeringNATStatus{
+							From: translated.Strings(),
+							To:   exposeRoutes.Strings(),
+						})
+
+						exposeRoutes = translated
+						if exposeAggregates, err = expose.Translation.Translate(exposeAggregates); err != nil {
+							status.Error = fmt.Sprintf("expose of %s: %s", peerName, err)
+
+							return status, nil
+						}
+					}
+
+					routes = routes.Union(exposeRoutes)
+					aggregates = aggregates.Union(exposeAggregates)
+
+					continue
+				}
+
+				ips, as, err := expose.Sets(subnets[peerName])
+				if err != nil {
+					status.Error = fmt.Sprintf("expose of %s: %s", peerName, err)
+
+					return status, nil
+				}
+
+				if as.IsEmpty() {
+					routes = routes.Union(ips)
+					aggregates = aggregates.Union(entriesSet(expose.IPs, subnets[peerName], ""))
+				} else {
+					routes = routes.Union(as)
+					if expose.Translation != nil {
+						aggregates = aggregates.Union(as)
+					} else {
+						aggregates = aggregates.Union(entriesSet(asEntries(expose.As), nil, ""))
+					}
+
+					nat, err := exposeNAT(expose, subnets[peerName])
+					if err != nil {
+						status.Error = fmt.Sprintf("expose of %s: %s", peerName, err)
+
+						return status, nil
+					}
+					vpcStatus.DestinationNAT = append(vpcStatus.DestinationNAT, gwapi.PeeringNATStatus{
+						From:         nat.To,
+						To:           nat.From,
+						ExcludedFrom: nat.ExcludedTo,
+						ExcludedTo:   nat.ExcludedFrom,
+					})
+				}
+			}
+		}
+
+		// the dataplane always enumerates the routes, so the advertisement mode is only reported as the intended one
+		// for the VPCs, the routes advertised to the externals are always enumerated anyway
+		if !externals[vpcName] {
+			vpcStatus.Advertisement = peering.Spec.EffectiveAdvertisementMode(len(routes.Prefixes()), unbounded)
+		}
+
+		vpcStatus.Routes = routes.Strings()
+		vpcStatus.RouteCount = len(vpcStatus.Routes)
+		if vpcStatus.RouteCount > PeeringStatusMaxRoutes {
+			vpcStatus.Routes = aggregates.Strings()
+			vpcStatus.Summarized = true
+		}
+
+		status.VPCs[vpcName] = vpcStatus
+	}
+
+	if slices.ContainsFunc(slices.Collect(maps.Values(peering.Spec.Peering)), func(e *gwapi.PeeringEntry) bool { return e != nil && e.QoS != nil }) {
+		agents := &gwintapi.GatewayAgentList{}
+		if err := kube.List(ctx, agents); err != nil {
+			return status, fmt.Errorf("listing gateway agents: %w", err)
+		}
+
+		for vpcName, vpcStatus := range status.VPCs {
+			vpcStatus.QoS = peeringQoSStatus(peering, vpcName, agents.Items)
+			status.VPCs[vpcName] = vpcStatus
+		}
+	}
+
+	if !slices.ContainsFunc(slices.Collect(maps.Values(peering.Spec.Peering)), func(e *gwapi.PeeringEntry) bool { return e != nil && e.Transit }) {
+		return status, nil
+	}
+
+	edges, err := transitEdges(ctx, kube, peering.Namespace, nil)
+	if err != nil {
+		return status, err
+	}
+	transit, err := computeTransit(edges, peering.Name)
+	if err != nil {
+		status.Error = err.Error()
+
+		return status, nil
+	}
+	for vpcName, paths := range transit[peering.Name] {
+		vpcStatus := status.VPCs[vpcName]
+		for _, path := range paths {
+			vpcStatus.Transit = append(vpcStatus.Transit, gwapi.PeeringTransitPath{
+				Path:   path.path,
+				Routes: path.routes.Strings(),
+			})
+		}
+		status.VPCs[vpcName] = vpcStatus
+	}
+
+	return status, nil
+}
+
+// peeringQoSStatus returns the configured QoS policies of the VPC with the drops of the policers summed over the
+// gateway agents reporting the policer counters
+func peeringQoSStatus(peering *gwapi.Peering, vpcName string, agents []gwintapi.GatewayAgent) *gwapi.PeeringQoSStatus {
+	entry := peering.Spec.Peering[vpcName]
+	if entry == nil || entry.QoS == nil {
+		return nil
+	}
+
+	res := &gwapi.PeeringQoSStatus{}
+	if entry.QoS.Egress != nil {
+		res.Egress = &gwapi.PeeringQoSPolicyStatus{PeeringQoSPolicy: *entry.QoS.Egress}
+	}
+	if entry.QoS.Ingress != nil {
+		res.Ingress = &gwapi.PeeringQoSPolicyStatus{PeeringQoSPolicy: *entry.QoS.Ingress}
+	}
+
+	for _, ag := range agents {
+		vpc, ok := ag.Status.Peerings[agentName(ag.Namespace, peering.Namespace, peering.Name)].VPCs[vpcName]
+		if !ok {
+			continue
+		}
+
+		// only the gateways actually running the policers report their counters
+		if res.Egress != nil && vpc.EgressDroppedPackets != nil {
+			res.Egress.DroppedPackets += *vpc.EgressDroppedPackets
+			res.Egress.Gateways++
+		}
+		if res.Ingress != nil && vpc.IngressDroppedPackets != nil {
+			res.Ingress.DroppedPackets += *vpc.IngressDroppedPackets
+			res.Ingress.Gateways++
+		}
+	}
+
+	return res
+}
+
+// peeringTraffic returns the traffic of each side of the peering summed over the gateway agents reporting it or nil
+// if none of them does
+func peeringTraffic(peering *gwapi.Peering, agents []gwintapi.GatewayAgent, now time.Time) *gwapi.PeeringTrafficStatus {
+	res := &gwapi.PeeringTrafficStatus{
+		UpdatedAt: kmetav1.NewTime(now),
+		VPCs:      map[string]gwapi.PeeringVPCTrafficStatus{},
+	}
+
+	for _, ag := range agents {
+		agPeering, ok := ag.Status.Peerings[agentName(ag.Namespace, peering.Namespace, peering.Name)]
+		if !ok {
+			continue
+		}
+		res.Gateways++
+
+		for vpcName, vpc := range agPeering.VPCs {
+			if _, ok := peering.Spec.Peering[vpcName]; !ok {
+				continue
+			}
+
+			traffic := res.VPCs[vpcName]
+			traffic.Add(vpc.PeeringVPCTrafficStatus)
+			res.VPCs[vpcName] = traffic
+		}
+	}
+
+	if res.Gateways == 0 {
+		return nil
+	}
+
+	return res
+}
+
+// exposeNAT returns the source NAT mapping of the expose from the ips to the as entries or to the addresses they're
+// translated to
+func exposeNAT(expose *gwapi.PeeringEntryExpose, subnets map[string][]string) (gwapi.PeeringNATStatus, error) {
+	ips, translated, err := expose.Sets(subnets)
+	if err != nil {
+		return gwapi.PeeringNATStatus{}, err //nolint:wrapcheck
+	}
+
+	if expose.Translation != nil {
+		return gwapi.PeeringNATStatus{
+			From: ips.Strings(),
+			To:   translated.Strings(),
+		}, nil
+	}
+
+	as := asEntries(expose.As)
+
+	return gwapi.PeeringNATStatus{
+		From:         entriesSet(expose.IPs, subnets, "").Strings(),
+		To:           entriesSet(as, nil, "").Strings(),
+		ExcludedFrom: notEntriesSet(expose.IPs).Strings(),
+		ExcludedTo:   notEntriesSet(as).Strings(),
+	}, nil
+}
+
+// entriesSet returns the union of the cidr and vpcSubnet entries (or the fallback if there are none), the entries
+// are expected to be validated already
+func entriesSet(entries []gwapi.PeeringEntryIP, subnets map[string][]string, fallback string) *prefixset.Set {
+	cidrs := []string{}
+	for _, entry := range entries {
+		switch {
+		case entry.CIDR != "":
+			cidrs = append(cidrs, entry.CIDR)
+		case entry.VPCSubnet != "":
+			cidrs = append(cidrs, subnets[entry.VPCSubnet]...)
+		}
+	}
+	if len(cidrs) == 0 && fallback != "" {
+		cidrs = append(cidrs, fallback)
+	}
+
+	set, err := prefixset.Parse(cidrs...)
+	if err != nil {
+		return &prefixset.Set{}
+	}
+
+	return set
+}
+
+// notEntriesSet returns the union of the not entries, the entries are expected to be validated already
+func notEntriesSet(entries []gwapi.PeeringEntryIP) *prefixset.Set {
+	cidrs := []string{}
+	for _, entry := range entries {
+		if entry.Not != "" {
+			cidrs = append(cidrs, entry.Not)
+		}
+	}
+
+	set, err := prefixset.Parse(cidrs...)
+	if err != nil {
+		return &prefixset.Set{}
+	}
+
+	return set
+}
+
+func asEntries(as []gwapi.PeeringEntryAs) []gwapi.PeeringEntryIP {
+	res := make([]gwapi.PeeringEntryIP, 0, len(as))
+	for _, entry := range as {
+		res = append(res, gwapi.PeeringEntryIP{CIDR: entry.CIDR, Not: entry.Not})
+	}
+
+	return res
+}
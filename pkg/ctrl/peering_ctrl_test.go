@@ -0,0 +1,297 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestPeeringQoSStatus(t *testing.T) {
+	egress := gwapi.PeeringQoSPolicy{Bandwidth: "100M"}
+	peering := &gwapi.Peering{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-1", Name: "vpc-1--vpc-2"},
+		Spec: gwapi.PeeringSpec{
+			Peering: map[string]*gwapi.PeeringEntry{
+				"vpc-1": {QoS: &gwapi.PeeringEntryQoS{Egress: &egress}},
+				"vpc-2": {},
+			},
+		},
+	}
+
+	agent := func(ns, key string, egress, ingress *uint64) gwintapi.GatewayAgent {
+		return gwintapi.GatewayAgent{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: ns, Name: "gw"},
+			Status: gwintapi.GatewayAgentStatus{Peerings: map[string]gwintapi.PeeringAgentStatus{
+				key: {VPCs: map[string]gwintapi.PeeringVPCAgentStatus{
+					"vpc-1": {EgressDroppedPackets: egress, IngressDroppedPackets: ingress},
+				}},
+			}},
+		}
+	}
+	agents := []gwintapi.GatewayAgent{
+		agent("default", "tenant-1..vpc-1--vpc-2", ptr.To(uint64(10)), ptr.To(uint64(1))),
+		agent("tenant-1", "vpc-1--vpc-2", ptr.To(uint64(5)), ptr.To(uint64(1))),
+		agent("default", "vpc-1--vpc-2", ptr.To(uint64(100)), ptr.To(uint64(100))),
+		agent("tenant-1", "tenant-1..vpc-1--vpc-2", nil, nil),
+		{ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "gw-idle"}},
+	}
+
+	require.Equal(t, &gwapi.PeeringQoSStatus{
+		Egress: &gwapi.PeeringQoSPolicyStatus{PeeringQoSPolicy: egress, DroppedPackets: 15, Gateways: 2},
+	}, peeringQoSStatus(peering, "vpc-1", agents))
+	require.Nil(t, peeringQoSStatus(peering, "vpc-2", agents))
+}
+
+func TestPeeringTraffic(t *testing.T) {
+	peering := &gwapi.Peering{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-1", Name: "vpc-1--vpc-2"},
+		Spec: gwapi.PeeringSpec{
+			Peering: map[string]*gwapi.PeeringEntry{
+				"vpc-1": {},
+				"vpc-2": {},
+			},
+		},
+	}
+
+	agent := func(ns, key string, vpcs map[string]gwapi.PeeringVPCTrafficStatus) gwintapi.GatewayAgent {
+		ag := gwintapi.GatewayAgent{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: ns, Name: "gw"},
+			Status: gwintapi.GatewayAgentStatus{Peerings: map[string]gwintapi.PeeringAgentStatus{
+				key: {VPCs: map[string]gwintapi.PeeringVPCAgentStatus{}},
+			}},
+		}
+		for vpcName, traffic := range vpcs {
+			ag.Status.Peerings[key].VPCs[vpcName] = gwintapi.PeeringVPCAgentStatus{PeeringVPCTrafficStatus: traffic}
+		}
+
+		return ag
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.Nil(t, peeringTraffic(peering, []gwintapi.GatewayAgent{
+		agent("default", "vpc-1--vpc-2", nil),
+		{ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "gw-idle"}},
+	}, now))
+
+	require.Equal(t, &gwapi.PeeringTrafficStatus{
+		UpdatedAt: kmetav1.NewTime(now),
+		Gateways:  2,
+		VPCs: map[string]gwapi.PeeringVPCTrafficStatus{
+			"vpc-1": {EgressBytes: 3000, EgressPackets: 3, IngressBytes: 300, IngressPackets: 2, NATSessions: 5},
+			"vpc-2": {EgressBytes: 300, EgressPackets: 2, IngressBytes: 3000, IngressPackets: 3},
+		},
+	}, peeringTraffic(peering, []gwintapi.GatewayAgent{
+		agent("default", "tenant-1..vpc-1--vpc-2", map[string]gwapi.PeeringVPCTrafficStatus{
+			"vpc-1": {EgressBytes: 1000, EgressPackets: 1, IngressBytes: 100, IngressPackets: 1, NATSessions: 2},
+			"vpc-2": {EgressBytes: 100, EgressPackets: 1, IngressBytes: 1000, IngressPackets: 1},
+			"vpc-3": {EgressBytes: 1},
+		}),
+		agent("tenant-1", "vpc-1--vpc-2", map[string]gwapi.PeeringVPCTrafficStatus{
+			"vpc-1": {EgressBytes: 2000, EgressPackets: 2, IngressBytes: 200, IngressPackets: 1, NATSessions: 3},
+			"vpc-2": {EgressBytes: 200, EgressPackets: 1, IngressBytes: 2000, IngressPackets: 2},
+		}),
+		agent("default", "vpc-1--vpc-2", map[string]gwapi.PeeringVPCTrafficStatus{
+			"vpc-1": {EgressBytes: 100000},
+		}),
+	}, now))
+}
+
+func TestPeeringStatus(t *testing.T) {
+	manyHoles := []gwapi.PeeringEntryIP{{CIDR: "10.2.0.0/16"}}
+	for idx := range 64 {
+		manyHoles = append(manyHoles, gwapi.PeeringEntryIP{Not: fmt.Sprintf("10.2.%d.0/24", idx*2)})
+	}
+
+	kube := kubetest.NewReader(
+		testVPC("vpc-1", "10.1.0.0/24"),
+		testVPC("vpc-2", "10.2.0.0/16"),
+		&gwapi.External{ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "ext-1"}},
+		&gwapi.External{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "ext-filtered"},
+			Spec:       gwapi.ExternalSpec{Import: []gwapi.ExternalPrefixFilter{{CIDR: "0.0.0.0/0"}}},
+		},
+	)
+	vpc1 := &gwapi.PeeringEntry{Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}}
+
+	for _, tt := range []struct {
+		name          string
+		entries       map[string]*gwapi.PeeringEntry
+		advertisement gwapi.PeeringAdvertisementMode
+		expected      gwapi.PeeringStatus
+	}{
+		{
+			name: "routes",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.2.0.0/24"}, {CIDR: "10.2.1.0/24"}}}}},
+			},
+			expected: gwapi.PeeringStatus{VPCs: map[string]gwapi.PeeringVPCStatus{
+				"vpc-1": {Routes: []string{"10.2.0.0/23"}, RouteCount: 1, Advertisement: gwapi.PeeringAdvertisementModeFull},
+				"vpc-2": {Routes: []string{"10.1.0.0/24"}, RouteCount: 1, Advertisement: gwapi.PeeringAdvertisementModeFull},
+			}},
+		},
+		{
+			name: "consume-only",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": nil,
+			},
+			expected: gwapi.PeeringStatus{
+				Error: "not configured on the gateways: vpc vpc-2 doesn't expose anything, consume-only vpcs aren't supported by the dataplane yet",
+			},
+		},
+		{
+			name: "nat",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{
+					IPs: []gwapi.PeeringEntryIP{{CIDR: "10.2.0.0/23"}, {Not: "10.2.1.0/24"}},
+					As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.0.0/23"}, {Not: "192.168.0.0/24"}},
+				}}},
+			},
+			expected: gwapi.PeeringStatus{VPCs: map[string]gwapi.PeeringVPCStatus{
+				"vpc-1": {
+					Routes:        []string{"192.168.1.0/24"},
+					RouteCount:    1,
+					Advertisement: gwapi.PeeringAdvertisementModeFull,
+					DestinationNAT: []gwapi.PeeringNATStatus{{
+						From:         []string{"192.168.0.0/23"},
+						To:           []string{"10.2.0.0/23"},
+						ExcludedFrom: []string{"192.168.0.0/24"},
+						ExcludedTo:   []string{"10.2.1.0/24"},
+					}},
+				},
+				"vpc-2": {
+					Routes:        []string{"10.1.0.0/24"},
+					RouteCount:    1,
+					Advertisement: gwapi.PeeringAdvertisementModeFull,
+					SourceNAT: []gwapi.PeeringNATStatus{{
+						From:         []string{"10.2.0.0/23"},
+						To:           []string{"192.168.0.0/23"},
+						ExcludedFrom: []string{"10.2.1.0/24"},
+						ExcludedTo:   []string{"192.168.0.0/24"},
+					}},
+				},
+			}},
+		},
+		{
+			name: "summarized",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{IPs: manyHoles}}},
+			},
+			expected: gwapi.PeeringStatus{VPCs: map[string]gwapi.PeeringVPCStatus{
+				"vpc-1": {Routes: []string{"10.2.0.0/16"}, RouteCount: 65, Summarized: true, Advertisement: gwapi.PeeringAdvertisementModeFull},
+				"vpc-2": {Routes: []string{"10.1.0.0/24"}, RouteCount: 1, Advertisement: gwapi.PeeringAdvertisementModeFull},
+			}},
+		},
+		{
+			name: "external",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"ext-1": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{Not: "10.0.0.0/8"}}}}},
+			},
+			expected: gwapi.PeeringStatus{VPCs: map[string]gwapi.PeeringVPCStatus{
+				"vpc-1": {
+					Routes:        []string{"0.0.0.0/5", "8.0.0.0/7", "11.0.0.0/8", "12.0.0.0/6", "16.0.0.0/4", "32.0.0.0/3", "64.0.0.0/2", "128.0.0.0/1"},
+					RouteCount:    8,
+					Advertisement: gwapi.PeeringAdvertisementModeFull,
+				},
+				"ext-1": {Routes: []string{"10.1.0.0/24"}, RouteCount: 1},
+			}},
+		},
+		{
+			name: "external-ipv6",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"ext-1": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "0.0.0.0/0"}, {CIDR: "::/0"}}}}},
+			},
+			expected: gwapi.PeeringStatus{
+				Error: "not configured on the gateways: external ext-1 expose 0: ipv6 routes aren't supported for externals yet",
+			},
+		},
+		{
+			name: "default-mode",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.2.0.0/24"}}}}},
+			},
+			advertisement: gwapi.PeeringAdvertisementModeDefault,
+			expected: gwapi.PeeringStatus{VPCs: map[string]gwapi.PeeringVPCStatus{
+				"vpc-1": {Routes: []string{"10.2.0.0/24"}, RouteCount: 1, Advertisement: gwapi.PeeringAdvertisementModeDefault},
+				"vpc-2": {Routes: []string{"10.1.0.0/24"}, RouteCount: 1, Advertisement: gwapi.PeeringAdvertisementModeDefault},
+			}},
+		},
+		{
+			name: "unsupported",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-2": {
+					Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.2.0.0/24"}}}},
+					QoS:    &gwapi.PeeringEntryQoS{Egress: &gwapi.PeeringQoSPolicy{Bandwidth: "100M"}},
+				},
+			},
+			expected: gwapi.PeeringStatus{Error: "not configured on the gateways: vpc vpc-2: qos policies aren't supported by the dataplane yet"},
+		},
+		{
+			name: "unsupported-external",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1":        vpc1,
+				"ext-filtered": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "0.0.0.0/0"}}}}},
+			},
+			expected: gwapi.PeeringStatus{
+				Error: "not configured on the gateways: external ext-filtered: import/export prefix filters aren't supported by the dataplane yet",
+			},
+		},
+		{
+			name: "missing-vpc",
+			entries: map[string]*gwapi.PeeringEntry{
+				"vpc-1": vpc1,
+				"vpc-3": {},
+			},
+			expected: gwapi.PeeringStatus{Error: "vpc or external vpc-3 not found"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			peering := testPeering("peering", tt.entries)
+			if tt.advertisement != "" {
+				peering.Spec.Advertisement = &gwapi.PeeringAdvertisement{Mode: tt.advertisement}
+			}
+
+			status, err := peeringStatus(context.Background(), kube, peering)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, status)
+		})
+	}
+}
+
+func TestEntriesSet(t *testing.T) {
+	subnets := map[string][]string{"subnet-1": {"10.1.0.0/24", "fd00:1::/64"}}
+
+	for _, tt := range []struct {
+		name     string
+		entries  []gwapi.PeeringEntryIP
+		fallback string
+		expected []string
+	}{
+		{"cidrs", []gwapi.PeeringEntryIP{{CIDR: "10.2.0.0/24"}, {CIDR: "10.2.1.0/24"}, {Not: "10.2.0.0/25"}}, "", []string{"10.2.0.0/23"}},
+		{"vpc-subnet", []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}, "", []string{"10.1.0.0/24", "fd00:1::/64"}},
+		{"fallback", []gwapi.PeeringEntryIP{{Not: "10.0.0.0/8"}}, "0.0.0.0/0", []string{"0.0.0.0/0"}},
+		{"no-fallback", []gwapi.PeeringEntryIP{{Not: "10.0.0.0/8"}}, "", []string{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, entriesSet(tt.entries, subnets, tt.fallback).Strings())
+		})
+	}
+}
@@ -6,12 +6,16 @@ package ctrl
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	kctrl "sigs.k8s.io/controller-runtime"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/prefixset"
 )
 
 // +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-peering,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=peerings,verbs=create;update;delete,versions=v1alpha1,name=mpeering.kb.io,admissionReviewVersions=v1
@@ -44,16 +48,279 @@ func (w *PeeringWebhook) Default(_ context.Context, obj *gwapi.Peering) error {
 }
 
 func (w *PeeringWebhook) ValidateCreate(ctx context.Context, obj *gwapi.Peering) (admission.Warnings, error) {
-	return nil, obj.Validate(ctx, w.Reader) //nolint:wrapcheck
+	if err := obj.Validate(ctx, w.Reader); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if err := w.validateVPCPair(ctx, obj); err != nil {
+		return nil, err
+	}
+
+	if err := w.validatePortForwards(ctx, obj); err != nil {
+		return nil, err
+	}
+
+	if err := w.validateTransit(ctx, obj); err != nil {
+		return nil, err
+	}
+
+	if err := gwapi.EnforceTenantQuotas(ctx, w.Reader, obj); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return w.warnings(ctx, obj)
 }
 
 func (w *PeeringWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.Peering, newObj *gwapi.Peering) (admission.Warnings, error) {
 	// TODO validate diff between oldObj and newObj if needed
 	_ = oldObj
 
-	return nil, newObj.Validate(ctx, w.Reader) //nolint:wrapcheck
+	if err := newObj.Validate(ctx, w.Reader); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if err := w.validateVPCPair(ctx, newObj); err != nil {
+		return nil, err
+	}
+
+	if err := w.validatePortForwards(ctx, newObj); err != nil {
+		return nil, err
+	}
+
+	if err := w.validateTransit(ctx, newObj); err != nil {
+		return nil, err
+	}
+
+	if err := gwapi.EnforceTenantQuotas(ctx, w.Reader, newObj); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return w.warnings(ctx, newObj)
 }
 
 func (w *PeeringWebhook) ValidateDelete(_ context.Context, _ *gwapi.Peering) (admission.Warnings, error) {
 	return nil, nil
 }
+
+// validateVPCPair makes sure the VPC labels match the peered VPCs so the peering is indexed correctly and that
+// there is no other peering between the same VPCs
+func (w *PeeringWebhook) validateVPCPair(ctx context.Context, obj *gwapi.Peering) error {
+	vpcs := slices.Sorted(maps.Keys(obj.Spec.Peering))
+	pair := gwapi.PeeringName(vpcs[0], vpcs[1])
+
+	if labeled := obj.LabeledVPCPair(); labeled != pair {
+		return fmt.Errorf("vpc labels (%s) must match the peered vpcs (%s)", labeled, pair) //nolint:goerr113
+	}
+
+	peerings := &gwapi.PeeringList{}
+	if err := w.List(ctx, peerings, kclient.InNamespace(obj.Namespace), kclient.MatchingFields{
+		gwapi.PeeringVPCPairField: pair,
+	}); err != nil {
+		return fmt.Errorf("listing peerings for vpcs %s: %w", pair, err)
+	}
+
+	for _, other := range peerings.Items {
+		if other.Name != obj.Name {
+			return fmt.Errorf("peering %s already exists between vpcs %s and %s", other.Name, vpcs[0], vpcs[1]) //nolint:goerr113
+		}
+	}
+
+	return nil
+}
+
+// validateTransit makes sure the peering doesn't introduce transit loops or ambiguous routes, only the paths through
+// the peering are checked so the existing issues elsewhere in the namespace don't block it
+func (w *PeeringWebhook) validateTransit(ctx context.Context, obj *gwapi.Peering) error {
+	edges, err := transitEdges(ctx, w.Reader, obj.Namespace, obj)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(edges, func(e transitEdge) bool { return slices.Contains(slices.Collect(maps.Values(e.transit)), true) }) {
+		return nil
+	}
+
+	if _, err := computeTransit(edges, obj.Name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validatePortForwards makes sure the port forwards of each VPC don't collide with the ones in its other peerings
+// (incl. the meshes and the accepted requests), the same port could only be forwarded to the same service in all of
+// them
+func (w *PeeringWebhook) validatePortForwards(ctx context.Context, obj *gwapi.Peering) error {
+	for vpcName, entry := range obj.Spec.Peering {
+		if entry == nil || !slices.ContainsFunc(entry.Expose, func(e gwapi.PeeringEntryExpose) bool { return len(e.PortForwards) > 0 }) {
+			continue
+		}
+
+		exposes, err := w.otherExposes(ctx, obj, vpcName)
+		if err != nil {
+			return err
+		}
+
+		type forward struct {
+			by     string
+			target string
+		}
+		forwards := map[string]forward{}
+		for _, by := range slices.Sorted(maps.Keys(exposes)) {
+			for _, expose := range exposes[by] {
+				for _, pf := range expose.PortForwards {
+					key, keyErr := pf.Key()
+					target, targetErr := pf.Target()
+					if keyErr == nil && targetErr == nil {
+						forwards[key] = forward{by: by, target: target}
+					}
+				}
+			}
+		}
+
+		services := &gwapi.VirtualServiceList{}
+		if err := w.List(ctx, services, kclient.InNamespace(obj.Namespace), kclient.MatchingLabels{
+			gwapi.ListLabelVPC(vpcName): gwapi.ListLabelValue,
+		}); err != nil {
+			return fmt.Errorf("listing virtual services for vpc %s: %w", vpcName, err)
+		}
+		vips := map[string]string{}
+		for _, vs := range services.Items {
+			if key, err := vs.PortForward().Key(); err == nil {
+				vips[key] = vs.Name
+			}
+		}
+
+		for _, expose := range entry.Expose {
+			for _, pf := range expose.PortForwards {
+				key, err := pf.Key()
+				if err != nil {
+					return fmt.Errorf("vpc %s: %w", vpcName, err)
+				}
+				target, err := pf.Target()
+				if err != nil {
+					return fmt.Errorf("vpc %s: %w", vpcName, err)
+				}
+				if other, exists := forwards[key]; exists && other.target != target {
+					return fmt.Errorf("vpc %s port forward %s to %s collides with the one to %s in %s", //nolint:goerr113
+						vpcName, key, target, other.target, other.by)
+				}
+				if vs, exists := vips[key]; exists {
+					return fmt.Errorf("vpc %s port forward %s collides with virtual service %s", vpcName, key, vs) //nolint:goerr113
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// otherExposes returns the exposes of the VPC in all other peerings of its namespace keyed by what they belong to:
+// the peerings, the meshes and the accepted requests and acceptances
+func (w *PeeringWebhook) otherExposes(ctx context.Context, obj *gwapi.Peering, vpcName string) (map[string][]gwapi.PeeringEntryExpose, error) {
+	res := map[string][]gwapi.PeeringEntryExpose{}
+
+	peerings := &gwapi.PeeringList{}
+	if err := w.List(ctx, peerings, kclient.InNamespace(obj.Namespace), kclient.MatchingLabels{
+		gwapi.ListLabelVPC(vpcName): gwapi.ListLabelValue,
+	}); err != nil {
+		return nil, fmt.Errorf("listing peerings for vpc %s: %w", vpcName, err)
+	}
+	for _, other := range peerings.Items {
+		if other.Name != obj.Name && other.Spec.Peering[vpcName] != nil {
+			res["peering "+other.Name] = other.Spec.Peering[vpcName].Expose
+		}
+	}
+
+	meshes := &gwapi.PeeringMeshList{}
+	if err := w.List(ctx, meshes, kclient.InNamespace(obj.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing peering meshes: %w", err)
+	}
+	for _, mesh := range meshes.Items {
+		if mesh.Spec.Peering[vpcName] != nil {
+			res["peering mesh "+mesh.Name] = mesh.Spec.Peering[vpcName].Expose
+		}
+	}
+
+	reqs := &gwapi.PeeringRequestList{}
+	if err := w.List(ctx, reqs, kclient.InNamespace(obj.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing peering requests: %w", err)
+	}
+	for _, req := range reqs.Items {
+		if req.Spec.VPC == vpcName && req.Status.State == gwapi.PeeringRequestStateAccepted {
+			res["peering request "+req.Name] = req.Spec.Expose
+		}
+	}
+
+	accs := &gwapi.PeeringAcceptanceList{}
+	if err := w.List(ctx, accs, kclient.InNamespace(obj.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing peering acceptances: %w", err)
+	}
+	for _, acc := range accs.Items {
+		if acc.Status.State != gwapi.PeeringRequestStateAccepted {
+			continue
+		}
+
+		req := &gwapi.PeeringRequest{}
+		if err := w.Get(ctx, kclient.ObjectKey{Namespace: acc.Spec.RequestNamespace, Name: acc.Spec.Request}, req); err != nil {
+			if kapierrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("getting peering request %s/%s: %w", acc.Spec.RequestNamespace, acc.Spec.Request, err)
+		}
+		if req.Spec.RemoteVPC == vpcName {
+			res["peering acceptance "+acc.Name] = acc.Spec.Expose
+		}
+	}
+
+	return res, nil
+}
+
+// warnings returns the prefixes advertised into the VPCs of the peering by other peerings (incl. the mesh pairs and the
+// accepted requests) as well, it's up to the dataplane which one is used as the route metrics aren't supported yet
+func (w *PeeringWebhook) warnings(ctx context.Context, obj *gwapi.Peering) (admission.Warnings, error) {
+	warnings := admission.Warnings{}
+
+	edges, err := transitEdges(ctx, w.Reader, obj.Namespace, obj)
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(edges, func(e transitEdge) bool { return e.peering == obj.Name })
+	if idx < 0 {
+		return warnings, nil
+	}
+	own := edges[idx]
+
+	for _, vpcName := range slices.Sorted(maps.Keys(own.exposed)) {
+		routes := advertisedInto(own, vpcName)
+
+		for _, other := range edges {
+			if _, peered := other.exposed[vpcName]; !peered || other.peering == obj.Name {
+				continue
+			}
+
+			if overlap := routes.Intersect(advertisedInto(other, vpcName)); !overlap.IsEmpty() {
+				warnings = append(warnings, fmt.Sprintf("prefixes %s are advertised into vpc %s by both peering %s and %s",
+					overlap, vpcName, obj.Name, other.peering))
+			}
+		}
+	}
+
+	slices.Sort(warnings)
+
+	return warnings, nil
+}
+
+// advertisedInto returns the prefixes the peering advertises into the VRF of the specified VPC, which are the "as"
+// pools exposed by the other side or its "ips" if no NAT is used
+func advertisedInto(edge transitEdge, vpcName string) *prefixset.Set {
+	routes := &prefixset.Set{}
+	for peerName, exposed := range edge.exposed {
+		if peerName != vpcName {
+			routes = routes.Union(exposed)
+		}
+	}
+
+	return routes
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func testVPC(name string, cidrs ...string) *gwapi.VPCInfo {
+	vpc := &gwapi.VPCInfo{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       gwapi.VPCInfoSpec{Subnets: map[string]*gwapi.VPCInfoSubnet{}},
+	}
+	for idx, cidr := range cidrs {
+		vpc.Spec.Subnets["subnet-"+string(rune('1'+idx))] = &gwapi.VPCInfoSubnet{CIDR: cidr}
+	}
+
+	return vpc
+}
+
+func testPeering(name string, entries map[string]*gwapi.PeeringEntry) *gwapi.Peering {
+	peering := &gwapi.Peering{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       gwapi.PeeringSpec{Peering: entries},
+	}
+	peering.Default()
+
+	return peering
+}
+
+func testPeeringWebhook(objs ...kclient.Object) *PeeringWebhook {
+	return &PeeringWebhook{
+		Reader: kubetest.NewReader(objs...).WithIndex(gwapi.PeeringVPCPairField, peeringVPCPairIndex),
+	}
+}
+
+func TestPeeringWebhookWarnings(t *testing.T) {
+	expose := func(ips, as string) []gwapi.PeeringEntryExpose {
+		return []gwapi.PeeringEntryExpose{{
+			IPs: []gwapi.PeeringEntryIP{{CIDR: ips}},
+			As:  []gwapi.PeeringEntryAs{{CIDR: as}},
+		}}
+	}
+	other := testPeering("vpc-1--vpc-3", map[string]*gwapi.PeeringEntry{
+		"vpc-1": {Expose: expose("10.1.0.0/24", "192.168.1.0/24")},
+		"vpc-3": {Expose: expose("10.3.0.0/24", "192.168.0.0/24")},
+	})
+	mesh := &gwapi.PeeringMesh{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "mesh-1"},
+		Spec: gwapi.PeeringMeshSpec{Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Expose: expose("10.1.0.0/24", "192.168.4.0/24")},
+			"vpc-4": {Expose: expose("10.4.0.0/24", "192.168.10.0/24")},
+		}},
+	}
+	remote := testVPC("vpc-5", "10.5.0.0/24")
+	remote.Namespace = "tenant-b"
+	req := &gwapi.PeeringRequest{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "to-b"},
+		Spec: gwapi.PeeringRequestSpec{
+			VPC: "vpc-1", RemoteNamespace: "tenant-b", RemoteVPC: "vpc-5", Expose: expose("10.1.0.0/24", "192.168.5.0/24"),
+		},
+	}
+	acc := &gwapi.PeeringAcceptance{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-b", Name: "from-a"},
+		Spec: gwapi.PeeringAcceptanceSpec{
+			RequestNamespace: "default", Request: "to-b", Expose: expose("10.5.0.0/24", "192.168.20.0/24"),
+		},
+	}
+	objs := []kclient.Object{
+		testVPC("vpc-1", "10.1.0.0/24"),
+		testVPC("vpc-2", "10.2.0.0/24"),
+		testVPC("vpc-3", "10.3.0.0/24"),
+		testVPC("vpc-4", "10.4.0.0/24"),
+		remote,
+		other,
+		mesh,
+		req,
+		acc,
+	}
+
+	for _, tt := range []struct {
+		name     string
+		as       string
+		expected []string
+	}{
+		{"peering", "192.168.0.0/24", []string{"prefixes [192.168.0.0/24] are advertised into vpc vpc-1 by both peering vpc-1--vpc-2 and vpc-1--vpc-3"}},
+		{"mesh", "192.168.10.0/24", []string{"prefixes [192.168.10.0/24] are advertised into vpc vpc-1 by both peering vpc-1--vpc-2 and mesh-1/vpc-1--vpc-4"}},
+		{"request", "192.168.20.0/24", []string{"prefixes [192.168.20.0/24] are advertised into vpc vpc-1 by both peering vpc-1--vpc-2 and default/to-b@tenant-b"}},
+		{"no-overlap", "192.168.30.0/24", []string{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			peering := testPeering("vpc-1--vpc-2", map[string]*gwapi.PeeringEntry{
+				"vpc-1": {Expose: expose("10.1.0.0/24", "192.168.2.0/24")},
+				"vpc-2": {Expose: expose("10.2.0.0/24", tt.as)},
+			})
+
+			warnings, err := testPeeringWebhook(append(objs, peering)...).warnings(context.Background(), peering)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, []string(warnings))
+		})
+	}
+}
+
+func TestPeeringWebhookValidateVPCPair(t *testing.T) {
+	entries := func() map[string]*gwapi.PeeringEntry {
+		return map[string]*gwapi.PeeringEntry{"vpc-1": {}, "vpc-2": {}}
+	}
+	existing := testPeering("vpc-1--vpc-2", entries())
+	w := testPeeringWebhook(existing, testPeering("vpc-1--vpc-3", map[string]*gwapi.PeeringEntry{"vpc-1": {}, "vpc-3": {}}))
+
+	for _, tt := range []struct {
+		name    string
+		peering *gwapi.Peering
+		err     string
+	}{
+		{"update", testPeering("vpc-1--vpc-2", entries()), ""},
+		{"new-pair", testPeering("vpc-2--vpc-3", map[string]*gwapi.PeeringEntry{"vpc-2": {}, "vpc-3": {}}), ""},
+		{"duplicate", testPeering("other", entries()), "peering vpc-1--vpc-2 already exists between vpcs vpc-1 and vpc-2"},
+		{"labels-mismatch", func() *gwapi.Peering {
+			peering := testPeering("other", entries())
+			delete(peering.Labels, gwapi.ListLabelVPC("vpc-2"))
+			peering.Labels[gwapi.ListLabelVPC("vpc-3")] = gwapi.ListLabelValue
+
+			return peering
+		}(), "vpc labels (vpc-1--vpc-3) must match the peered vpcs (vpc-1--vpc-2)"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := w.validateVPCPair(context.Background(), tt.peering)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPeeringWebhookValidatePortForwards(t *testing.T) {
+	forward := func(internalIP string) []gwapi.PeeringEntryExpose {
+		return []gwapi.PeeringEntryExpose{{
+			IPs:          []gwapi.PeeringEntryIP{{CIDR: "10.1.0.0/24"}},
+			As:           []gwapi.PeeringEntryAs{{CIDR: "192.168.1.0/24"}},
+			PortForwards: []gwapi.PeeringEntryPortForward{{ExternalIP: "192.168.1.1", ExternalPort: 443, InternalIP: internalIP}},
+		}}
+	}
+	peering := testPeering("vpc-1--vpc-2", map[string]*gwapi.PeeringEntry{
+		"vpc-1": {Expose: forward("10.1.0.10")},
+		"vpc-2": {},
+	})
+
+	for _, tt := range []struct {
+		name  string
+		other kclient.Object
+		err   string
+	}{
+		{
+			name: "peering-same-target",
+			other: testPeering("vpc-1--vpc-3", map[string]*gwapi.PeeringEntry{
+				"vpc-1": {Expose: forward("10.1.0.10")}, "vpc-3": {},
+			}),
+		},
+		{
+			name: "peering",
+			other: testPeering("vpc-1--vpc-3", map[string]*gwapi.PeeringEntry{
+				"vpc-1": {Expose: forward("10.1.0.20")}, "vpc-3": {},
+			}),
+			err: "vpc vpc-1 port forward tcp/192.168.1.1:443 to 10.1.0.10:443 collides with the one to 10.1.0.20:443 in peering vpc-1--vpc-3",
+		},
+		{
+			name: "mesh",
+			other: &gwapi.PeeringMesh{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "mesh-1"},
+				Spec: gwapi.PeeringMeshSpec{Peering: map[string]*gwapi.PeeringEntry{
+					"vpc-1": {Expose: forward("10.1.0.20")}, "vpc-3": {}, "vpc-4": {},
+				}},
+			},
+			err: "vpc vpc-1 port forward tcp/192.168.1.1:443 to 10.1.0.10:443 collides with the one to 10.1.0.20:443 in peering mesh mesh-1",
+		},
+		{
+			name: "accepted-request",
+			other: &gwapi.PeeringRequest{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "to-b"},
+				Spec:       gwapi.PeeringRequestSpec{VPC: "vpc-1", Expose: forward("10.1.0.20"), RemoteNamespace: "tenant-b", RemoteVPC: "vpc-b"},
+				Status:     gwapi.PeeringRequestStatus{State: gwapi.PeeringRequestStateAccepted},
+			},
+			err: "vpc vpc-1 port forward tcp/192.168.1.1:443 to 10.1.0.10:443 collides with the one to 10.1.0.20:443 in peering request to-b",
+		},
+		{
+			name: "pending-request",
+			other: &gwapi.PeeringRequest{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "to-b"},
+				Spec:       gwapi.PeeringRequestSpec{VPC: "vpc-1", Expose: forward("10.1.0.20"), RemoteNamespace: "tenant-b", RemoteVPC: "vpc-b"},
+				Status:     gwapi.PeeringRequestStatus{State: gwapi.PeeringRequestStatePending},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testPeeringWebhook(peering, tt.other).validatePortForwards(context.Background(), peering)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+
+	acc := &gwapi.PeeringAcceptance{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "from-b"},
+		Spec:       gwapi.PeeringAcceptanceSpec{RequestNamespace: "tenant-b", Request: "to-a", Expose: forward("10.1.0.20")},
+		Status:     gwapi.PeeringAcceptanceStatus{State: gwapi.PeeringRequestStateAccepted},
+	}
+	req := &gwapi.PeeringRequest{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-b", Name: "to-a"},
+		Spec:       gwapi.PeeringRequestSpec{VPC: "vpc-b", RemoteNamespace: "default", RemoteVPC: "vpc-1"},
+	}
+	err := testPeeringWebhook(peering, acc, req).validatePortForwards(context.Background(), peering)
+	require.EqualError(t, err, "vpc vpc-1 port forward tcp/192.168.1.1:443 to 10.1.0.10:443 collides with the one to 10.1.0.20:443 in peering acceptance from-b")
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringmeshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringmeshes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
+
+type PeeringMeshReconciler struct {
+	kclient.Client
+}
+
+func SetupPeeringMeshReconcilerWith(mgr kctrl.Manager) error {
+	r := &PeeringMeshReconciler{
+		Client: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("PeeringMesh").
+		For(&gwapi.PeeringMesh{}).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueMeshesInNamespace)).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueueMeshesInNamespace)).
+		Watches(&gwapi.External{}, handler.EnqueueRequestsFromMapFunc(r.enqueueMeshesInNamespace)).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+// enqueueMeshesInNamespace enqueues all meshes in the namespace of the object as the state of their pairs depends on
+// the VPCs and peerings, the changes of the other meshes are handled by updating all meshes of the namespace at once
+func (r *PeeringMeshReconciler) enqueueMeshesInNamespace(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	meshes := &gwapi.PeeringMeshList{}
+	if err := r.List(ctx, meshes, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing peering meshes to reconcile")
+
+		return nil
+	}
+
+	for _, mesh := range meshes.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: mesh.Namespace,
+			Name:      mesh.Name,
+		}})
+	}
+
+	return res
+}
+
+// Reconcile updates the status of all meshes in the namespace of the requested one as the pairs of a mesh could
+// conflict with the pairs of the other meshes
+func (r *PeeringMeshReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	meshes := &gwapi.PeeringMeshList{}
+	if err := r.List(ctx, meshes, kclient.InNamespace(req.Namespace)); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peering meshes: %w", err)
+	}
+
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings, kclient.InNamespace(req.Namespace)); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peerings: %w", err)
+	}
+
+	exists, externals := map[string]bool{}, map[string]bool{}
+	for _, mesh := range meshes.Items {
+		for vpcName := range mesh.Spec.Peering {
+			if _, checked := exists[vpcName]; checked {
+				continue
+			}
+
+			_, isExternal, err := gwapi.GetPeeredSubnets(ctx, r, req.Namespace, vpcName)
+			if err != nil && !kapierrors.IsNotFound(err) {
+				return kctrl.Result{}, fmt.Errorf("getting peered %s: %w", vpcName, err)
+			}
+			exists[vpcName] = err == nil
+			externals[vpcName] = isExternal
+		}
+	}
+
+	// reconcile again at the next window boundary of the peerings as only the active ones conflict with the pairs
+	now := time.Now()
+	res := kctrl.Result{}
+	for _, peering := range peerings.Items {
+		if _, next := peering.Spec.WindowState(now); !next.IsZero() && (res.RequeueAfter == 0 || next.Sub(now) < res.RequeueAfter) {
+			res.RequeueAfter = next.Sub(now)
+		}
+	}
+
+	_, states := expandMeshes(meshes.Items, peerings.Items, now, func(_, name string) bool {
+		return exists[name]
+	}, func(_, name string) bool {
+		return externals[name]
+	})
+
+	for _, mesh := range meshes.Items {
+		if mesh.DeletionTimestamp != nil {
+			continue
+		}
+
+		status := gwapi.PeeringMeshStatus{
+			Pairs: states[ktypes.NamespacedName{Namespace: mesh.Namespace, Name: mesh.Name}],
+		}
+		if equality.Semantic.DeepEqual(mesh.Status, status) {
+			continue
+		}
+
+		kctrllog.FromContext(ctx).Info("Updating PeeringMesh status", "name", mesh.Name, "namespace", mesh.Namespace)
+
+		mesh.Status = status
+		if err := r.Status().Update(ctx, &mesh); err != nil {
+			return kctrl.Result{}, fmt.Errorf("updating peering mesh %s status: %w", mesh.Name, err)
+		}
+	}
+
+	return res, nil
+}
+
+// expandMeshes expands the meshes into the pairwise peerings keyed by their agent names (see meshPeeringName) and reports the state of each
+// pair of each mesh, pairs already peered by a Peering active at now or by another mesh (first by name wins) are
+// reported as conflicts, pairs with VPCs that don't exist yet as pending and pairs the dataplane can't configure as
+// invalid, none of them is returned as peering
+func expandMeshes(meshes []gwapi.PeeringMesh, peerings []gwapi.Peering, now time.Time, exists, isExternal func(namespace, name string) bool) (map[string]gwapi.PeeringSpec, map[ktypes.NamespacedName]map[string]gwapi.PeeringMeshPairStatus) {
+	peered := map[string]string{}
+	for _, peering := range peerings {
+		// the peerings outside of their activity window aren't passed to the dataplane, so the mesh takes over the pair
+		if state, _ := peering.Spec.WindowState(now); state != gwapi.PeeringWindowStateActive {
+			continue
+		}
+
+		vpcs := slices.Sorted(maps.Keys(peering.Spec.Peering))
+		if len(vpcs) != 2 {
+			continue
+		}
+
+		peered[peering.Namespace+"/"+gwapi.PeeringName(vpcs[0], vpcs[1])] = "peering " + peering.Name
+	}
+
+	slices.SortFunc(meshes, func(a, b gwapi.PeeringMesh) int {
+		if a.Namespace != b.Namespace {
+			return cmp.Compare(a.Namespace, b.Namespace)
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	specs := map[string]gwapi.PeeringSpec{}
+	states := map[ktypes.NamespacedName]map[string]gwapi.PeeringMeshPairStatus{}
+	for _, mesh := range meshes {
+		meshStates := map[string]gwapi.PeeringMeshPairStatus{}
+
+		for _, pair := range mesh.Pairs() {
+			key := mesh.Namespace + "/" + pair.Name
+			if by, exists := peered[key]; exists {
+				meshStates[pair.Name] = gwapi.PeeringMeshPairStatus{
+					State:   gwapi.PeeringMeshPairStateConflict,
+					Message: "already peered by " + by,
+				}
+
+				continue
+			}
+			peered[key] = "mesh " + mesh.Name
+
+			missing := ""
+			for _, vpcName := range slices.Sorted(maps.Keys(pair.Spec.Peering)) {
+				if !exists(mesh.Namespace, vpcName) {
+					missing = vpcName
+
+					break
+				}
+			}
+			if missing != "" {
+				meshStates[pair.Name] = gwapi.PeeringMeshPairStatus{
+					State:   gwapi.PeeringMeshPairStatePending,
+					Message: "vpc or external " + missing + " not found",
+				}
+
+				continue
+			}
+
+			if err := pair.Spec.DataplaneUnsupported(func(name string) bool { return isExternal(mesh.Namespace, name) }); err != nil {
+				meshStates[pair.Name] = gwapi.PeeringMeshPairStatus{
+					State:   gwapi.PeeringMeshPairStateInvalid,
+					Message: err.Error(),
+				}
+
+				continue
+			}
+
+			meshStates[pair.Name] = gwapi.PeeringMeshPairStatus{State: gwapi.PeeringMeshPairStateActive}
+			specs[meshPeeringName(mesh.Namespace, mesh.Name, pair.Name)] = pair.Spec
+		}
+
+		states[ktypes.NamespacedName{Namespace: mesh.Namespace, Name: mesh.Name}] = meshStates
+	}
+
+	return specs, states
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestExpandMeshes(t *testing.T) {
+	now := time.Now()
+	entry := func(cidr string) *gwapi.PeeringEntry {
+		return &gwapi.PeeringEntry{Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: cidr}}}}}
+	}
+
+	qos := entry("10.0.6.0/24")
+	qos.QoS = &gwapi.PeeringEntryQoS{Egress: &gwapi.PeeringQoSPolicy{Bandwidth: "100M"}}
+
+	meshes := []gwapi.PeeringMesh{
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "mesh-3"},
+			Spec:       gwapi.PeeringMeshSpec{Peering: map[string]*gwapi.PeeringEntry{"vpc-5": entry("10.0.5.0/24"), "vpc-6": qos}},
+		},
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "mesh-2"},
+			Spec:       gwapi.PeeringMeshSpec{Peering: map[string]*gwapi.PeeringEntry{"vpc-1": entry("10.0.1.0/24"), "vpc-4": entry("10.0.4.0/24")}},
+		},
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "mesh-1"},
+			Spec: gwapi.PeeringMeshSpec{Peering: map[string]*gwapi.PeeringEntry{
+				"vpc-1": entry("10.0.1.0/24"), "vpc-2": entry("10.0.2.0/24"), "vpc-3": entry("10.0.3.0/24"), "vpc-4": entry("10.0.4.0/24"),
+			}},
+		},
+	}
+	peerings := []gwapi.Peering{
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "manual"},
+			Spec:       gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{"vpc-3": entry("10.0.3.0/24"), "vpc-2": entry("10.0.2.0/24")}},
+		},
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "expired"},
+			Spec: gwapi.PeeringSpec{
+				Peering:   map[string]*gwapi.PeeringEntry{"vpc-1": entry("10.0.1.0/24"), "vpc-3": entry("10.0.3.0/24")},
+				ExpiresAt: &kmetav1.Time{Time: now.Add(-time.Hour)},
+			},
+		},
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "upcoming"},
+			Spec: gwapi.PeeringSpec{
+				Peering:    map[string]*gwapi.PeeringEntry{"vpc-1": entry("10.0.1.0/24"), "vpc-2": entry("10.0.2.0/24")},
+				ActiveFrom: &kmetav1.Time{Time: now.Add(time.Hour)},
+			},
+		},
+	}
+
+	specs, states := expandMeshes(meshes, peerings, now, func(_, name string) bool {
+		return name != "vpc-4"
+	}, func(_, _ string) bool { return false })
+
+	require.Len(t, specs, 2)
+	require.Equal(t, gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{
+		"vpc-1": entry("10.0.1.0/24"), "vpc-2": entry("10.0.2.0/24"),
+	}}, specs["default..mesh..mesh-1..vpc-1--vpc-2"])
+	require.Contains(t, specs, "default..mesh..mesh-1..vpc-1--vpc-3")
+
+	require.Equal(t, map[string]gwapi.PeeringMeshPairStatus{
+		"vpc-1--vpc-2": {State: gwapi.PeeringMeshPairStateActive},
+		"vpc-1--vpc-3": {State: gwapi.PeeringMeshPairStateActive},
+		"vpc-1--vpc-4": {State: gwapi.PeeringMeshPairStatePending, Message: "vpc or external vpc-4 not found"},
+		"vpc-2--vpc-3": {State: gwapi.PeeringMeshPairStateConflict, Message: "already peered by peering manual"},
+		"vpc-2--vpc-4": {State: gwapi.PeeringMeshPairStatePending, Message: "vpc or external vpc-4 not found"},
+		"vpc-3--vpc-4": {State: gwapi.PeeringMeshPairStatePending, Message: "vpc or external vpc-4 not found"},
+	}, states[ktypes.NamespacedName{Namespace: "default", Name: "mesh-1"}])
+	require.Equal(t, map[string]gwapi.PeeringMeshPairStatus{
+		"vpc-1--vpc-4": {State: gwapi.PeeringMeshPairStateConflict, Message: "already peered by mesh mesh-1"},
+	}, states[ktypes.NamespacedName{Namespace: "default", Name: "mesh-2"}])
+	require.Equal(t, map[string]gwapi.PeeringMeshPairStatus{
+		"vpc-5--vpc-6": {State: gwapi.PeeringMeshPairStateInvalid, Message: "vpc vpc-6: qos policies aren't supported by the dataplane yet"},
+	}, states[ktypes.NamespacedName{Namespace: "default", Name: "mesh-3"}])
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-peeringmesh,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=peeringmeshes,verbs=create;update;delete,versions=v1alpha1,name=mpeeringmesh.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gateway-githedgehog-com-v1alpha1-peeringmesh,mutating=false,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=peeringmeshes,verbs=create;update;delete,versions=v1alpha1,name=vpeeringmesh.kb.io,admissionReviewVersions=v1
+
+type PeeringMeshWebhook struct {
+	kclient.Reader
+}
+
+func SetupPeeringMeshWebhookWith(mgr kctrl.Manager) error {
+	w := &PeeringMeshWebhook{
+		Reader: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewWebhookManagedBy(mgr).
+		For(&gwapi.PeeringMesh{}).
+		WithDefaulter(FromTypedDefaulter(w)).
+		WithValidator(FromTypedValidator(w)).
+		Complete(); err != nil {
+		return fmt.Errorf("creating webhook: %w", err) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (w *PeeringMeshWebhook) Default(_ context.Context, obj *gwapi.PeeringMesh) error {
+	obj.Default()
+
+	return nil
+}
+
+func (w *PeeringMeshWebhook) ValidateCreate(ctx context.Context, obj *gwapi.PeeringMesh) (admission.Warnings, error) {
+	if err := obj.Validate(ctx, w.Reader); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return nil, gwapi.EnforceTenantQuotas(ctx, w.Reader, obj) //nolint:wrapcheck
+}
+
+func (w *PeeringMeshWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.PeeringMesh, newObj *gwapi.PeeringMesh) (admission.Warnings, error) {
+	// TODO validate diff between oldObj and newObj if needed
+	_ = oldObj
+
+	if err := newObj.Validate(ctx, w.Reader); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return nil, gwapi.EnforceTenantQuotas(ctx, w.Reader, newObj) //nolint:wrapcheck
+}
+
+func (w *PeeringMeshWebhook) ValidateDelete(_ context.Context, _ *gwapi.PeeringMesh) (admission.Warnings, error) {
+	return nil, nil
+}
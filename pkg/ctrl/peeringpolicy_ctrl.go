@@ -0,0 +1,227 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+
+type PeeringPolicyReconciler struct {
+	kclient.Client
+}
+
+func SetupPeeringPolicyReconcilerWith(mgr kctrl.Manager) error {
+	r := &PeeringPolicyReconciler{
+		Client: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("PeeringPolicy").
+		For(&gwapi.PeeringPolicy{}).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePoliciesInNamespace)).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePoliciesInNamespace)).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+// enqueuePoliciesInNamespace enqueues all policies in the namespace of the object as any VPC could start or stop
+// matching a policy and any hand-written peering could start or stop conflicting with it
+func (r *PeeringPolicyReconciler) enqueuePoliciesInNamespace(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	policies := &gwapi.PeeringPolicyList{}
+	if err := r.List(ctx, policies, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing peering policies to reconcile")
+
+		return nil
+	}
+
+	for _, policy := range policies.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: policy.Namespace,
+			Name:      policy.Name,
+		}})
+	}
+
+	return res
+}
+
+func (r *PeeringPolicyReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	policy := &gwapi.PeeringPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("getting peering policy: %w", err)
+	}
+
+	// generated peerings are garbage collected using the owner references
+	if policy.DeletionTimestamp != nil {
+		return kctrl.Result{}, nil
+	}
+
+	selector, err := kmetav1.LabelSelectorAsSelector(&policy.Spec.Selector)
+	if err != nil {
+		return kctrl.Result{}, fmt.Errorf("parsing selector: %w", err)
+	}
+
+	vpcs := &gwapi.VPCInfoList{}
+	if err := r.List(ctx, vpcs, kclient.InNamespace(policy.Namespace), kclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing vpcs: %w", err)
+	}
+
+	status := gwapi.PeeringPolicyStatus{}
+	desired := map[string]bool{}
+
+	for _, vpc := range vpcs.Items {
+		if vpc.Name == policy.Spec.Peer {
+			continue
+		}
+
+		peering := policy.PeeringFor(vpc.Name)
+		if err := ctrlutil.SetControllerReference(policy, peering, r.Scheme()); err != nil {
+			return kctrl.Result{}, fmt.Errorf("setting owner reference: %w", err)
+		}
+
+		owned, conflict, err := r.ensurePeering(ctx, policy, peering)
+		if err != nil {
+			return kctrl.Result{}, fmt.Errorf("ensuring peering %s: %w", peering.Name, err)
+		}
+		// the peering is kept as is if its update is rejected, so it isn't deleted below
+		if owned != "" {
+			desired[owned] = true
+		}
+		if conflict != nil {
+			conflict.VPC = vpc.Name
+			status.Conflicts = append(status.Conflicts, *conflict)
+		}
+	}
+
+	owned := &gwapi.PeeringList{}
+	if err := r.List(ctx, owned, kclient.InNamespace(policy.Namespace), kclient.MatchingLabels{
+		gwapi.LabelPeeringPolicy: policy.Name,
+	}); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing owned peerings: %w", err)
+	}
+
+	for _, peering := range owned.Items {
+		if desired[peering.Name] || !kmetav1.IsControlledBy(&peering, policy) {
+			continue
+		}
+
+		l.Info("Deleting Peering not matched by the policy anymore", "peering", peering.Name, "policy", policy.Name)
+
+		if err := r.Delete(ctx, &peering); err != nil && !kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, fmt.Errorf("deleting peering %s: %w", peering.Name, err)
+		}
+	}
+
+	if len(desired) > 0 {
+		status.Peerings = slices.Sorted(maps.Keys(desired))
+	}
+
+	if equality.Semantic.DeepEqual(policy.Status, status) {
+		return kctrl.Result{}, nil
+	}
+
+	policy.Status = status
+	if err := r.Status().Update(ctx, policy); err != nil {
+		return kctrl.Result{}, fmt.Errorf("updating peering policy status: %w", err)
+	}
+
+	return kctrl.Result{}, nil
+}
+
+// ensurePeering creates or updates the peering generated by the policy and returns its name, it returns a conflict
+// instead if there is already a peering between the same VPCs not owned by the policy or if the peering is rejected by
+// the webhook, the name of the owned peering is still returned if only its update is rejected
+func (r *PeeringPolicyReconciler) ensurePeering(ctx context.Context, policy *gwapi.PeeringPolicy, peering *gwapi.Peering) (string, *gwapi.PeeringPolicyConflict, error) {
+	l := kctrllog.FromContext(ctx)
+
+	existing := &gwapi.PeeringList{}
+	if err := r.List(ctx, existing, kclient.InNamespace(peering.Namespace), kclient.MatchingFields{
+		gwapi.PeeringVPCPairField: peering.LabeledVPCPair(),
+	}); err != nil {
+		return "", nil, fmt.Errorf("listing peerings: %w", err)
+	}
+
+	for _, other := range existing.Items {
+		if !kmetav1.IsControlledBy(&other, policy) {
+			return "", &gwapi.PeeringPolicyConflict{
+				Peering: other.Name,
+				Message: "peering between the same VPCs not owned by the policy already exists",
+			}, nil
+		}
+	}
+
+	if len(existing.Items) == 0 {
+		l.Info("Creating Peering for the policy", "peering", peering.Name, "policy", policy.Name)
+
+		if err := r.Create(ctx, peering); err != nil {
+			if rejected(err) {
+				return "", &gwapi.PeeringPolicyConflict{
+					Peering: peering.Name,
+					Message: err.Error(),
+				}, nil
+			}
+
+			return "", nil, err //nolint:wrapcheck
+		}
+
+		return peering.Name, nil, nil
+	}
+
+	current := &existing.Items[0]
+	if equality.Semantic.DeepEqual(current.Spec, peering.Spec) && current.Labels[gwapi.LabelPeeringPolicy] == policy.Name {
+		return current.Name, nil, nil
+	}
+
+	l.Info("Updating Peering for the policy", "peering", current.Name, "policy", policy.Name)
+
+	current.Spec = peering.Spec
+	maps.Copy(current.Labels, peering.Labels)
+	if err := r.Update(ctx, current); err != nil {
+		if rejected(err) {
+			return current.Name, &gwapi.PeeringPolicyConflict{
+				Peering: current.Name,
+				Message: "update rejected, the previous spec is kept: " + err.Error(),
+			}, nil
+		}
+
+		return "", nil, err //nolint:wrapcheck
+	}
+
+	return current.Name, nil, nil
+}
+
+// rejected returns true if the webhook or the API server rejected the object, so retrying wouldn't help
+func rejected(err error) bool {
+	return kapierrors.IsInvalid(err) || kapierrors.IsForbidden(err) || kapierrors.IsAlreadyExists(err)
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPeeringPolicyReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, gwapi.AddToScheme(scheme))
+
+	selected := func(vpc *gwapi.VPCInfo) *gwapi.VPCInfo {
+		vpc.Labels = map[string]string{"tier": "app"}
+
+		return vpc
+	}
+	policy := &gwapi.PeeringPolicy{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "apps", UID: "apps-uid"},
+		Spec: gwapi.PeeringPolicySpec{
+			Selector:   kmetav1.LabelSelector{MatchLabels: map[string]string{"tier": "app"}},
+			Peer:       "hub",
+			Expose:     []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}},
+			PeerExpose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.0.0.0/24"}}}},
+		},
+	}
+	kube := kubetest.NewClient(scheme,
+		policy,
+		testVPC("hub", "10.0.0.0/24"),
+		selected(testVPC("vpc-1", "10.1.0.0/24")),
+		selected(testVPC("vpc-2", "10.2.0.0/24")),
+		testVPC("vpc-3", "10.3.0.0/24"),
+	)
+	kube.Reader.WithIndex(gwapi.PeeringVPCPairField, peeringVPCPairIndex)
+	r := &PeeringPolicyReconciler{Client: kube}
+
+	reconcile := func() (*gwapi.PeeringPolicy, map[string]gwapi.Peering) {
+		_, err := r.Reconcile(ctx, kctrl.Request{NamespacedName: kclient.ObjectKeyFromObject(policy)})
+		require.NoError(t, err)
+
+		current := &gwapi.PeeringPolicy{}
+		require.NoError(t, kube.Get(ctx, kclient.ObjectKeyFromObject(policy), current))
+		peerings := &gwapi.PeeringList{}
+		require.NoError(t, kube.List(ctx, peerings))
+		res := map[string]gwapi.Peering{}
+		for _, peering := range peerings.Items {
+			res[peering.Name] = peering
+		}
+
+		return current, res
+	}
+
+	// create
+	current, peerings := reconcile()
+	require.Equal(t, gwapi.PeeringPolicyStatus{Peerings: []string{"hub--vpc-1", "hub--vpc-2"}}, current.Status)
+	require.Len(t, peerings, 2)
+	require.Equal(t, "subnet-1", peerings["hub--vpc-1"].Spec.Peering["vpc-1"].Expose[0].IPs[0].VPCSubnet)
+
+	// update
+	current.Spec.Expose = []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.1.0.0/25"}}}}
+	require.NoError(t, kube.Update(ctx, current))
+	current, peerings = reconcile()
+	require.Equal(t, gwapi.PeeringPolicyStatus{Peerings: []string{"hub--vpc-1", "hub--vpc-2"}}, current.Status)
+	require.Equal(t, "10.1.0.0/25", peerings["hub--vpc-1"].Spec.Peering["vpc-1"].Expose[0].IPs[0].CIDR)
+
+	// rejected update of one of the peerings keeps it with the previous spec
+	kube.Reject = func(obj kclient.Object) error {
+		if obj.GetName() != "hub--vpc-2" {
+			return nil
+		}
+
+		return kapierrors.NewInvalid(schema.GroupKind{Group: gwapi.GroupVersion.Group, Kind: "Peering"}, obj.GetName(), field.ErrorList{
+			field.Invalid(field.NewPath("spec"), nil, "rejected"),
+		})
+	}
+	current.Spec.Expose = []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.0.0.0/8"}}}}
+	require.NoError(t, kube.Update(ctx, current))
+	current, peerings = reconcile()
+	require.Equal(t, []string{"hub--vpc-1", "hub--vpc-2"}, current.Status.Peerings)
+	require.Len(t, current.Status.Conflicts, 1)
+	require.Equal(t, "vpc-2", current.Status.Conflicts[0].VPC)
+	require.Equal(t, "hub--vpc-2", current.Status.Conflicts[0].Peering)
+	require.Contains(t, current.Status.Conflicts[0].Message, "update rejected, the previous spec is kept")
+	require.Equal(t, "10.0.0.0/8", peerings["hub--vpc-1"].Spec.Peering["vpc-1"].Expose[0].IPs[0].CIDR)
+	require.Equal(t, "10.1.0.0/25", peerings["hub--vpc-2"].Spec.Peering["vpc-2"].Expose[0].IPs[0].CIDR)
+	kube.Reject = nil
+
+	// cleanup of the peering of the VPC not selected anymore
+	vpc := &gwapi.VPCInfo{}
+	require.NoError(t, kube.Get(ctx, kclient.ObjectKey{Namespace: "default", Name: "vpc-2"}, vpc))
+	vpc.Labels = nil
+	require.NoError(t, kube.Update(ctx, vpc))
+	current, peerings = reconcile()
+	require.Equal(t, gwapi.PeeringPolicyStatus{Peerings: []string{"hub--vpc-1"}}, current.Status)
+	require.Len(t, peerings, 1)
+	require.Contains(t, peerings, "hub--vpc-1")
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-peeringpolicy,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=peeringpolicies,verbs=create;update;delete,versions=v1alpha1,name=mpeeringpolicy.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gateway-githedgehog-com-v1alpha1-peeringpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=peeringpolicies,verbs=create;update;delete,versions=v1alpha1,name=vpeeringpolicy.kb.io,admissionReviewVersions=v1
+
+type PeeringPolicyWebhook struct {
+	kclient.Reader
+}
+
+func SetupPeeringPolicyWebhookWith(mgr kctrl.Manager) error {
+	w := &PeeringPolicyWebhook{
+		Reader: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewWebhookManagedBy(mgr).
+		For(&gwapi.PeeringPolicy{}).
+		WithDefaulter(FromTypedDefaulter(w)).
+		WithValidator(FromTypedValidator(w)).
+		Complete(); err != nil {
+		return fmt.Errorf("creating webhook: %w", err) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (w *PeeringPolicyWebhook) Default(_ context.Context, obj *gwapi.PeeringPolicy) error {
+	obj.Default()
+
+	return nil
+}
+
+func (w *PeeringPolicyWebhook) ValidateCreate(ctx context.Context, obj *gwapi.PeeringPolicy) (admission.Warnings, error) {
+	return nil, obj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *PeeringPolicyWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.PeeringPolicy, newObj *gwapi.PeeringPolicy) (admission.Warnings, error) {
+	// TODO validate diff between oldObj and newObj if needed
+	_ = oldObj
+
+	return nil, newObj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *PeeringPolicyWebhook) ValidateDelete(_ context.Context, _ *gwapi.PeeringPolicy) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,351 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringrequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringrequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringacceptances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringacceptances/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringmeshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
+
+type PeeringRequestReconciler struct {
+	kclient.Client
+}
+
+func SetupPeeringRequestReconcilerWith(mgr kctrl.Manager) error {
+	r := &PeeringRequestReconciler{
+		Client: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("PeeringRequest").
+		For(&gwapi.PeeringRequest{}).
+		Watches(&gwapi.PeeringAcceptance{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAcceptedRequest)).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRequests)).
+		Watches(&gwapi.PeeringMesh{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRequests)).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRequests)).
+		Watches(&gwapi.External{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRequests)).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PeeringRequestReconciler) enqueueAcceptedRequest(_ context.Context, obj kclient.Object) []reconcile.Request {
+	acc, ok := obj.(*gwapi.PeeringAcceptance)
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: ktypes.NamespacedName{
+		Namespace: acc.Spec.RequestNamespace,
+		Name:      acc.Spec.Request,
+	}}}
+}
+
+func (r *PeeringRequestReconciler) enqueueAllRequests(ctx context.Context, _ kclient.Object) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	reqs := &gwapi.PeeringRequestList{}
+	if err := r.List(ctx, reqs); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing peering requests to reconcile all")
+
+		return nil
+	}
+
+	for _, req := range reqs.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: req.Namespace,
+			Name:      req.Name,
+		}})
+	}
+
+	return res
+}
+
+// Reconcile updates the status of all requests and their acceptances as an accepted request could conflict with the
+// other requests peering the same VPCs
+func (r *PeeringRequestReconciler) Reconcile(ctx context.Context, _ kctrl.Request) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	reqs := &gwapi.PeeringRequestList{}
+	if err := r.List(ctx, reqs); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peering requests: %w", err)
+	}
+
+	accs := &gwapi.PeeringAcceptanceList{}
+	if err := r.List(ctx, accs); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peering acceptances: %w", err)
+	}
+
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peerings: %w", err)
+	}
+
+	meshes := &gwapi.PeeringMeshList{}
+	if err := r.List(ctx, meshes); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peering meshes: %w", err)
+	}
+
+	peered := []ktypes.NamespacedName{}
+	for _, peerReq := range reqs.Items {
+		peered = append(peered,
+			ktypes.NamespacedName{Namespace: peerReq.Namespace, Name: peerReq.Spec.VPC},
+			ktypes.NamespacedName{Namespace: peerReq.Spec.RemoteNamespace, Name: peerReq.Spec.RemoteVPC},
+		)
+	}
+	for _, mesh := range meshes.Items {
+		for vpcName := range mesh.Spec.Peering {
+			peered = append(peered, ktypes.NamespacedName{Namespace: mesh.Namespace, Name: vpcName})
+		}
+	}
+
+	exists, externals := map[ktypes.NamespacedName]bool{}, map[ktypes.NamespacedName]bool{}
+	for _, vpc := range peered {
+		if _, checked := exists[vpc]; checked {
+			continue
+		}
+
+		_, isExternal, err := gwapi.GetPeeredSubnets(ctx, r, vpc.Namespace, vpc.Name)
+		if err != nil && !kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, fmt.Errorf("getting peered %s: %w", vpc, err)
+		}
+		exists[vpc] = err == nil
+		externals[vpc] = isExternal
+	}
+	existsFunc := func(namespace, name string) bool {
+		return exists[ktypes.NamespacedName{Namespace: namespace, Name: name}]
+	}
+	isExternalFunc := func(namespace, name string) bool {
+		return externals[ktypes.NamespacedName{Namespace: namespace, Name: name}]
+	}
+
+	// reconcile again at the next window boundary of the peerings as only the active ones conflict with the requests
+	now := time.Now()
+	res := kctrl.Result{}
+	for _, peering := range peerings.Items {
+		if _, next := peering.Spec.WindowState(now); !next.IsZero() && (res.RequeueAfter == 0 || next.Sub(now) < res.RequeueAfter) {
+			res.RequeueAfter = next.Sub(now)
+		}
+	}
+
+	meshPeerings, _ := expandMeshes(meshes.Items, peerings.Items, now, existsFunc, isExternalFunc)
+	_, statuses := expandPeeringRequests(ctx, r, reqs.Items, accs.Items, peeredPairs(peerings.Items, meshPeerings, now), existsFunc, isExternalFunc)
+
+	for _, peerReq := range reqs.Items {
+		if peerReq.DeletionTimestamp != nil {
+			continue
+		}
+
+		status := statuses[ktypes.NamespacedName{Namespace: peerReq.Namespace, Name: peerReq.Name}]
+		if !equality.Semantic.DeepEqual(peerReq.Status, status) {
+			l.Info("Updating PeeringRequest status", "name", peerReq.Name, "namespace", peerReq.Namespace, "state", status.State)
+
+			peerReq.Status = status
+			if err := r.Status().Update(ctx, &peerReq); err != nil {
+				return kctrl.Result{}, fmt.Errorf("updating peering request %s status: %w", peerReq.Name, err)
+			}
+		}
+
+		if status.Acceptance == "" {
+			continue
+		}
+
+		for _, acc := range accs.Items {
+			if acc.Namespace != peerReq.Spec.RemoteNamespace || acc.Name != status.Acceptance {
+				continue
+			}
+
+			accStatus := gwapi.PeeringAcceptanceStatus{
+				State:   status.State,
+				Message: status.Message,
+			}
+			if !equality.Semantic.DeepEqual(acc.Status, accStatus) {
+				acc.Status = accStatus
+				if err := r.Status().Update(ctx, &acc); err != nil {
+					return kctrl.Result{}, fmt.Errorf("updating peering acceptance %s status: %w", acc.Name, err)
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// peeredPairs returns the VPC pairs (as the canonical peering names) peered by the peerings active at now and by the
+// expanded meshes with what peers them
+func peeredPairs(peerings []gwapi.Peering, meshPeerings map[string]gwapi.PeeringSpec, now time.Time) map[string]string {
+	res := map[string]string{}
+	for _, peering := range peerings {
+		if state, _ := peering.Spec.WindowState(now); state != gwapi.PeeringWindowStateActive {
+			continue
+		}
+
+		vpcs := slices.Sorted(maps.Keys(peering.Spec.Peering))
+		if len(vpcs) != 2 {
+			continue
+		}
+		res[gwapi.PeeringName(vpcs[0], vpcs[1])] = "peering " + peering.Namespace + "/" + peering.Name
+	}
+
+	for key, spec := range meshPeerings {
+		vpcs := slices.Sorted(maps.Keys(spec.Peering))
+		if len(vpcs) != 2 {
+			continue
+		}
+		// the meshes are keyed by "<namespace>..mesh..<mesh>..<pair>"
+		parts := strings.Split(key, agentNameSep)
+		res[gwapi.PeeringName(vpcs[0], vpcs[1])] = "mesh " + parts[0] + "/" + parts[2]
+	}
+
+	return res
+}
+
+// expandPeeringRequests returns the peerings of the accepted requests keyed by their agent names (see
+// requestPeeringName) and the status of each request, accepted requests for the VPCs already peered (see peeredPairs) or
+// peered by another request (first by namespace and name wins) are reported as conflicts and the ones with invalid
+// acceptance exposes or the dataplane can't configure as invalid, neither of them is returned
+func expandPeeringRequests(ctx context.Context, kube kclient.Reader, reqs []gwapi.PeeringRequest, accs []gwapi.PeeringAcceptance, peered map[string]string, exists, isExternal func(namespace, name string) bool) (map[string]gwapi.PeeringSpec, map[ktypes.NamespacedName]gwapi.PeeringRequestStatus) {
+	peered = maps.Clone(peered)
+
+	slices.SortFunc(reqs, func(a, b gwapi.PeeringRequest) int {
+		if a.Namespace != b.Namespace {
+			return cmp.Compare(a.Namespace, b.Namespace)
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	specs := map[string]gwapi.PeeringSpec{}
+	statuses := map[ktypes.NamespacedName]gwapi.PeeringRequestStatus{}
+	for idx := range reqs {
+		peerReq := &reqs[idx]
+		key := ktypes.NamespacedName{Namespace: peerReq.Namespace, Name: peerReq.Name}
+
+		status, acc := peeringRequestStatus(peerReq, accs, exists)
+		if status.State != gwapi.PeeringRequestStateAccepted {
+			statuses[key] = status
+
+			continue
+		}
+
+		// the acceptance could be created before the request, so its exposes are only checked once they're paired
+		if err := acc.ValidateExposes(ctx, kube, peerReq); err != nil {
+			statuses[key] = gwapi.PeeringRequestStatus{
+				State:      gwapi.PeeringRequestStateInvalid,
+				Message:    fmt.Sprintf("peering acceptance %s/%s: %s", acc.Namespace, acc.Name, err),
+				Acceptance: status.Acceptance,
+			}
+
+			continue
+		}
+
+		pair := gwapi.PeeringName(peerReq.Spec.VPC, peerReq.Spec.RemoteVPC)
+		if by, exists := peered[pair]; exists {
+			statuses[key] = gwapi.PeeringRequestStatus{
+				State:      gwapi.PeeringRequestStateConflict,
+				Message:    "already peered by " + by,
+				Acceptance: status.Acceptance,
+			}
+
+			continue
+		}
+		peered[pair] = "request " + peerReq.Namespace + "/" + peerReq.Name
+
+		spec := gwapi.PeeringSpec{
+			Peering: map[string]*gwapi.PeeringEntry{
+				peerReq.Spec.VPC:       {Expose: peerReq.Spec.Expose},
+				peerReq.Spec.RemoteVPC: {Expose: acc.Spec.Expose},
+			},
+		}
+		if err := spec.DataplaneUnsupported(func(name string) bool {
+			if name == peerReq.Spec.VPC {
+				return isExternal(peerReq.Namespace, name)
+			}
+
+			return isExternal(peerReq.Spec.RemoteNamespace, name)
+		}); err != nil {
+			statuses[key] = gwapi.PeeringRequestStatus{
+				State:      gwapi.PeeringRequestStateInvalid,
+				Message:    err.Error(),
+				Acceptance: status.Acceptance,
+			}
+
+			continue
+		}
+
+		statuses[key] = status
+		specs[requestPeeringName(peerReq.Namespace, peerReq.Name, peerReq.Spec.RemoteNamespace)] = spec
+	}
+
+	return specs, statuses
+}
+
+// peeringRequestStatus returns the state of the cross-namespace peering based on the acceptances from the remote
+// namespace (first by name wins) and on whether both VPCs exist in their namespaces, the matched acceptance is
+// returned if there is one
+func peeringRequestStatus(req *gwapi.PeeringRequest, accs []gwapi.PeeringAcceptance, exists func(namespace, name string) bool) (gwapi.PeeringRequestStatus, *gwapi.PeeringAcceptance) {
+	var acc *gwapi.PeeringAcceptance
+	for idx := range accs {
+		if accs[idx].Accepts(req) && (acc == nil || accs[idx].Name < acc.Name) {
+			acc = &accs[idx]
+		}
+	}
+
+	switch {
+	case acc == nil:
+		return gwapi.PeeringRequestStatus{
+			State:   gwapi.PeeringRequestStatePending,
+			Message: "waiting for acceptance in namespace " + req.Spec.RemoteNamespace,
+		}, nil
+	case acc.Spec.Reject:
+		return gwapi.PeeringRequestStatus{
+			State:      gwapi.PeeringRequestStateRejected,
+			Message:    "rejected by acceptance " + acc.Name,
+			Acceptance: acc.Name,
+		}, acc
+	case !exists(req.Namespace, req.Spec.VPC):
+		return gwapi.PeeringRequestStatus{
+			State:      gwapi.PeeringRequestStatePending,
+			Message:    fmt.Sprintf("vpc %s not found in namespace %s", req.Spec.VPC, req.Namespace),
+			Acceptance: acc.Name,
+		}, acc
+	case !exists(req.Spec.RemoteNamespace, req.Spec.RemoteVPC):
+		return gwapi.PeeringRequestStatus{
+			State:      gwapi.PeeringRequestStatePending,
+			Message:    fmt.Sprintf("vpc or external %s not found in namespace %s", req.Spec.RemoteVPC, req.Spec.RemoteNamespace),
+			Acceptance: acc.Name,
+		}, acc
+	}
+
+	return gwapi.PeeringRequestStatus{
+		State:      gwapi.PeeringRequestStateAccepted,
+		Acceptance: acc.Name,
+	}, acc
+}
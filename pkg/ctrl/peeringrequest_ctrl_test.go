@@ -0,0 +1,150 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestPeeringRequestStatus(t *testing.T) {
+	req := &gwapi.PeeringRequest{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-a", Name: "to-b"},
+		Spec:       gwapi.PeeringRequestSpec{VPC: "vpc-a", RemoteNamespace: "tenant-b", RemoteVPC: "vpc-b"},
+	}
+	acc := func(ns, name string, reject bool) gwapi.PeeringAcceptance {
+		return gwapi.PeeringAcceptance{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       gwapi.PeeringAcceptanceSpec{RequestNamespace: "tenant-a", Request: "to-b", Reject: reject},
+		}
+	}
+	all := func(_, _ string) bool { return true }
+
+	for _, tt := range []struct {
+		name   string
+		accs   []gwapi.PeeringAcceptance
+		exists func(namespace, name string) bool
+		state  gwapi.PeeringRequestState
+		acc    string
+	}{
+		{"no-acceptance", nil, all, gwapi.PeeringRequestStatePending, ""},
+		{"wrong-namespace", []gwapi.PeeringAcceptance{acc("tenant-c", "from-a", false)}, all, gwapi.PeeringRequestStatePending, ""},
+		{"accepted", []gwapi.PeeringAcceptance{acc("tenant-b", "from-a", false)}, all, gwapi.PeeringRequestStateAccepted, "from-a"},
+		{"rejected", []gwapi.PeeringAcceptance{acc("tenant-b", "from-a", true)}, all, gwapi.PeeringRequestStateRejected, "from-a"},
+		{"first-wins", []gwapi.PeeringAcceptance{acc("tenant-b", "z", true), acc("tenant-b", "a", false)}, all, gwapi.PeeringRequestStateAccepted, "a"},
+		{"remote-vpc-elsewhere", []gwapi.PeeringAcceptance{acc("tenant-b", "from-a", false)}, func(namespace, _ string) bool {
+			return namespace == "tenant-a"
+		}, gwapi.PeeringRequestStatePending, "from-a"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status, matched := peeringRequestStatus(req, tt.accs, tt.exists)
+			require.Equal(t, tt.state, status.State)
+			require.Equal(t, tt.acc, status.Acceptance)
+			if tt.acc == "" {
+				require.Nil(t, matched)
+			} else {
+				require.Equal(t, tt.acc, matched.Name)
+			}
+		})
+	}
+}
+
+func TestExpandPeeringRequests(t *testing.T) {
+	now := time.Now()
+	expose := []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "default"}}}}
+	request := func(ns, name, vpc, remoteNS, remoteVPC string) gwapi.PeeringRequest {
+		return gwapi.PeeringRequest{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       gwapi.PeeringRequestSpec{VPC: vpc, RemoteNamespace: remoteNS, RemoteVPC: remoteVPC, Expose: expose},
+		}
+	}
+	accept := func(ns, reqNS, req string) gwapi.PeeringAcceptance {
+		return gwapi.PeeringAcceptance{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: ns, Name: "from-" + reqNS},
+			Spec:       gwapi.PeeringAcceptanceSpec{RequestNamespace: reqNS, Request: req, Expose: expose},
+		}
+	}
+
+	reqs := []gwapi.PeeringRequest{
+		request("tenant-b", "to-a", "vpc-b", "tenant-a", "vpc-a"),
+		request("tenant-a", "to-b", "vpc-a", "tenant-b", "vpc-b"),
+		request("tenant-a", "to-c", "vpc-a", "tenant-c", "vpc-c"),
+		request("tenant-a", "to-d", "vpc-a", "tenant-d", "vpc-d"),
+		request("tenant-a", "to-e", "vpc-a", "tenant-e", "vpc-e"),
+		request("tenant-a", "to-f", "vpc-a", "tenant-f", "vpc-f"),
+	}
+	accs := []gwapi.PeeringAcceptance{
+		accept("tenant-a", "tenant-b", "to-a"),
+		accept("tenant-b", "tenant-a", "to-b"),
+		accept("tenant-c", "tenant-a", "to-c"),
+		accept("tenant-d", "tenant-a", "to-d"),
+		accept("tenant-e", "tenant-a", "to-e"),
+		accept("tenant-f", "tenant-a", "to-f"),
+	}
+	// the request could be created before its exposes were rejected by the webhook
+	reqs[4].Spec.Expose = []gwapi.PeeringEntryExpose{{
+		IPs:         []gwapi.PeeringEntryIP{{CIDR: "10.0.5.0/24"}},
+		Translation: &gwapi.PeeringEntryTranslation{Mode: gwapi.PeeringTranslationModeNAT64},
+	}}
+	peerings := []gwapi.Peering{
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-a", Name: "manual"},
+			Spec:       gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{"vpc-a": {}, "vpc-c": {}}},
+		},
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-a", Name: "expired"},
+			Spec: gwapi.PeeringSpec{
+				Peering:   map[string]*gwapi.PeeringEntry{"vpc-a": {}, "vpc-d": {}},
+				ExpiresAt: &kmetav1.Time{Time: now.Add(-time.Hour)},
+			},
+		},
+	}
+	meshPeerings := map[string]gwapi.PeeringSpec{
+		"tenant-e..mesh..mesh-1..vpc-e1--vpc-e2": {Peering: map[string]*gwapi.PeeringEntry{"vpc-e1": {}, "vpc-e2": {}}},
+	}
+
+	peered := peeredPairs(peerings, meshPeerings, now)
+	require.Equal(t, map[string]string{
+		"vpc-a--vpc-c":   "peering tenant-a/manual",
+		"vpc-e1--vpc-e2": "mesh tenant-e/mesh-1",
+	}, peered)
+
+	// the acceptance exposes a subnet vpc-f doesn't have
+	vpcF := testVPC("vpc-f", "10.0.6.0/24")
+	vpcF.Namespace = "tenant-f"
+	kube := kubetest.NewReader(vpcF)
+
+	specs, statuses := expandPeeringRequests(context.Background(), kube, reqs, accs, peered, func(_, _ string) bool { return true }, func(_, _ string) bool { return false })
+	require.Len(t, specs, 2)
+	require.Contains(t, specs, "tenant-a..request..to-b..tenant-b")
+	require.Contains(t, specs, "tenant-a..request..to-d..tenant-d")
+
+	require.Equal(t, map[ktypes.NamespacedName]gwapi.PeeringRequestStatus{
+		{Namespace: "tenant-a", Name: "to-b"}: {State: gwapi.PeeringRequestStateAccepted, Acceptance: "from-tenant-a"},
+		{Namespace: "tenant-a", Name: "to-c"}: {
+			State: gwapi.PeeringRequestStateConflict, Message: "already peered by peering tenant-a/manual", Acceptance: "from-tenant-a",
+		},
+		{Namespace: "tenant-a", Name: "to-d"}: {State: gwapi.PeeringRequestStateAccepted, Acceptance: "from-tenant-a"},
+		{Namespace: "tenant-a", Name: "to-e"}: {
+			State:      gwapi.PeeringRequestStateInvalid,
+			Message:    "vpc vpc-a expose 0: nat64/nat46 translation isn't supported by the dataplane yet",
+			Acceptance: "from-tenant-a",
+		},
+		{Namespace: "tenant-a", Name: "to-f"}: {
+			State:      gwapi.PeeringRequestStateInvalid,
+			Message:    "peering acceptance tenant-f/from-tenant-a: vpc vpc-f expose 0: unknown vpc subnet default",
+			Acceptance: "from-tenant-a",
+		},
+		{Namespace: "tenant-b", Name: "to-a"}: {
+			State: gwapi.PeeringRequestStateConflict, Message: "already peered by request tenant-a/to-b", Acceptance: "from-tenant-b",
+		},
+	}, statuses)
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=tenantquotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=tenantquotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringmeshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringrequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peeringacceptances,verbs=get;list;watch
+
+type TenantQuotaReconciler struct {
+	kclient.Client
+}
+
+func SetupTenantQuotaReconcilerWith(mgr kctrl.Manager) error {
+	r := &TenantQuotaReconciler{
+		Client: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("TenantQuota").
+		For(&gwapi.TenantQuota{}).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueueQuotasInNamespace)).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueQuotasInNamespace)).
+		Watches(&gwapi.PeeringMesh{}, handler.EnqueueRequestsFromMapFunc(r.enqueueQuotasInNamespace)).
+		Watches(&gwapi.PeeringRequest{}, handler.EnqueueRequestsFromMapFunc(r.enqueueQuotasInNamespace)).
+		Watches(&gwapi.PeeringAcceptance{}, handler.EnqueueRequestsFromMapFunc(r.enqueueQuotasInNamespace)).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TenantQuotaReconciler) enqueueQuotasInNamespace(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	quotas := &gwapi.TenantQuotaList{}
+	if err := r.List(ctx, quotas, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing tenant quotas to reconcile")
+
+		return nil
+	}
+
+	for _, quota := range quotas.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: quota.Namespace,
+			Name:      quota.Name,
+		}})
+	}
+
+	return res
+}
+
+func (r *TenantQuotaReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	quota := &gwapi.TenantQuota{}
+	if err := r.Get(ctx, req.NamespacedName, quota); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("getting tenant quota: %w", err)
+	}
+
+	if quota.DeletionTimestamp != nil {
+		return kctrl.Result{}, nil
+	}
+
+	usage, err := quota.Usage(ctx, r, nil)
+	if err != nil {
+		return kctrl.Result{}, fmt.Errorf("computing usage: %w", err)
+	}
+
+	if quota.Status.Usage == usage {
+		return kctrl.Result{}, nil
+	}
+
+	quota.Status.Usage = usage
+	if err := r.Status().Update(ctx, quota); err != nil {
+		return kctrl.Result{}, fmt.Errorf("updating tenant quota status: %w", err)
+	}
+
+	return kctrl.Result{}, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-tenantquota,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=tenantquotas,verbs=create;update;delete,versions=v1alpha1,name=mtenantquota.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gateway-githedgehog-com-v1alpha1-tenantquota,mutating=false,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=tenantquotas,verbs=create;update;delete,versions=v1alpha1,name=vtenantquota.kb.io,admissionReviewVersions=v1
+
+type TenantQuotaWebhook struct {
+	kclient.Reader
+}
+
+func SetupTenantQuotaWebhookWith(mgr kctrl.Manager) error {
+	w := &TenantQuotaWebhook{
+		Reader: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewWebhookManagedBy(mgr).
+		For(&gwapi.TenantQuota{}).
+		WithDefaulter(FromTypedDefaulter(w)).
+		WithValidator(FromTypedValidator(w)).
+		Complete(); err != nil {
+		return fmt.Errorf("creating webhook: %w", err) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (w *TenantQuotaWebhook) Default(_ context.Context, obj *gwapi.TenantQuota) error {
+	obj.Default()
+
+	return nil
+}
+
+func (w *TenantQuotaWebhook) ValidateCreate(ctx context.Context, obj *gwapi.TenantQuota) (admission.Warnings, error) {
+	return nil, obj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *TenantQuotaWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.TenantQuota, newObj *gwapi.TenantQuota) (admission.Warnings, error) {
+	// TODO validate diff between oldObj and newObj if needed
+	_ = oldObj
+
+	return nil, newObj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *TenantQuotaWebhook) ValidateDelete(_ context.Context, _ *gwapi.TenantQuota) (admission.Warnings, error) {
+	return nil, nil
+}
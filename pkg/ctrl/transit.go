@@ -0,0 +1,340 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// transitEdge is a peering (or a mesh pair or an accepted request) with the prefixes each of its sides exposes to the
+// other one
+type transitEdge struct {
+	peering string
+	exposed map[string]*prefixset.Set
+	transit map[string]bool
+}
+
+// transitPath is the routes re-exposed through a path of transit VPCs starting from the one exposing them, peerings
+// is the list of the peerings the routes are learned through
+type transitPath struct {
+	path     []string
+	peerings []string
+	routes   *prefixset.Set
+}
+
+// transitEdges returns the edges for all peerings, mesh pairs and accepted requests of the namespace, if override is
+// set it replaces the peering of the same name (or is added if there is none) to check the result of creating or
+// updating it. The mesh pairs are named "<mesh>/<pair>" and the requests "<namespace>/<request>@<remote namespace>".
+func transitEdges(ctx context.Context, kube kclient.Reader, namespace string, override *gwapi.Peering) ([]transitEdge, error) {
+	peerings := &gwapi.PeeringList{}
+	if err := kube.List(ctx, peerings, kclient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("listing peerings: %w", err)
+	}
+	meshes := &gwapi.PeeringMeshList{}
+	if err := kube.List(ctx, meshes, kclient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("listing peering meshes: %w", err)
+	}
+	reqs := &gwapi.PeeringRequestList{}
+	if err := kube.List(ctx, reqs); err != nil {
+		return nil, fmt.Errorf("listing peering requests: %w", err)
+	}
+	accs := &gwapi.PeeringAcceptanceList{}
+	if err := kube.List(ctx, accs); err != nil {
+		return nil, fmt.Errorf("listing peering acceptances: %w", err)
+	}
+
+	items := peerings.Items
+	if override != nil {
+		items = slices.DeleteFunc(items, func(p gwapi.Peering) bool { return p.Name == override.Name })
+		items = append(items, *override)
+	}
+
+	// the missing VPCs are skipped below, so everything is expanded as if they exist
+	now := time.Now()
+	exists := func(_, _ string) bool { return true }
+	isExternal := func(_, _ string) bool { return false }
+	meshPeerings, _ := expandMeshes(meshes.Items, items, now, exists, isExternal)
+	reqPeerings, _ := expandPeeringRequests(ctx, kube, reqs.Items, accs.Items, peeredPairs(items, meshPeerings, now), exists, isExternal)
+
+	type peered struct {
+		subnets    map[string][]string
+		isExternal bool
+		found      bool
+	}
+	cache := map[string]peered{}
+
+	edges := []transitEdge{}
+
+	// namespaces is the namespace of each VPC of the peering, only the requests peer VPCs of different namespaces
+	addEdge := func(name string, spec *gwapi.PeeringSpec, namespaces map[string]string) error {
+		edge := transitEdge{
+			peering: name,
+			exposed: map[string]*prefixset.Set{},
+			transit: map[string]bool{},
+		}
+
+		for vpcName, entry := range spec.Peering {
+			edge.exposed[vpcName] = &prefixset.Set{}
+			if entry == nil {
+				continue
+			}
+			edge.transit[vpcName] = entry.Transit
+
+			vpcNamespace := namespaces[vpcName]
+			vpc, ok := cache[vpcNamespace+"/"+vpcName]
+			if !ok {
+				subnets, isExternal, err := gwapi.GetPeeredSubnets(ctx, kube, vpcNamespace, vpcName)
+				if err != nil && !kapierrors.IsNotFound(err) {
+					return err //nolint:wrapcheck
+				}
+				vpc = peered{subnets: subnets, isExternal: isExternal, found: err == nil}
+				cache[vpcNamespace+"/"+vpcName] = vpc
+			}
+			if !vpc.found {
+				continue
+			}
+
+			// invalid exposes are rejected by the validation anyway
+			for _, expose := range entry.Expose {
+				if vpc.isExternal {
+					if routes, err := expose.ExternalRoutes(); err == nil {
+						edge.exposed[vpcName] = edge.exposed[vpcName].Union(routes)
+					}
+
+					continue
+				}
+
+				if expose.ASPool != nil {
+					continue
+				}
+
+				ips, as, err := expose.Sets(vpc.subnets)
+				if err != nil {
+					continue
+				}
+				if as.IsEmpty() {
+					edge.exposed[vpcName] = edge.exposed[vpcName].Union(ips)
+				} else {
+					edge.exposed[vpcName] = edge.exposed[vpcName].Union(as)
+				}
+			}
+		}
+
+		edges = append(edges, edge)
+
+		return nil
+	}
+
+	for _, peering := range items {
+		// the exposes waiting for the NAT pool allocation are skipped below
+		if resolved, _, pending, err := resolveASPools(ctx, kube, &peering); err != nil {
+			return nil, err
+		} else if pending == "" {
+			peering = *resolved
+		}
+
+		namespaces := map[string]string{}
+		for vpcName := range peering.Spec.Peering {
+			namespaces[vpcName] = namespace
+		}
+		if err := addEdge(peering.Name, &peering.Spec, namespaces); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, spec := range meshPeerings {
+		namespaces := map[string]string{}
+		for vpcName := range spec.Peering {
+			namespaces[vpcName] = namespace
+		}
+		parts := strings.Split(key, agentNameSep)
+		if err := addEdge(parts[2]+"/"+parts[3], &spec, namespaces); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, req := range reqs.Items {
+		spec, ok := reqPeerings[requestPeeringName(req.Namespace, req.Name, req.Spec.RemoteNamespace)]
+		if !ok || req.Namespace != namespace && req.Spec.RemoteNamespace != namespace {
+			continue
+		}
+
+		namespaces := map[string]string{
+			req.Spec.VPC:       req.Namespace,
+			req.Spec.RemoteVPC: req.Spec.RemoteNamespace,
+		}
+		if err := addEdge(req.Namespace+"/"+req.Name+"@"+req.Spec.RemoteNamespace, &spec, namespaces); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(edges, func(a, b transitEdge) int { return strings.Compare(a.peering, b.peering) })
+
+	return edges, nil
+}
+
+// computeTransit returns the routes re-exposed by the transit VPCs keyed by the peering and the VPC on the other side
+// receiving them. It fails if the routes of a VPC could be re-exposed back to it (loop) or if a VPC would receive
+// overlapping routes through the different paths, as the (NATed) addresses would be ambiguous. If scope is set, only
+// the loops and the ambiguous routes involving that peering fail, the others are skipped.
+func computeTransit(edges []transitEdge, scope string) (map[string]map[string][]transitPath, error) {
+	res := map[string]map[string][]transitPath{}
+	received := map[string][]transitPath{}
+
+	for _, edge := range edges {
+		for _, target := range slices.Sorted(maps.Keys(edge.exposed)) {
+			received[target] = append(received[target], transitPath{
+				path:     []string{otherSide(edge, target)},
+				peerings: []string{edge.peering},
+				routes:   edge.exposed[otherSide(edge, target)],
+			})
+		}
+	}
+
+	for _, edge := range edges {
+		for _, hub := range slices.Sorted(maps.Keys(edge.transit)) {
+			if !edge.transit[hub] {
+				continue
+			}
+
+			target := otherSide(edge, hub)
+			paths, err := transitLearned(edges, hub, []string{edge.peering}, []string{target, hub}, scope)
+			if err != nil {
+				return nil, err
+			}
+			if len(paths) == 0 {
+				continue
+			}
+
+			if res[edge.peering] == nil {
+				res[edge.peering] = map[string][]transitPath{}
+			}
+			res[edge.peering][target] = paths
+			received[target] = append(received[target], paths...)
+		}
+	}
+
+	for _, target := range slices.Sorted(maps.Keys(received)) {
+		paths := received[target]
+		for i, path := range paths {
+			for _, other := range paths[i+1:] {
+				// overlaps between the direct peerings are only ambiguous if the transit is involved, otherwise the
+				// metrics are used to pick the route
+				if len(path.path) == 1 && len(other.path) == 1 {
+					continue
+				}
+				if scope != "" && !slices.Contains(path.peerings, scope) && !slices.Contains(other.peerings, scope) {
+					continue
+				}
+
+				if overlap := path.routes.Intersect(other.routes); !overlap.IsEmpty() {
+					return nil, fmt.Errorf("ambiguous routes %s in vpc %s learned from %s and %s", //nolint:goerr113
+						overlap, target, strings.Join(path.path, " -> "), strings.Join(other.path, " -> "))
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// transitLearned returns the routes the hub learns from its peerings other than the ones it re-exposes them through,
+// via is the list of peerings and visited is the list of VPCs on the path starting from the one receiving the routes
+func transitLearned(edges []transitEdge, hub string, via, visited []string, scope string) ([]transitPath, error) {
+	res := []transitPath{}
+
+	for _, edge := range edges {
+		if edge.peering == via[len(via)-1] {
+			continue
+		}
+		if _, ok := edge.exposed[hub]; !ok {
+			continue
+		}
+
+		origin := otherSide(edge, hub)
+		if slices.Contains(visited, origin) {
+			if scope != "" && edge.peering != scope && !slices.Contains(via, scope) {
+				continue
+			}
+
+			loop := append(slices.Clone(visited), origin)
+			slices.Reverse(loop)
+
+			return nil, fmt.Errorf("transit loop %s", strings.Join(loop, " -> ")) //nolint:goerr113
+		}
+
+		if routes := edge.exposed[origin]; !routes.IsEmpty() {
+			res = append(res, transitPath{
+				path:     []string{origin, hub},
+				peerings: append([]string{edge.peering}, via...),
+				routes:   routes,
+			})
+		}
+
+		if !edge.transit[origin] {
+			continue
+		}
+
+		paths, err := transitLearned(edges, origin, append(slices.Clone(via), edge.peering), append(slices.Clone(visited), origin), scope)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			res = append(res, transitPath{path: append(path.path, hub), peerings: path.peerings, routes: path.routes})
+		}
+	}
+
+	return res, nil
+}
+
+func otherSide(edge transitEdge, vpcName string) string {
+	for name := range edge.exposed {
+		if name != vpcName {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// withTransitRoutes returns the peering spec with the routes re-exposed by the transit VPCs (keyed by the VPC on the
+// other side receiving them, see computeTransit) added as the exposes of the transit VPCs, so the dataplane gets them
+// as the plain prefixes reachable through the transit VPC
+func withTransitRoutes(spec *gwapi.PeeringSpec, paths map[string][]transitPath) *gwapi.PeeringSpec {
+	res := spec.DeepCopy()
+
+	for _, target := range slices.Sorted(maps.Keys(paths)) {
+		routes := &prefixset.Set{}
+		for _, path := range paths[target] {
+			routes = routes.Union(path.routes)
+		}
+		if routes.IsEmpty() {
+			continue
+		}
+
+		for hub, entry := range res.Peering {
+			if hub == target || entry == nil {
+				continue
+			}
+
+			expose := gwapi.PeeringEntryExpose{}
+			for _, prefix := range routes.Strings() {
+				expose.IPs = append(expose.IPs, gwapi.PeeringEntryIP{CIDR: prefix})
+			}
+			entry.Expose = append(entry.Expose, expose)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	"go.githedgehog.com/gateway/pkg/prefixset"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputeTransit(t *testing.T) {
+	edge := func(vpc1, cidr1 string, transit1 bool, vpc2, cidr2 string, transit2 bool) transitEdge {
+		return transitEdge{
+			peering: vpc1 + "--" + vpc2,
+			exposed: map[string]*prefixset.Set{vpc1: prefixset.MustParse(cidr1), vpc2: prefixset.MustParse(cidr2)},
+			transit: map[string]bool{vpc1: transit1, vpc2: transit2},
+		}
+	}
+
+	t.Run("hub-and-spoke", func(t *testing.T) {
+		res, err := computeTransit([]transitEdge{
+			edge("hub", "10.0.0.0/24", true, "spoke-1", "10.1.0.0/24", false),
+			edge("hub", "10.0.0.0/24", true, "spoke-2", "10.2.0.0/24", false),
+		}, "")
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		paths := res["hub--spoke-1"]["spoke-1"]
+		require.Len(t, paths, 1)
+		require.Equal(t, []string{"spoke-2", "hub"}, paths[0].path)
+		require.Equal(t, []string{"10.2.0.0/24"}, paths[0].routes.Strings())
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		res, err := computeTransit([]transitEdge{
+			edge("vpc-a", "10.1.0.0/24", false, "hub-1", "10.0.1.0/24", true),
+			edge("hub-1", "10.0.1.0/24", false, "hub-2", "10.0.2.0/24", true),
+			edge("hub-2", "10.0.2.0/24", false, "vpc-c", "10.3.0.0/24", false),
+		}, "")
+		require.NoError(t, err)
+
+		paths := res["vpc-a--hub-1"]["vpc-a"]
+		require.Len(t, paths, 2)
+		require.Equal(t, []string{"hub-2", "hub-1"}, paths[0].path)
+		require.Equal(t, []string{"vpc-c", "hub-2", "hub-1"}, paths[1].path)
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		_, err := computeTransit([]transitEdge{
+			edge("vpc-a", "10.1.0.0/24", false, "hub-1", "10.0.1.0/24", true),
+			edge("hub-1", "10.0.1.0/24", false, "hub-2", "10.0.2.0/24", true),
+			edge("hub-2", "10.0.2.0/24", false, "vpc-a", "10.1.0.0/24", false),
+		}, "")
+		require.ErrorContains(t, err, "transit loop")
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		_, err := computeTransit([]transitEdge{
+			edge("hub", "10.0.0.0/24", true, "spoke-1", "10.1.0.0/24", false),
+			edge("hub", "10.0.0.0/24", false, "spoke-2", "192.168.0.0/24", false),
+			edge("spoke-1", "10.1.0.0/24", false, "spoke-3", "192.168.0.0/24", false),
+		}, "")
+		require.ErrorContains(t, err, "ambiguous routes")
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		edges := []transitEdge{
+			edge("vpc-a", "10.1.0.0/24", false, "hub-1", "10.0.1.0/24", true),
+			edge("hub-1", "10.0.1.0/24", false, "hub-2", "10.0.2.0/24", true),
+			edge("hub-2", "10.0.2.0/24", false, "vpc-a", "10.1.0.0/24", false),
+			edge("hub-3", "10.0.3.0/24", true, "vpc-d", "10.4.0.0/24", false),
+		}
+
+		_, err := computeTransit(edges, "hub-1--hub-2")
+		require.ErrorContains(t, err, "transit loop")
+
+		res, err := computeTransit(append(edges, edge("hub-3", "10.0.3.0/24", false, "vpc-e", "10.5.0.0/24", false)), "hub-3--vpc-d")
+		require.NoError(t, err)
+		require.Len(t, res["hub-3--vpc-d"]["vpc-d"], 1)
+		require.Equal(t, []string{"vpc-e", "hub-3"}, res["hub-3--vpc-d"]["vpc-d"][0].path)
+	})
+}
+
+func TestTransitEdges(t *testing.T) {
+	remote := testVPC("vpc-b", "10.11.0.0/24")
+	remote.Namespace = "tenant-b"
+
+	kube := kubetest.NewReader(
+		testVPC("vpc-1", "10.1.0.0/24"),
+		testVPC("vpc-2", "10.2.0.0/24"),
+		testVPC("vpc-3", "10.3.0.0/24"),
+		remote,
+		testPeering("hub", map[string]*gwapi.PeeringEntry{
+			"vpc-1": {Transit: true},
+			"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+		}),
+		&gwapi.PeeringMesh{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "mesh-1"},
+			Spec: gwapi.PeeringMeshSpec{Peering: map[string]*gwapi.PeeringEntry{
+				"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+				"vpc-3": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+			}},
+		},
+		&gwapi.PeeringRequest{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-b", Name: "to-a"},
+			Spec: gwapi.PeeringRequestSpec{
+				VPC:             "vpc-b",
+				Expose:          []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}},
+				RemoteNamespace: "default",
+				RemoteVPC:       "vpc-1",
+			},
+		},
+		&gwapi.PeeringAcceptance{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "from-b"},
+			Spec: gwapi.PeeringAcceptanceSpec{
+				RequestNamespace: "tenant-b",
+				Request:          "to-a",
+				Expose:           []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}},
+			},
+		},
+	)
+
+	edges, err := transitEdges(context.Background(), kube, "default", nil)
+	require.NoError(t, err)
+	require.Len(t, edges, 3)
+
+	res, err := computeTransit(edges, "hub")
+	require.NoError(t, err)
+
+	paths := res["hub"]["vpc-2"]
+	require.Len(t, paths, 2)
+	require.Equal(t, []string{"vpc-3", "vpc-1"}, paths[0].path)
+	require.Equal(t, []string{"10.3.0.0/24"}, paths[0].routes.Strings())
+	require.Equal(t, []string{"vpc-b", "vpc-1"}, paths[1].path)
+	require.Equal(t, []string{"10.11.0.0/24"}, paths[1].routes.Strings())
+
+	spec := withTransitRoutes(&gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{
+		"vpc-1": {Transit: true},
+		"vpc-2": {Expose: []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}}}}},
+	}}, res["hub"])
+	require.Equal(t, []gwapi.PeeringEntryExpose{{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.3.0.0/24"}, {CIDR: "10.11.0.0/24"}}}}, spec.Peering["vpc-1"].Expose)
+	require.Len(t, spec.Peering["vpc-2"].Expose, 1)
+}
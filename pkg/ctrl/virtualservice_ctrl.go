@@ -0,0 +1,207 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=virtualservices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=virtualservices/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gwint.githedgehog.com,resources=gatewayagents,verbs=get;list;watch
+
+type VirtualServiceReconciler struct {
+	kclient.Client
+}
+
+func SetupVirtualServiceReconcilerWith(mgr kctrl.Manager) error {
+	r := &VirtualServiceReconciler{
+		Client: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("VirtualService").
+		For(&gwapi.VirtualService{}).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueueServicesForVPC)).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueServicesForPeering)).
+		Watches(&gwintapi.GatewayAgent{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllServices)).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+func (r *VirtualServiceReconciler) enqueueServices(ctx context.Context, opts ...kclient.ListOption) []reconcile.Request {
+	res := []reconcile.Request{}
+
+	services := &gwapi.VirtualServiceList{}
+	if err := r.List(ctx, services, opts...); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing virtual services to reconcile")
+
+		return nil
+	}
+
+	for _, vs := range services.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: vs.Namespace,
+			Name:      vs.Name,
+		}})
+	}
+
+	return res
+}
+
+func (r *VirtualServiceReconciler) enqueueServicesForVPC(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	return r.enqueueServices(ctx, kclient.InNamespace(obj.GetNamespace()), kclient.MatchingLabels{
+		gwapi.ListLabelVPC(obj.GetName()): gwapi.ListLabelValue,
+	})
+}
+
+func (r *VirtualServiceReconciler) enqueueServicesForPeering(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	peering, ok := obj.(*gwapi.Peering)
+	if !ok {
+		return nil
+	}
+
+	res := []reconcile.Request{}
+	for vpcName := range peering.Spec.Peering {
+		res = append(res, r.enqueueServices(ctx, kclient.InNamespace(peering.Namespace), kclient.MatchingLabels{
+			gwapi.ListLabelVPC(vpcName): gwapi.ListLabelValue,
+		})...)
+	}
+
+	return res
+}
+
+func (r *VirtualServiceReconciler) enqueueAllServices(ctx context.Context, _ kclient.Object) []reconcile.Request {
+	return r.enqueueServices(ctx)
+}
+
+func (r *VirtualServiceReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	vs := &gwapi.VirtualService{}
+	if err := r.Get(ctx, req.NamespacedName, vs); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("getting virtual service: %w", err)
+	}
+
+	if vs.DeletionTimestamp != nil {
+		return kctrl.Result{}, nil
+	}
+
+	status := gwapi.VirtualServiceStatus{}
+
+	subnets, _, err := gwapi.GetPeeredSubnets(ctx, r, vs.Namespace, vs.Spec.VPC)
+	if err != nil && !kapierrors.IsNotFound(err) {
+		return kctrl.Result{}, fmt.Errorf("getting vpc %s: %w", vs.Spec.VPC, err)
+	}
+	if err != nil {
+		status.Error = fmt.Sprintf("vpc %s not found", vs.Spec.VPC)
+	} else {
+		peerings := &gwapi.PeeringList{}
+		if err := r.List(ctx, peerings, kclient.InNamespace(vs.Namespace), kclient.MatchingLabels{
+			gwapi.ListLabelVPC(vs.Spec.VPC): gwapi.ListLabelValue,
+		}); err != nil {
+			return kctrl.Result{}, fmt.Errorf("listing peerings for vpc %s: %w", vs.Spec.VPC, err)
+		}
+
+		exposing, err := vs.ExposingPeerings(peerings.Items, subnets)
+		switch {
+		case err != nil:
+			status.Error = err.Error()
+		case len(exposing) == 0:
+			status.Error = fmt.Sprintf("vip %s isn't exposed by any peering of vpc %s", vs.Spec.VIP, vs.Spec.VPC)
+		default:
+			status.Peerings = exposing
+		}
+	}
+
+	// the gateway controller doesn't pass the service to the gateways
+	if err := vs.Spec.DataplaneUnsupported(); err != nil && status.Error == "" {
+		status.Error = "not configured on the gateways: " + err.Error()
+	}
+
+	agents := &gwintapi.GatewayAgentList{}
+	if err := r.List(ctx, agents); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing gateway agents: %w", err)
+	}
+	status.Backends, status.HealthyBackends = virtualServiceBackends(vs, agents.Items)
+
+	if equality.Semantic.DeepEqual(vs.Status, status) {
+		return kctrl.Result{}, nil
+	}
+
+	l.Info("Updating VirtualService status", "name", req.Name, "namespace", req.Namespace, "healthy", status.HealthyBackends, "error", status.Error)
+
+	vs.Status = status
+	if err := r.Status().Update(ctx, vs); err != nil {
+		return kctrl.Result{}, fmt.Errorf("updating virtual service status: %w", err)
+	}
+
+	return kctrl.Result{}, nil
+}
+
+// virtualServiceBackends aggregates the health of the backends reported by the gateway agents, the worst health
+// reported by any of the gateways wins as the flows could land on any of them, while the gateways that don't know the
+// health yet don't override the ones that do
+func virtualServiceBackends(vs *gwapi.VirtualService, agents []gwintapi.GatewayAgent) ([]gwapi.VirtualServiceBackendStatus, int) {
+	rank := map[gwapi.VirtualServiceBackendHealth]int{
+		gwapi.VirtualServiceBackendUnknown:   0,
+		gwapi.VirtualServiceBackendHealthy:   1,
+		gwapi.VirtualServiceBackendUnhealthy: 2,
+	}
+
+	res := []gwapi.VirtualServiceBackendStatus{}
+	healthy := 0
+	for _, backend := range vs.Spec.Backends {
+		addr, err := netip.ParseAddr(backend.IP)
+		if err != nil {
+			continue
+		}
+		key := netip.AddrPortFrom(addr, backend.Port).String()
+
+		health := gwapi.VirtualServiceBackendHealth("")
+		for _, ag := range agents {
+			for _, reported := range ag.Status.VirtualServices[agentName(ag.Namespace, vs.Namespace, vs.Name)].Backends {
+				if reported.Backend != key {
+					continue
+				}
+				if health == "" || rank[reported.Health] > rank[health] {
+					health = reported.Health
+				}
+			}
+		}
+		if health == "" {
+			health = gwapi.VirtualServiceBackendUnknown
+		}
+		if health == gwapi.VirtualServiceBackendHealthy {
+			healthy++
+		}
+
+		res = append(res, gwapi.VirtualServiceBackendStatus{Backend: key, Health: health})
+	}
+
+	return res, healthy
+}
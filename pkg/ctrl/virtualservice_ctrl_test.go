@@ -0,0 +1,76 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVirtualServiceBackends(t *testing.T) {
+	vs := &gwapi.VirtualService{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-1", Name: "web"},
+		Spec: gwapi.VirtualServiceSpec{
+			Backends: []gwapi.VirtualServiceBackend{{IP: "10.1.1.10", Port: 8443}, {IP: "10.1.1.11", Port: 8443}, {IP: "10.1.1.12", Port: 8443}},
+		},
+	}
+
+	agent := func(name string, health ...gwapi.VirtualServiceBackendHealth) gwintapi.GatewayAgent {
+		status := gwintapi.VirtualServiceAgentStatus{}
+		for idx, h := range health {
+			status.Backends = append(status.Backends, gwapi.VirtualServiceBackendStatus{Backend: vs.Spec.Backends[idx].IP + ":8443", Health: h})
+		}
+
+		return gwintapi.GatewayAgent{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: name},
+			Status:     gwintapi.GatewayAgentStatus{VirtualServices: map[string]gwintapi.VirtualServiceAgentStatus{"tenant-1..web": status}},
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		agents   []gwintapi.GatewayAgent
+		expected []gwapi.VirtualServiceBackendHealth
+		healthy  int
+	}{
+		{
+			name: "worst-wins",
+			agents: []gwintapi.GatewayAgent{
+				agent("gw-1", gwapi.VirtualServiceBackendHealthy, gwapi.VirtualServiceBackendHealthy),
+				agent("gw-2", gwapi.VirtualServiceBackendHealthy, gwapi.VirtualServiceBackendUnhealthy),
+			},
+			expected: []gwapi.VirtualServiceBackendHealth{gwapi.VirtualServiceBackendHealthy, gwapi.VirtualServiceBackendUnhealthy, gwapi.VirtualServiceBackendUnknown},
+			healthy:  1,
+		},
+		{
+			name: "unknown-doesnt-override",
+			agents: []gwintapi.GatewayAgent{
+				agent("gw-1", gwapi.VirtualServiceBackendUnknown, gwapi.VirtualServiceBackendUnknown, gwapi.VirtualServiceBackendUnknown),
+				agent("gw-2", gwapi.VirtualServiceBackendHealthy, gwapi.VirtualServiceBackendUnhealthy),
+			},
+			expected: []gwapi.VirtualServiceBackendHealth{gwapi.VirtualServiceBackendHealthy, gwapi.VirtualServiceBackendUnhealthy, gwapi.VirtualServiceBackendUnknown},
+			healthy:  1,
+		},
+		{
+			name:     "not-reported",
+			agents:   []gwintapi.GatewayAgent{agent("gw-1")},
+			expected: []gwapi.VirtualServiceBackendHealth{gwapi.VirtualServiceBackendUnknown, gwapi.VirtualServiceBackendUnknown, gwapi.VirtualServiceBackendUnknown},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			backends, healthy := virtualServiceBackends(vs, tt.agents)
+			require.Equal(t, tt.healthy, healthy)
+
+			expected := []gwapi.VirtualServiceBackendStatus{}
+			for idx, health := range tt.expected {
+				expected = append(expected, gwapi.VirtualServiceBackendStatus{Backend: vs.Spec.Backends[idx].IP + ":8443", Health: health})
+			}
+			require.Equal(t, expected, backends)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-virtualservice,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=virtualservices,verbs=create;update;delete,versions=v1alpha1,name=mvirtualservice.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gateway-githedgehog-com-v1alpha1-virtualservice,mutating=false,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=virtualservices,verbs=create;update;delete,versions=v1alpha1,name=vvirtualservice.kb.io,admissionReviewVersions=v1
+
+type VirtualServiceWebhook struct {
+	kclient.Reader
+}
+
+func SetupVirtualServiceWebhookWith(mgr kctrl.Manager) error {
+	w := &VirtualServiceWebhook{
+		Reader: mgr.GetClient(),
+	}
+
+	if err := kctrl.NewWebhookManagedBy(mgr).
+		For(&gwapi.VirtualService{}).
+		WithDefaulter(FromTypedDefaulter(w)).
+		WithValidator(FromTypedValidator(w)).
+		Complete(); err != nil {
+		return fmt.Errorf("creating webhook: %w", err) //nolint:goerr113
+	}
+
+	return nil
+}
+
+func (w *VirtualServiceWebhook) Default(_ context.Context, obj *gwapi.VirtualService) error {
+	obj.Default()
+
+	return nil
+}
+
+func (w *VirtualServiceWebhook) ValidateCreate(ctx context.Context, obj *gwapi.VirtualService) (admission.Warnings, error) {
+	return nil, obj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *VirtualServiceWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.VirtualService, newObj *gwapi.VirtualService) (admission.Warnings, error) {
+	// TODO validate diff between oldObj and newObj if needed
+	_ = oldObj
+
+	return nil, newObj.Validate(ctx, w.Reader) //nolint:wrapcheck
+}
+
+func (w *VirtualServiceWebhook) ValidateDelete(_ context.Context, _ *gwapi.VirtualService) (admission.Warnings, error) {
+	return nil, nil
+}
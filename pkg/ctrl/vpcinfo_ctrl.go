@@ -11,24 +11,30 @@ import (
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	kctrl "sigs.k8s.io/controller-runtime"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=externals,verbs=get;list;watch
 
 type VPCInfoReconciler struct {
 	kclient.Client
+	apiReader kclient.Reader
 }
 
 func SetupVPCInfoReconcilerWith(mgr kctrl.Manager) error {
 	r := &VPCInfoReconciler{
-		Client: mgr.GetClient(),
+		Client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
 	}
 
 	if err := kctrl.NewControllerManagedBy(mgr).
 		Named("VPCInfo").
 		For(&gwapi.VPCInfo{}).
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(enqueueInternalIDDuplicates(r, internalIDKindVPC))).
+		Watches(&gwapi.External{}, handler.EnqueueRequestsFromMapFunc(enqueueInternalIDDuplicates(r, internalIDKindVPC))).
 		Complete(r); err != nil {
 		return fmt.Errorf("setting up controller: %w", err)
 	}
@@ -54,47 +60,5 @@ func (r *VPCInfoReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 		return kctrl.Result{}, nil
 	}
 
-	if vpc.IsReady() {
-		return kctrl.Result{}, nil
-	}
-
-	l.Info("Reconciling VPCInfo", "name", req.Name, "namespace", req.Namespace)
-
-	// TODO actually generate a unique ID in a reliable way
-	// this is a temporary solution, we should use a proper way of generating unique IDs
-	vpcs := &gwapi.VPCInfoList{}
-	if err := r.List(ctx, vpcs); err != nil {
-		return kctrl.Result{}, fmt.Errorf("listing vpc info: %w", err)
-	}
-
-	taken := map[uint32]bool{}
-	for _, v := range vpcs.Items {
-		if v.Status.InternalID == "" {
-			continue
-		}
-
-		id, err := VPCID.Decode(v.Status.InternalID)
-		if err != nil {
-			return kctrl.Result{}, fmt.Errorf("decoding vpc id: %w", err)
-		}
-		taken[id] = true
-	}
-
-	for i := range VPCID.GetMaxValue() {
-		if !taken[i] {
-			vpc.Status.InternalID, _ = VPCID.Encode(i)
-
-			break
-		}
-	}
-
-	if vpc.Status.InternalID == "" {
-		return kctrl.Result{}, fmt.Errorf("no available vpc id") //nolint:err113
-	}
-
-	if err := r.Status().Update(ctx, vpc); err != nil {
-		return kctrl.Result{}, fmt.Errorf("updating vpc status: %w", err)
-	}
-
-	return kctrl.Result{}, nil
+	return ensureInternalID(ctx, r, r.apiReader, internalIDKindVPC, vpc, &vpc.Status.InternalID)
 }
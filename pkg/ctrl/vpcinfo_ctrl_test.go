@@ -0,0 +1,106 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/pkg/kubetest"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestVPCInfoInternalID(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, gwapi.AddToScheme(scheme))
+
+	id := func(i uint32) string {
+		id, err := VPCID.Encode(i)
+		require.NoError(t, err)
+
+		return id
+	}
+	created := kmetav1.NewTime(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	vpc := func(name string, age time.Duration, internalID string) *gwapi.VPCInfo {
+		vpc := testVPC(name, "10.0.0.0/24")
+		vpc.CreationTimestamp = kmetav1.NewTime(created.Add(-age))
+		vpc.Status.InternalID = internalID
+
+		return vpc
+	}
+
+	kube := kubetest.NewClient(scheme,
+		vpc("vpc-1", 3*time.Hour, id(0)),
+		vpc("vpc-2", time.Hour, ""),
+		vpc("vpc-3", 2*time.Hour, id(0)),
+		&gwapi.External{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "ext-1", CreationTimestamp: created},
+			Status:     gwapi.ExternalStatus{InternalID: id(1)},
+		},
+	)
+	r := &VPCInfoReconciler{Client: kube, apiReader: kube}
+
+	reconcile := func(name string) (kctrl.Result, string) {
+		res, err := r.Reconcile(ctx, kctrl.Request{NamespacedName: ktypes.NamespacedName{Namespace: "default", Name: name}})
+		require.NoError(t, err)
+
+		current := &gwapi.VPCInfo{}
+		require.NoError(t, kube.Get(ctx, kclient.ObjectKey{Namespace: "default", Name: name}, current))
+
+		return res, current.Status.InternalID
+	}
+
+	// the first free ID is allocated and the reconcile is requeued to check it's unique
+	res, internalID := reconcile("vpc-2")
+	require.Equal(t, id(2), internalID)
+	require.Positive(t, res.RequeueAfter)
+	res, internalID = reconcile("vpc-2")
+	require.Equal(t, id(2), internalID)
+	require.Zero(t, res.RequeueAfter)
+
+	// the older one keeps the duplicate ID
+	res, internalID = reconcile("vpc-1")
+	require.Equal(t, id(0), internalID)
+	require.Zero(t, res.RequeueAfter)
+
+	// the newer one releases it and gets a new one
+	res, internalID = reconcile("vpc-3")
+	require.Empty(t, internalID)
+	require.Positive(t, res.RequeueAfter)
+	_, internalID = reconcile("vpc-3")
+	require.Equal(t, id(3), internalID)
+}
+
+func TestEnqueueInternalIDDuplicates(t *testing.T) {
+	vpc := func(name, internalID string) *gwapi.VPCInfo {
+		vpc := testVPC(name)
+		vpc.Status.InternalID = internalID
+
+		return vpc
+	}
+	ext := &gwapi.External{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "default", Name: "ext-1"},
+		Status:     gwapi.ExternalStatus{InternalID: "00001"},
+	}
+	kube := kubetest.NewReader(vpc("vpc-1", "00001"), vpc("vpc-2", "00001"), vpc("vpc-3", "00002"), vpc("vpc-4", ""), ext)
+
+	require.Equal(t, []reconcile.Request{
+		{NamespacedName: ktypes.NamespacedName{Namespace: "default", Name: "vpc-1"}},
+		{NamespacedName: ktypes.NamespacedName{Namespace: "default", Name: "vpc-2"}},
+	}, enqueueInternalIDDuplicates(kube, internalIDKindVPC)(t.Context(), ext))
+	require.Equal(t, []reconcile.Request{
+		{NamespacedName: ktypes.NamespacedName{Namespace: "default", Name: "vpc-2"}},
+	}, enqueueInternalIDDuplicates(kube, internalIDKindVPC)(t.Context(), vpc("vpc-1", "00001")))
+	require.Nil(t, enqueueInternalIDDuplicates(kube, internalIDKindVPC)(t.Context(), vpc("vpc-4", "")))
+}
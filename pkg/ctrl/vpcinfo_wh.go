@@ -44,14 +44,22 @@ func (w *VPCInfoWebhook) Default(_ context.Context, obj *gwapi.VPCInfo) error {
 }
 
 func (w *VPCInfoWebhook) ValidateCreate(ctx context.Context, obj *gwapi.VPCInfo) (admission.Warnings, error) {
-	return nil, obj.Validate(ctx, w.Reader) //nolint:wrapcheck
+	if err := obj.Validate(ctx, w.Reader); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return nil, gwapi.EnforceTenantQuotas(ctx, w.Reader, obj) //nolint:wrapcheck
 }
 
 func (w *VPCInfoWebhook) ValidateUpdate(ctx context.Context, oldObj *gwapi.VPCInfo, newObj *gwapi.VPCInfo) (admission.Warnings, error) {
 	// TODO validate diff between oldObj and newObj if needed
 	_ = oldObj
 
-	return nil, newObj.Validate(ctx, w.Reader) //nolint:wrapcheck
+	if err := newObj.Validate(ctx, w.Reader); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return nil, gwapi.EnforceTenantQuotas(ctx, w.Reader, newObj) //nolint:wrapcheck
 }
 
 func (w *VPCInfoWebhook) ValidateDelete(_ context.Context, _ *gwapi.VPCInfo) (admission.Warnings, error) {
@@ -0,0 +1,143 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package kubetest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var errStale = errors.New("the object has been modified, apply the changes to the latest version and try again")
+
+// Client is a kclient.Client on top of the Reader for the unit tests of the controllers, it supports creating,
+// updating (incl. the status) and deleting the objects with the resource version checks, the other methods panic
+type Client struct {
+	kclient.Client
+	*Reader
+
+	scheme  *runtime.Scheme
+	version int
+
+	// Reject is called before each create, update or delete (but not the status updates) if set and the write fails
+	// with the returned error, so the tests could simulate the webhooks
+	Reject func(obj kclient.Object) error
+}
+
+var _ kclient.Client = &Client{}
+
+func NewClient(scheme *runtime.Scheme, objs ...kclient.Object) *Client {
+	return &Client{
+		Reader: NewReader(objs...),
+		scheme: scheme,
+	}
+}
+
+func (c *Client) Scheme() *runtime.Scheme {
+	return c.scheme
+}
+
+func (c *Client) Get(ctx context.Context, key kclient.ObjectKey, obj kclient.Object, opts ...kclient.GetOption) error {
+	return c.Reader.Get(ctx, key, obj, opts...)
+}
+
+func (c *Client) List(ctx context.Context, list kclient.ObjectList, opts ...kclient.ListOption) error {
+	return c.Reader.List(ctx, list, opts...)
+}
+
+func (c *Client) Create(_ context.Context, obj kclient.Object, _ ...kclient.CreateOption) error {
+	if c.Reject != nil {
+		if err := c.Reject(obj); err != nil {
+			return err
+		}
+	}
+	if idx := c.find(obj); idx >= 0 {
+		return kapierrors.NewAlreadyExists(resource(obj), obj.GetName())
+	}
+
+	c.bump(obj)
+	c.objs = append(c.objs, obj.DeepCopyObject().(kclient.Object)) //nolint:forcetypeassert
+
+	return nil
+}
+
+func (c *Client) Update(_ context.Context, obj kclient.Object, _ ...kclient.UpdateOption) error {
+	if c.Reject != nil {
+		if err := c.Reject(obj); err != nil {
+			return err
+		}
+	}
+
+	return c.update(obj)
+}
+
+func (c *Client) Delete(_ context.Context, obj kclient.Object, _ ...kclient.DeleteOption) error {
+	if c.Reject != nil {
+		if err := c.Reject(obj); err != nil {
+			return err
+		}
+	}
+	idx := c.find(obj)
+	if idx < 0 {
+		return kapierrors.NewNotFound(resource(obj), obj.GetName())
+	}
+
+	c.objs = append(c.objs[:idx], c.objs[idx+1:]...)
+
+	return nil
+}
+
+func (c *Client) Status() kclient.SubResourceWriter {
+	return &statusWriter{client: c}
+}
+
+// update replaces the stored object if the resource version matches (or isn't set), the status is replaced as well
+func (c *Client) update(obj kclient.Object) error {
+	idx := c.find(obj)
+	if idx < 0 {
+		return kapierrors.NewNotFound(resource(obj), obj.GetName())
+	}
+	if version := obj.GetResourceVersion(); version != "" && version != c.objs[idx].GetResourceVersion() {
+		return kapierrors.NewConflict(resource(obj), obj.GetName(), errStale)
+	}
+
+	c.bump(obj)
+	c.objs[idx] = obj.DeepCopyObject().(kclient.Object) //nolint:forcetypeassert
+
+	return nil
+}
+
+func (c *Client) find(obj kclient.Object) int {
+	for idx, o := range c.objs {
+		if reflect.TypeOf(o) == reflect.TypeOf(obj) && o.GetNamespace() == obj.GetNamespace() && o.GetName() == obj.GetName() {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+func (c *Client) bump(obj kclient.Object) {
+	c.version++
+	obj.SetResourceVersion(strconv.Itoa(c.version))
+}
+
+type statusWriter struct {
+	kclient.SubResourceWriter
+	client *Client
+}
+
+func (w *statusWriter) Update(_ context.Context, obj kclient.Object, _ ...kclient.SubResourceUpdateOption) error {
+	return w.client.update(obj)
+}
+
+func resource(obj kclient.Object) schema.GroupResource {
+	return schema.GroupResource{Resource: reflect.TypeOf(obj).Elem().Name()}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kubetest provides an in-memory kube reader for the unit tests of the validation and the controllers.
+package kubetest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IndexFunc returns the values of an indexed field of the object, same as for the field indexer of the manager
+type IndexFunc func(obj kclient.Object) []string
+
+// Reader is a kclient.Reader serving the objects it's created with, it supports the namespace, label and indexed
+// field selectors
+type Reader struct {
+	objs    []kclient.Object
+	indexes map[string]IndexFunc
+}
+
+var _ kclient.Reader = &Reader{}
+
+func NewReader(objs ...kclient.Object) *Reader {
+	return &Reader{
+		objs:    objs,
+		indexes: map[string]IndexFunc{},
+	}
+}
+
+// WithIndex registers the index for the field selectors on the field
+func (r *Reader) WithIndex(field string, fn IndexFunc) *Reader {
+	r.indexes[field] = fn
+
+	return r
+}
+
+func (r *Reader) Get(_ context.Context, key kclient.ObjectKey, obj kclient.Object, _ ...kclient.GetOption) error {
+	for _, o := range r.objs {
+		if reflect.TypeOf(o) == reflect.TypeOf(obj) && o.GetNamespace() == key.Namespace && o.GetName() == key.Name {
+			reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(o.DeepCopyObject()).Elem())
+
+			return nil
+		}
+	}
+
+	return kapierrors.NewNotFound(schema.GroupResource{Resource: reflect.TypeOf(obj).Elem().Name()}, key.Name)
+}
+
+func (r *Reader) List(_ context.Context, list kclient.ObjectList, opts ...kclient.ListOption) error {
+	listOpts := (&kclient.ListOptions{}).ApplyOptions(opts)
+
+	items := reflect.ValueOf(list).Elem().FieldByName("Items")
+	res := reflect.MakeSlice(items.Type(), 0, len(r.objs))
+	for _, o := range r.objs {
+		if reflect.TypeOf(o).Elem() != items.Type().Elem() {
+			continue
+		}
+		if listOpts.Namespace != "" && o.GetNamespace() != listOpts.Namespace {
+			continue
+		}
+		if listOpts.LabelSelector != nil && !listOpts.LabelSelector.Matches(labels.Set(o.GetLabels())) {
+			continue
+		}
+		if listOpts.FieldSelector != nil {
+			matches := true
+			for _, req := range listOpts.FieldSelector.Requirements() {
+				index, ok := r.indexes[req.Field]
+				if !ok {
+					return fmt.Errorf("field %s isn't indexed", req.Field) //nolint:goerr113
+				}
+				matches = matches && slices.Contains(index(o), req.Value)
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		res = reflect.Append(res, reflect.ValueOf(o.DeepCopyObject()).Elem())
+	}
+	items.Set(res)
+
+	return nil
+}
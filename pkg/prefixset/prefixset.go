@@ -0,0 +1,284 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+// Package prefixset implements sets of IP addresses built from prefixes with the set operations and the minimal
+// prefix enumeration needed to translate the "cidr"/"not" entries of the peerings into the routes and NAT rules.
+package prefixset
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// addrRange is an inclusive range of addresses of the same family
+type addrRange struct {
+	from netip.Addr
+	to   netip.Addr
+}
+
+// Set is an immutable set of IP addresses, IPv4 and IPv6 addresses could be mixed in the same set. Zero value is an
+// empty set ready to use.
+type Set struct {
+	// ranges are sorted, non-overlapping and non-adjacent
+	ranges []addrRange
+}
+
+// New returns a set of all addresses covered by the prefixes
+func New(prefixes ...netip.Prefix) *Set {
+	ranges := make([]addrRange, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if !prefix.IsValid() {
+			continue
+		}
+
+		prefix = prefix.Masked()
+		ranges = append(ranges, addrRange{from: prefix.Addr(), to: lastAddr(prefix)})
+	}
+
+	return &Set{ranges: normalize(ranges)}
+}
+
+// Parse returns a set of all addresses covered by the CIDRs
+func Parse(cidrs ...string) (*Set, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing prefix %s: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	return New(prefixes...), nil
+}
+
+// MustParse is like Parse but panics on error, it's intended for tests and constants
+func MustParse(cidrs ...string) *Set {
+	s, err := Parse(cidrs...)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// IsEmpty returns true if the set has no addresses
+func (s *Set) IsEmpty() bool {
+	return s == nil || len(s.ranges) == 0
+}
+
+// Size returns the number of addresses in the set
+func (s *Set) Size() *big.Int {
+	size := big.NewInt(0)
+	if s == nil {
+		return size
+	}
+
+	for _, r := range s.ranges {
+		size.Add(size, new(big.Int).Sub(addrInt(r.to), addrInt(r.from)))
+		size.Add(size, big.NewInt(1))
+	}
+
+	return size
+}
+
+// Union returns a set of addresses that are in either of the sets
+func (s *Set) Union(other *Set) *Set {
+	ranges := make([]addrRange, 0, len(s.getRanges())+len(other.getRanges()))
+	ranges = append(ranges, s.getRanges()...)
+	ranges = append(ranges, other.getRanges()...)
+
+	return &Set{ranges: normalize(ranges)}
+}
+
+// Intersect returns a set of addresses that are in both of the sets
+func (s *Set) Intersect(other *Set) *Set {
+	a, b := s.getRanges(), other.getRanges()
+	res := []addrRange{}
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		from := maxAddr(a[i].from, b[j].from)
+		to := minAddr(a[i].to, b[j].to)
+		if from.Compare(to) <= 0 && from.BitLen() == to.BitLen() {
+			res = append(res, addrRange{from: from, to: to})
+		}
+
+		if a[i].to.Compare(b[j].to) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return &Set{ranges: res}
+}
+
+// Subtract returns a set of addresses that are in the set but not in the other one
+func (s *Set) Subtract(other *Set) *Set {
+	b := other.getRanges()
+	res := []addrRange{}
+
+	j := 0
+	for _, r := range s.getRanges() {
+		for j < len(b) && b[j].to.Compare(r.from) < 0 {
+			j++
+		}
+
+		from := r.from
+		done := false
+		for k := j; k < len(b) && b[k].from.Compare(r.to) <= 0; k++ {
+			if b[k].from.Compare(from) > 0 {
+				res = append(res, addrRange{from: from, to: b[k].from.Prev()})
+			}
+
+			if b[k].to.Compare(r.to) >= 0 {
+				done = true
+
+				break
+			}
+
+			from = b[k].to.Next()
+		}
+
+		if !done {
+			res = append(res, addrRange{from: from, to: r.to})
+		}
+	}
+
+	return &Set{ranges: res}
+}
+
+// Contains returns true if the address is in the set
+func (s *Set) Contains(addr netip.Addr) bool {
+	ranges := s.getRanges()
+	idx, _ := slices.BinarySearchFunc(ranges, addr, func(r addrRange, addr netip.Addr) int {
+		return r.to.Compare(addr)
+	})
+
+	return idx < len(ranges) && ranges[idx].from.Compare(addr) <= 0
+}
+
+// ContainsPrefix returns true if all addresses of the prefix are in the set
+func (s *Set) ContainsPrefix(prefix netip.Prefix) bool {
+	return s.Covers(New(prefix))
+}
+
+// Covers returns true if all addresses of the other set are in the set
+func (s *Set) Covers(other *Set) bool {
+	return other.Subtract(s).IsEmpty()
+}
+
+// Overlaps returns true if the sets have at least one address in common
+func (s *Set) Overlaps(other *Set) bool {
+	return !s.Intersect(other).IsEmpty()
+}
+
+// Equal returns true if the sets have exactly the same addresses
+func (s *Set) Equal(other *Set) bool {
+	return slices.Equal(s.getRanges(), other.getRanges())
+}
+
+// Prefixes returns the minimal sorted list of non-overlapping prefixes covering exactly the addresses in the set
+func (s *Set) Prefixes() []netip.Prefix {
+	res := []netip.Prefix{}
+
+	for _, r := range s.getRanges() {
+		for from := r.from; from.IsValid() && from.Compare(r.to) <= 0; {
+			var prefix netip.Prefix
+			for bits := 0; bits <= from.BitLen(); bits++ {
+				prefix = netip.PrefixFrom(from, bits)
+				if prefix.Masked().Addr() == from && lastAddr(prefix).Compare(r.to) <= 0 {
+					break
+				}
+			}
+
+			res = append(res, prefix)
+			from = lastAddr(prefix).Next()
+		}
+	}
+
+	return res
+}
+
+// Strings returns the minimal list of prefixes covering the set as strings
+func (s *Set) Strings() []string {
+	prefixes := s.Prefixes()
+	res := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		res = append(res, prefix.String())
+	}
+
+	return res
+}
+
+func (s *Set) String() string {
+	return "[" + strings.Join(s.Strings(), " ") + "]"
+}
+
+func (s *Set) getRanges() []addrRange {
+	if s == nil {
+		return nil
+	}
+
+	return s.ranges
+}
+
+// normalize sorts the ranges and merges overlapping and adjacent ones
+func normalize(ranges []addrRange) []addrRange {
+	slices.SortFunc(ranges, func(a, b addrRange) int {
+		return a.from.Compare(b.from)
+	})
+
+	res := make([]addrRange, 0, len(ranges))
+	for _, r := range ranges {
+		if len(res) > 0 {
+			last := &res[len(res)-1]
+			next := last.to.Next()
+			if r.from.BitLen() == last.to.BitLen() && (r.from.Compare(last.to) <= 0 || next == r.from) {
+				last.to = maxAddr(last.to, r.to)
+
+				continue
+			}
+		}
+
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// lastAddr returns the last address covered by the (masked) prefix
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr()
+	bytes := addr.AsSlice()
+	for bit := prefix.Bits(); bit < addr.BitLen(); bit++ {
+		bytes[bit/8] |= 1 << (7 - bit%8)
+	}
+
+	last, _ := netip.AddrFromSlice(bytes)
+
+	return last
+}
+
+func addrInt(addr netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(addr.AsSlice())
+}
+
+func minAddr(a, b netip.Addr) netip.Addr {
+	if a.Compare(b) < 0 {
+		return a
+	}
+
+	return b
+}
+
+func maxAddr(a, b netip.Addr) netip.Addr {
+	if a.Compare(b) > 0 {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package prefixset
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixesNot(t *testing.T) {
+	// example from the docs/proposed-api.md
+	got := MustParse("192.168.1.0/24").Subtract(MustParse("192.168.1.7/32")).Strings()
+	require.Equal(t, []string{
+		"192.168.1.0/30",
+		"192.168.1.4/31",
+		"192.168.1.6/32",
+		"192.168.1.8/29",
+		"192.168.1.16/28",
+		"192.168.1.32/27",
+		"192.168.1.64/26",
+		"192.168.1.128/25",
+	}, got)
+}
+
+func TestSetOps(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		op   func() *Set
+		want []string
+	}{
+		{"union-merge", func() *Set { return MustParse("10.0.0.0/25").Union(MustParse("10.0.0.128/25")) }, []string{"10.0.0.0/24"}},
+		{"union-disjoint", func() *Set { return MustParse("10.0.0.0/24").Union(MustParse("10.0.2.0/24")) }, []string{"10.0.0.0/24", "10.0.2.0/24"}},
+		{"union-mixed", func() *Set { return MustParse("10.0.0.0/24").Union(MustParse("fd00::/64")) }, []string{"10.0.0.0/24", "fd00::/64"}},
+		{"intersect", func() *Set { return MustParse("10.0.0.0/16").Intersect(MustParse("10.0.5.0/24", "10.1.0.0/24")) }, []string{"10.0.5.0/24"}},
+		{"intersect-mixed", func() *Set { return MustParse("0.0.0.0/0").Intersect(MustParse("::/0")) }, []string{}},
+		{"subtract-all", func() *Set { return MustParse("10.0.0.0/24").Subtract(MustParse("10.0.0.0/8")) }, []string{}},
+		{"subtract-edges", func() *Set {
+			return MustParse("10.0.0.0/24").Subtract(MustParse("10.0.0.0/32", "10.0.0.255/32"))
+		}, []string{
+			"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/30", "10.0.0.8/29", "10.0.0.16/28", "10.0.0.32/27", "10.0.0.64/26", "10.0.0.128/26",
+			"10.0.0.192/27", "10.0.0.224/28", "10.0.0.240/29", "10.0.0.248/30", "10.0.0.252/31", "10.0.0.254/32",
+		}},
+		{"full-v4", func() *Set { return MustParse("0.0.0.0/1", "128.0.0.0/1") }, []string{"0.0.0.0/0"}},
+		{"full-v6-minus", func() *Set { return MustParse("::/0").Subtract(MustParse("::/1")) }, []string{"8000::/1"}},
+		{"unmasked", func() *Set { return MustParse("10.0.0.1/24") }, []string{"10.0.0.0/24"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.op().Strings())
+		})
+	}
+}
+
+func TestSizeContains(t *testing.T) {
+	s := MustParse("10.0.0.0/24", "10.0.2.0/31").Subtract(MustParse("10.0.0.7/32"))
+	require.Equal(t, big.NewInt(257), s.Size())
+	require.True(t, s.Contains(netip.MustParseAddr("10.0.0.6")))
+	require.False(t, s.Contains(netip.MustParseAddr("10.0.0.7")))
+	require.True(t, s.Contains(netip.MustParseAddr("10.0.2.1")))
+	require.False(t, s.Contains(netip.MustParseAddr("10.0.2.2")))
+	require.False(t, s.Contains(netip.MustParseAddr("fd00::1")))
+	require.True(t, s.ContainsPrefix(netip.MustParsePrefix("10.0.0.128/25")))
+	require.False(t, s.ContainsPrefix(netip.MustParsePrefix("10.0.0.0/25")))
+
+	full := MustParse("::/0")
+	require.Equal(t, new(big.Int).Lsh(big.NewInt(1), 128), full.Size())
+	require.True(t, (&Set{}).IsEmpty())
+	require.Equal(t, big.NewInt(0), (*Set)(nil).Size())
+}
+
+// space is a small address space (10.0.0.0/24) used to check the set operations against a bitmap implementation
+const spaceBits = 8
+
+type bitmap [1 << spaceBits]bool
+
+func randomSet(rnd *rand.Rand) (*Set, bitmap) {
+	bm := bitmap{}
+	prefixes := []netip.Prefix{}
+
+	for range rnd.IntN(5) {
+		bits := 32 - spaceBits + rnd.IntN(spaceBits+1)
+		size := 1 << (32 - bits)
+		start := rnd.IntN(1<<spaceBits) / size * size
+		prefixes = append(prefixes, netip.PrefixFrom(netip.AddrFrom4([4]byte{10, 0, 0, byte(start)}), bits))
+
+		for idx := start; idx < start+size; idx++ {
+			bm[idx] = true
+		}
+	}
+
+	return New(prefixes...), bm
+}
+
+func requireMatches(t *testing.T, s *Set, bm bitmap) {
+	t.Helper()
+
+	count := int64(0)
+	for idx, in := range bm {
+		require.Equal(t, in, s.Contains(netip.AddrFrom4([4]byte{10, 0, 0, byte(idx)})), "address 10.0.0.%d in %s", idx, s)
+		if in {
+			count++
+		}
+	}
+	require.Equal(t, big.NewInt(count), s.Size())
+
+	// prefixes should be non-overlapping, cover exactly the set and be minimal (no two could be merged)
+	prefixes := s.Prefixes()
+	require.True(t, New(prefixes...).Equal(s))
+	for i, a := range prefixes {
+		for j, b := range prefixes {
+			if i == j {
+				continue
+			}
+
+			require.False(t, a.Overlaps(b), "prefixes %s and %s overlap", a, b)
+			if a.Bits() == b.Bits() && a.Bits() > 0 {
+				parentA, _ := a.Addr().Prefix(a.Bits() - 1)
+				parentB, _ := b.Addr().Prefix(b.Bits() - 1)
+				require.NotEqual(t, parentA, parentB, "prefixes %s and %s could be merged", a, b)
+			}
+		}
+	}
+}
+
+func TestProperties(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(42, 42)) //nolint:gosec
+
+	for range 2000 {
+		a, aBM := randomSet(rnd)
+		b, bBM := randomSet(rnd)
+
+		requireMatches(t, a, aBM)
+
+		union, inter, diff := bitmap{}, bitmap{}, bitmap{}
+		covers := true
+		for idx := range aBM {
+			union[idx] = aBM[idx] || bBM[idx]
+			inter[idx] = aBM[idx] && bBM[idx]
+			diff[idx] = aBM[idx] && !bBM[idx]
+			if bBM[idx] && !aBM[idx] {
+				covers = false
+			}
+		}
+
+		requireMatches(t, a.Union(b), union)
+		requireMatches(t, a.Intersect(b), inter)
+		requireMatches(t, a.Subtract(b), diff)
+
+		require.Equal(t, covers, a.Covers(b))
+		require.Equal(t, !a.Intersect(b).IsEmpty(), a.Overlaps(b))
+		require.True(t, a.Union(b).Equal(b.Union(a)))
+		require.True(t, a.Intersect(b).Equal(b.Intersect(a)))
+		require.True(t, a.Subtract(b).Union(a.Intersect(b)).Equal(a))
+		require.True(t, a.Union(b).Covers(a))
+		require.True(t, a.Covers(a.Intersect(b)))
+	}
+}